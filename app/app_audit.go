@@ -0,0 +1,73 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the “Software”), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package app
+
+import (
+	"errors"
+	"os/exec"
+
+	"github.com/mkloubert/autark/utils"
+)
+
+// AuditEntry describes an external command, which
+// has been run to change the system
+type AuditEntry struct {
+	// Command is the redacted command line
+	Command string `json:"command"`
+	// ExitCode is the exit code, which is -1, if
+	// the command could not be started
+	ExitCode int `json:"exit_code"`
+	// Error is the error message, if the command failed
+	Error string `json:"error,omitempty"`
+}
+
+// AuditLog returns the commands, which have been
+// recorded with RecordCommand, in order
+func (a *AppContext) AuditLog() []*AuditEntry {
+	a.auditMutex.Lock()
+	defer a.auditMutex.Unlock()
+
+	return append([]*AuditEntry{}, a.auditLog...)
+}
+
+// RecordCommand adds an external command, which has been run
+// to change the system, with its result to the audit log
+func (a *AppContext) RecordCommand(name string, args []string, err error) {
+	entry := &AuditEntry{
+		Command: utils.RedactCommandLine(name, args...),
+	}
+
+	if err != nil {
+		entry.ExitCode = -1
+		entry.Error = err.Error()
+
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			entry.ExitCode = exitErr.ExitCode()
+		}
+	}
+
+	a.auditMutex.Lock()
+	defer a.auditMutex.Unlock()
+
+	a.auditLog = append(a.auditLog, entry)
+}
@@ -0,0 +1,74 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the “Software”), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package app
+
+import (
+	"errors"
+	"os/exec"
+	"runtime"
+	"testing"
+
+	"github.com/mkloubert/autark/utils"
+)
+
+func TestRecordCommand(t *testing.T) {
+	a := newTestAppContext()
+
+	a.RecordCommand("apt-get", []string{"install", "-y", "git"}, nil)
+	a.RecordCommand("docker", []string{"login", "--password", "s3cr3t"}, errors.New("executable file not found"))
+
+	log := a.AuditLog()
+	if len(log) != 2 {
+		t.Fatalf("AuditLog() contains %d entries, want 2", len(log))
+	}
+
+	want := []AuditEntry{
+		{Command: "apt-get install -y git", ExitCode: 0},
+		{Command: "docker login --password " + utils.RedactedValue, ExitCode: -1, Error: "executable file not found"},
+	}
+	for i, entry := range log {
+		if *entry != want[i] {
+			t.Errorf("AuditLog()[%d] = %+v, want %+v", i, *entry, want[i])
+		}
+	}
+
+	// the returned log is a copy
+	log[0] = nil
+	if a.AuditLog()[0] == nil {
+		t.Error("AuditLog() returned the internal log")
+	}
+}
+
+func TestRecordCommandExitCode(t *testing.T) {
+	if runtime.GOOS == "windows" || !utils.CommandExists("sh") {
+		t.Skip("sh is not available")
+	}
+
+	a := newTestAppContext()
+
+	err := exec.Command("sh", "-c", "exit 3").Run()
+	a.RecordCommand("sh", []string{"-c", "exit 3"}, err)
+
+	if got := a.AuditLog()[0].ExitCode; got != 3 {
+		t.Errorf("ExitCode = %d, want 3", got)
+	}
+}
@@ -21,15 +21,41 @@
 
 package app
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/mkloubert/autark/utils"
+)
 
 // AppConfig stores application configuration
 type AppConfig struct {
+	// ASCII indicates if output should only contain ASCII characters
+	ASCII bool
+	// ConfigFile stores the path of the config file to use
+	ConfigFile string
 	// EOL stores the End-Of-Line string to use
 	EOL string
+	// File stores the loaded config file, if available
+	File *ConfigFile
+	// NoColor indicates if output should never be colored
+	NoColor bool
+	// Offline indicates that no network connections
+	// should be made, which are not required
+	Offline bool
+	// Symbols indicates if statuses should be written with symbols,
+	// like "✔ OK", instead of plain markers, like "[OK]"
+	Symbols bool
 	// Verbose indicates if additional output should be
 	// written
 	Verbose bool
+	// Yes indicates if all prompts should be answered with yes, which
+	// is the default inside CI systems, where prompts are only answered
+	// with their defaults, unless it is set explicitly
+	Yes bool
+	// WorkDir is the absolute path of the directory, relative to
+	// which commands run and paths are resolved, or empty for
+	// the current directory
+	WorkDir string
 }
 
 // NewAppConfig creates a new instance of AppConfig
@@ -37,6 +63,7 @@ func NewAppConfig() (*AppConfig, error) {
 	newConfig := &AppConfig{
 		EOL:     fmt.Sprintln(),
 		Verbose: false,
+		Yes:     utils.IsCI(),
 	}
 
 	return newConfig, nil
@@ -0,0 +1,357 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the “Software”), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package app
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultConfigFileName is the name of the config file, which is
+// loaded from the current directory if no --config flag is set
+const DefaultConfigFileName = "autark.yml"
+
+var (
+	yamlErrorLineRegex       = regexp.MustCompile(`^line (\d+): (.*)$`)
+	yamlUnknownFieldRegex    = regexp.MustCompile(`^field (\S+) not found in type \S+$`)
+	yamlCannotUnmarshalRegex = regexp.MustCompile("^cannot unmarshal !!(\\w+) (?:`.*` )?into (\\S+)$")
+)
+
+// ConfigFile stores the settings of an autark.yml file
+//
+// The YAML keys of the sections are equal to the names
+// of the flags of the command with the same name
+type ConfigFile struct {
+	// Verbose indicates if additional output should be written
+	Verbose *bool `yaml:"verbose"`
+	// Doctor stores the settings for the doctor command
+	Doctor *DoctorConfigFile `yaml:"doctor"`
+	// Setup stores the settings for the setup command
+	Setup *SetupConfigFile `yaml:"setup"`
+}
+
+// DoctorConfigFile stores the settings of the doctor section
+// of an autark.yml file
+type DoctorConfigFile struct {
+	DockerVersion   *string  `yaml:"docker-version"`
+	PkgArgs         []string `yaml:"pkg-arg"`
+	Repair          *bool    `yaml:"repair"`
+	SkipDaemonStart *bool    `yaml:"skip-daemon-start"`
+	Skip            []string `yaml:"skip"`
+}
+
+// SetupConfigFile stores the settings of the setup section
+// of an autark.yml file
+type SetupConfigFile struct {
+	NoFirewall   *bool `yaml:"no-firewall"`
+	NoSSH        *bool `yaml:"no-ssh"`
+	RegistryPort *int  `yaml:"registry-port"`
+}
+
+// ConfigFileError describes a single validation error
+// of a config file
+type ConfigFileError struct {
+	// Line is the line inside the file
+	Line int
+	// Path is the path of the field, like "setup.registry-port"
+	Path string
+	// Reason is the description of the error
+	Reason string
+}
+
+func (e *ConfigFileError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("line %d: %s", e.Line, e.Reason)
+	}
+
+	return fmt.Sprintf("%s (line %d): %s", e.Path, e.Line, e.Reason)
+}
+
+// ConfigFileSections returns the names of the sections of a config
+// file, which are equal to the names of their commands
+func ConfigFileSections() []string {
+	sections := make([]string, 0)
+
+	t := reflect.TypeOf(ConfigFile{})
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Type.Kind() == reflect.Pointer && field.Type.Elem().Kind() == reflect.Struct {
+			sections = append(sections, getYAMLKey(field))
+		}
+	}
+
+	return sections
+}
+
+// LoadConfigFile loads and validates a config file from a specific path
+func LoadConfigFile(path string) (*ConfigFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	configFile, err := ParseConfigFile(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config file %s:\n%w", path, err)
+	}
+
+	return configFile, nil
+}
+
+// ParseConfigFile parses and validates the YAML data of a config file
+//
+// Unknown keys and values of the wrong type are rejected with
+// errors of type *ConfigFileError
+func ParseConfigFile(data []byte) (*ConfigFile, error) {
+	configFile := &ConfigFile{}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	err := decoder.Decode(configFile)
+	if err == nil || errors.Is(err, io.EOF) {
+		return configFile, nil
+	}
+
+	var typeErr *yaml.TypeError
+	if !errors.As(err, &typeErr) {
+		return nil, err
+	}
+
+	paths := map[int]string{}
+	collectYAMLPaths(&root, "", paths)
+
+	errs := make([]error, 0, len(typeErr.Errors))
+	for _, msg := range typeErr.Errors {
+		errs = append(errs, newConfigFileError(msg, paths))
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+// ApplyTo uses the values of this config file for all flags
+// of a command, which have not been set explicitly, and returns
+// the names of the flags, which have been set
+func (c *ConfigFile) ApplyTo(cmd *cobra.Command) ([]string, error) {
+	values := getConfigFileValues(c)
+
+	if section := c.section(cmd.Name()); section != nil {
+		for name, value := range getConfigFileValues(section) {
+			values[name] = value
+		}
+	}
+
+	flags := cmd.Flags()
+	applied := make([]string, 0, len(values))
+
+	for name, value := range values {
+		flag := flags.Lookup(name)
+		if flag == nil || flag.Changed {
+			continue
+		}
+
+		rv := reflect.ValueOf(value)
+		if rv.Kind() == reflect.Slice {
+			for i := 0; i < rv.Len(); i++ {
+				if err := flags.Set(name, fmt.Sprint(rv.Index(i).Interface())); err != nil {
+					return applied, fmt.Errorf("invalid value for %s: %w", name, err)
+				}
+			}
+			applied = append(applied, name)
+			continue
+		}
+
+		if err := flags.Set(name, fmt.Sprint(value)); err != nil {
+			return applied, fmt.Errorf("invalid value for %s: %w", name, err)
+		}
+		applied = append(applied, name)
+	}
+
+	return applied, nil
+}
+
+func (c *ConfigFile) section(name string) any {
+	v := reflect.ValueOf(c).Elem()
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := v.Field(i)
+		if getYAMLKey(t.Field(i)) != name {
+			continue
+		}
+
+		if field.Kind() == reflect.Pointer && !field.IsNil() && field.Elem().Kind() == reflect.Struct {
+			return field.Interface()
+		}
+	}
+
+	return nil
+}
+
+// collectYAMLPaths maps the lines of all keys and items of a YAML node
+// to their paths, like "setup.registry-port"
+func collectYAMLPaths(node *yaml.Node, path string, paths map[int]string) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			collectYAMLPaths(child, path, paths)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			keyNode := node.Content[i]
+			valueNode := node.Content[i+1]
+
+			childPath := keyNode.Value
+			if path != "" {
+				childPath = path + "." + keyNode.Value
+			}
+
+			if _, ok := paths[keyNode.Line]; !ok {
+				paths[keyNode.Line] = childPath
+			}
+
+			collectYAMLPaths(valueNode, childPath, paths)
+		}
+	case yaml.SequenceNode:
+		for i, child := range node.Content {
+			childPath := fmt.Sprintf("%s[%d]", path, i)
+
+			if _, ok := paths[child.Line]; !ok {
+				paths[child.Line] = childPath
+			}
+
+			collectYAMLPaths(child, childPath, paths)
+		}
+	}
+}
+
+// describeYAMLTag returns a readable name of a YAML tag
+// like "str" or "int"
+func describeYAMLTag(tag string) string {
+	switch tag {
+	case "str":
+		return "string"
+	case "int":
+		return "integer"
+	case "float":
+		return "number"
+	case "bool":
+		return "boolean"
+	case "seq":
+		return "list"
+	case "map":
+		return "mapping"
+	case "null":
+		return "null"
+	default:
+		return tag
+	}
+}
+
+// describeGoType returns a readable name of a Go type name
+// from a YAML error message
+func describeGoType(typeName string) string {
+	typeName = strings.TrimPrefix(typeName, "*")
+
+	switch {
+	case typeName == "int" || strings.HasPrefix(typeName, "int") || strings.HasPrefix(typeName, "uint"):
+		return "integer"
+	case strings.HasPrefix(typeName, "float"):
+		return "number"
+	case typeName == "bool":
+		return "boolean"
+	case typeName == "string":
+		return "string"
+	case strings.HasPrefix(typeName, "[]"):
+		return "list"
+	case strings.HasPrefix(typeName, "map["), strings.Contains(typeName, "."):
+		return "mapping"
+	default:
+		return typeName
+	}
+}
+
+func getConfigFileValues(section any) map[string]any {
+	values := map[string]any{}
+
+	v := reflect.ValueOf(section).Elem()
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := v.Field(i)
+
+		switch field.Kind() {
+		case reflect.Pointer:
+			if field.IsNil() || field.Elem().Kind() == reflect.Struct {
+				continue // not set or a section
+			}
+			values[getYAMLKey(t.Field(i))] = field.Elem().Interface()
+		case reflect.Slice:
+			if field.Len() == 0 {
+				continue
+			}
+			values[getYAMLKey(t.Field(i))] = field.Interface()
+		}
+	}
+
+	return values
+}
+
+func getYAMLKey(field reflect.StructField) string {
+	key, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	return key
+}
+
+func newConfigFileError(msg string, paths map[int]string) *ConfigFileError {
+	result := &ConfigFileError{
+		Reason: msg,
+	}
+
+	if match := yamlErrorLineRegex.FindStringSubmatch(msg); match != nil {
+		result.Line, _ = strconv.Atoi(match[1])
+		result.Path = paths[result.Line]
+		result.Reason = match[2]
+	}
+
+	if match := yamlUnknownFieldRegex.FindStringSubmatch(result.Reason); match != nil {
+		result.Reason = fmt.Sprintf("unknown key %q", match[1])
+	} else if match := yamlCannotUnmarshalRegex.FindStringSubmatch(result.Reason); match != nil {
+		result.Reason = fmt.Sprintf("expected %s, got %s", describeGoType(match[2]), describeYAMLTag(match[1]))
+	}
+
+	return result
+}
@@ -0,0 +1,105 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the “Software”), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package app
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseConfigFile(t *testing.T) {
+	data := []byte("verbose: true\nsetup:\n  registry-port: 5001\n  no-ssh: true\n")
+
+	configFile, err := ParseConfigFile(data)
+	if err != nil {
+		t.Fatalf("ParseConfigFile() error = %v", err)
+	}
+
+	if configFile.Verbose == nil || !*configFile.Verbose {
+		t.Errorf("Verbose = %v, want true", configFile.Verbose)
+	}
+	if configFile.Setup == nil || configFile.Setup.RegistryPort == nil || *configFile.Setup.RegistryPort != 5001 {
+		t.Errorf("Setup.RegistryPort is not 5001")
+	}
+}
+
+func TestParseConfigFileErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       string
+		wantLine   int
+		wantPath   string
+		wantReason string
+	}{
+		{
+			name:       "unknown key",
+			data:       "verbose: true\nsetup:\n  registry-prot: 5001\n",
+			wantLine:   3,
+			wantPath:   "setup.registry-prot",
+			wantReason: `unknown key "registry-prot"`,
+		},
+		{
+			name:       "wrong type",
+			data:       "setup:\n  no-ssh: true\n  registry-port: abc\n",
+			wantLine:   3,
+			wantPath:   "setup.registry-port",
+			wantReason: "expected integer, got string",
+		},
+		{
+			name:       "list instead of boolean",
+			data:       "doctor:\n  repair: [yes]\n",
+			wantLine:   2,
+			wantPath:   "doctor.repair",
+			wantReason: "expected boolean, got list",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseConfigFile([]byte(tt.data))
+			if err == nil {
+				t.Fatal("ParseConfigFile() error = nil, want an error")
+			}
+
+			var configErr *ConfigFileError
+			if !errors.As(err, &configErr) {
+				t.Fatalf("ParseConfigFile() error = %v, want a *ConfigFileError", err)
+			}
+
+			if configErr.Line != tt.wantLine {
+				t.Errorf("Line = %d, want %d", configErr.Line, tt.wantLine)
+			}
+			if configErr.Path != tt.wantPath {
+				t.Errorf("Path = %q, want %q", configErr.Path, tt.wantPath)
+			}
+			if configErr.Reason != tt.wantReason {
+				t.Errorf("Reason = %q, want %q", configErr.Reason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestParseConfigFileSyntaxError(t *testing.T) {
+	if _, err := ParseConfigFile([]byte("setup: [\n")); err == nil {
+		t.Error("ParseConfigFile() error = nil, want a syntax error")
+	}
+}
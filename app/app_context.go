@@ -26,8 +26,11 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"os/exec"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/mkloubert/autark/utils"
 	"github.com/spf13/cobra"
@@ -35,13 +38,16 @@ import (
 
 // AppContext handles the current application context
 type AppContext struct {
-	config   *AppConfig
-	logger   *log.Logger
-	platform *utils.PlatformInfo
-	stderr   *os.File
-	stdin    *os.File
-	stdout   *os.File
-	rootCmd  *cobra.Command
+	auditLog   []*AuditEntry
+	auditMutex sync.Mutex
+	config     *AppConfig
+	fileFlags  []string
+	logger     *log.Logger
+	platform   *utils.PlatformInfo
+	stderr     *os.File
+	stdin      *os.File
+	stdout     *os.File
+	rootCmd    *cobra.Command
 }
 
 // NewAppContext creates a new instance of AppContext and returns
@@ -58,13 +64,28 @@ func NewAppContext() (*AppContext, error) {
 		Use:   "autark",
 		Short: "Installs server software with Docker Compose",
 		Long:  `A platform independent Command Line Tool that installs a server software stack with ease using Docker Compose.`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := a.loadWorkDir(); err != nil {
+				cmd.SilenceUsage = true
+				return err
+			}
+
+			return a.loadConfigFile(cmd)
+		},
 		Run: func(cmd *cobra.Command, args []string) {
 			cmd.Help()
 		},
 	}
 
 	flags := rootCmd.PersistentFlags()
+	flags.BoolVarP(&config.ASCII, "ascii", "", false, "only write ASCII characters, like [OK] instead of symbols")
+	flags.StringVarP(&config.ConfigFile, "config", "", "", fmt.Sprintf("path to the config file (default: %s, if it exists)", DefaultConfigFileName))
+	flags.BoolVarP(&config.NoColor, "no-color", "", false, "do not color output (also set by the NO_COLOR environment variable)")
+	flags.BoolVarP(&config.Offline, "offline", "", false, "skip checks, which require network access")
+	flags.BoolVarP(&config.Symbols, "symbols", "", false, "write statuses with symbols, like ✔ OK instead of [OK], if standard output is a terminal")
 	flags.BoolVarP(&config.Verbose, "verbose", "", false, "verbose output")
+	flags.StringVarP(&config.WorkDir, "work-dir", "C", "", "run as if autark was started in this directory")
+	flags.BoolVarP(&config.Yes, "yes", "y", config.Yes, "answer all prompts with yes; inside CI systems, prompts are answered with their defaults (disable with --yes=false)")
 
 	a.config = config
 	a.platform = utils.DetectPlatform()
@@ -83,6 +104,27 @@ func NewAppContext() (*AppContext, error) {
 	return a, nil
 }
 
+// ASCIIOnly checks if output of this app should only contain
+// ASCII characters, which is the default and only not the case
+// with --symbols, if --ascii is not set and standard output is a terminal
+func (a *AppContext) ASCIIOnly() bool {
+	if a.Config().ASCII || !a.Config().Symbols {
+		return true
+	}
+
+	return !utils.IsTerminal(a.Stdout())
+}
+
+// ColorsEnabled checks if output of this app can be colored, which
+// requires a terminal and neither --no-color nor NO_COLOR to be set
+func (a *AppContext) ColorsEnabled() bool {
+	if a.Config().NoColor || os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+
+	return utils.IsTerminal(a.Stdout())
+}
+
 // Config returns the current configuration
 // of this app
 func (a *AppContext) Config() *AppConfig {
@@ -103,6 +145,12 @@ func (a *AppContext) E(format string, args ...any) {
 	a.logWithPrefix("[ERROR] ", format, args...)
 }
 
+// FlagsFromConfigFile returns the names of the flags of the
+// current command, which have been set by the config file
+func (a *AppContext) FlagsFromConfigFile() []string {
+	return a.fileFlags
+}
+
 // I logs an information message via the logger of this app
 func (a *AppContext) I(format string, args ...any) {
 	a.logWithPrefix("[INFO] ", format, args...)
@@ -113,6 +161,89 @@ func (a *AppContext) L() *log.Logger {
 	return a.logger
 }
 
+// isInteractive checks if standard input of this app
+// is a terminal, which can answer prompts
+func (a *AppContext) isInteractive() bool {
+	return utils.IsTerminal(a.Stdin())
+}
+
+// isYesExplicit checks if --yes has been set on the command line
+// or by the config file and not only by the default of CI systems
+func (a *AppContext) isYesExplicit() bool {
+	if a.rootCmd == nil {
+		return false
+	}
+
+	flag := a.rootCmd.PersistentFlags().Lookup("yes")
+	return flag != nil && flag.Changed
+}
+
+func (a *AppContext) loadConfigFile(cmd *cobra.Command) error {
+	config := a.Config()
+
+	configFilePath, err := utils.ExpandPath(config.ConfigFile)
+	if err != nil {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("invalid --config: %w", err)
+	}
+
+	if configFilePath == "" {
+		defaultConfigFilePath := a.ResolvePath(DefaultConfigFileName)
+		if _, err := os.Stat(defaultConfigFilePath); err != nil {
+			return nil // no config file available
+		}
+
+		configFilePath = defaultConfigFilePath
+	} else {
+		configFilePath = a.ResolvePath(configFilePath)
+	}
+
+	configFile, err := LoadConfigFile(configFilePath)
+	if err != nil {
+		cmd.SilenceUsage = true // usage does not help with invalid files
+		return err
+	}
+
+	a.D("Using config file: %s", configFilePath)
+
+	config.File = configFile
+
+	a.fileFlags, err = configFile.ApplyTo(cmd)
+	return err
+}
+
+// loadWorkDir validates the directory of --work-dir and
+// stores its absolute path
+func (a *AppContext) loadWorkDir() error {
+	config := a.Config()
+	if config.WorkDir == "" {
+		return nil
+	}
+
+	workDir, err := utils.ExpandPath(config.WorkDir)
+	if err != nil {
+		return fmt.Errorf("invalid --work-dir: %w", err)
+	}
+
+	workDir, err = filepath.Abs(workDir)
+	if err != nil {
+		return fmt.Errorf("invalid --work-dir: %w", err)
+	}
+
+	info, err := os.Stat(workDir)
+	if err != nil {
+		return fmt.Errorf("invalid --work-dir: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("invalid --work-dir: %s is not a directory", workDir)
+	}
+
+	a.D("Using work directory: %s", workDir)
+
+	config.WorkDir = workDir
+	return nil
+}
+
 func (a *AppContext) logWithPrefix(prefix string, format string, args ...any) {
 	l := a.L()
 	if l == nil {
@@ -138,63 +269,128 @@ func (a *AppContext) Platform() *utils.PlatformInfo {
 	return a.platform
 }
 
+// ConfirmInteractive asks the user a yes/no question, which has to be
+// answered explicitly on a terminal, and returns true for yes
+//
+// It is never answered by --yes or a default, so without a terminal,
+// false is returned immediately, like for privilege escalation
+func (a *AppContext) ConfirmInteractive(prompt string) bool {
+	if !a.isInteractive() {
+		a.WriteF("%s [y/N]: n (no terminal)", prompt)
+		a.WriteLn("")
+		return false
+	}
+
+	return a.readYesNo(bufio.NewReader(a.Stdin()), prompt, "[y/N]", false)
+}
+
 // PromptPort prompts the user for a port number with a suggested default
+//
+// If standard input is no terminal or has no more data, or --yes
+// is set, the default is returned immediately
 func (a *AppContext) PromptPort(prompt string, defaultPort int) int {
-	reader := bufio.NewReader(a.Stdin())
+	if !a.isInteractive() || a.Config().Yes {
+		a.WriteF("%s [%d]: %d", prompt, defaultPort, defaultPort)
+		a.WriteLn("")
+		return defaultPort
+	}
 
+	return a.readPort(bufio.NewReader(a.Stdin()), prompt, defaultPort)
+}
+
+// PromptYesNo prompts the user with a yes/no question and returns true for yes
+//
+// With an explicit --yes, true is returned immediately. If standard
+// input is no terminal or has no more data, or --yes is only set by
+// the default of CI systems, the default is returned immediately, so
+// prompts with the default no, like destructive ones, are declined
+func (a *AppContext) PromptYesNo(prompt string, defaultYes bool) bool {
+	hint := "[y/N]"
+	defaultAnswer := "n"
+	if defaultYes {
+		hint = "[Y/n]"
+		defaultAnswer = "y"
+	}
+
+	if a.Config().Yes && a.isYesExplicit() {
+		a.WriteF("%s %s: y", prompt, hint)
+		a.WriteLn("")
+		return true
+	}
+
+	if !a.isInteractive() || a.Config().Yes {
+		a.WriteF("%s %s: %s", prompt, hint, defaultAnswer)
+		a.WriteLn("")
+		return defaultYes
+	}
+
+	return a.readYesNo(bufio.NewReader(a.Stdin()), prompt, hint, defaultYes)
+}
+
+// readPort reads the answer of PromptPort from reader, until
+// it is a valid port, or returns defaultPort without more data
+func (a *AppContext) readPort(reader *bufio.Reader, prompt string, defaultPort int) int {
 	for {
 		a.WriteF("%s [%d]: ", prompt, defaultPort)
 
 		input, err := reader.ReadString('\n')
-		if err != nil {
-			return defaultPort
-		}
-
 		input = strings.TrimSpace(input)
 
 		if input == "" {
 			return defaultPort
 		}
 
-		port, err := strconv.Atoi(input)
-		if err != nil {
+		port, convErr := strconv.Atoi(input)
+		if convErr != nil {
 			a.WriteErrLn("Invalid port number. Please enter a valid number.")
-			continue
-		}
-
-		if port < 1 || port > 65535 {
+		} else if port < 1 || port > 65535 {
 			a.WriteErrLn("Port must be between 1 and 65535.")
-			continue
+		} else {
+			return port
 		}
 
-		return port
+		if err != nil {
+			// no more input to ask again
+			return defaultPort
+		}
 	}
 }
 
-// PromptYesNo prompts the user with a yes/no question and returns true for yes
-func (a *AppContext) PromptYesNo(prompt string, defaultYes bool) bool {
+// readYesNo reads the answer of PromptYesNo from reader, until
+// it is yes or no, or returns defaultYes without more data
+func (a *AppContext) readYesNo(reader *bufio.Reader, prompt string, hint string, defaultYes bool) bool {
 	for {
-		reader := bufio.NewReader(a.Stdin())
-
-		hint := "[y/N]"
-		if defaultYes {
-			hint = "[Y/n]"
-		}
-
 		a.WriteF("%s %s: ", prompt, hint)
 
 		input, err := reader.ReadString('\n')
-		if err != nil {
-			return defaultYes
-		}
 
 		switch strings.TrimSpace(strings.ToLower(input)) {
-		case "n", "no", "y", "yes":
-			return input == "y" || input == "yes"
+		case "y", "yes":
+			return true
+		case "n", "no":
+			return false
 		case "":
 			return defaultYes
 		}
+
+		if err != nil {
+			// no more input to ask again
+			return defaultYes
+		}
+	}
+}
+
+// ResolvePath resolves a relative path against the working
+// directory of this app (see --work-dir)
+//
+// Empty and absolute paths are returned unchanged
+func (a *AppContext) ResolvePath(p string) string {
+	workDir := a.Config().WorkDir
+	if p == "" || workDir == "" || filepath.IsAbs(p) {
+		return p
 	}
+
+	return filepath.Join(workDir, p)
 }
 
 // RootCommand returns the unterlying root command
@@ -208,6 +404,41 @@ func (a *AppContext) Run() error {
 	return a.rootCmd.Execute()
 }
 
+// RunCommandInDir runs a command in the working directory of this
+// app (see --work-dir) with the standard input and output of this app
+func (a *AppContext) RunCommandInDir(name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	cmd.Dir = a.Config().WorkDir
+	cmd.Stdin = a.Stdin()
+	cmd.Stdout = a.Stdout()
+	cmd.Stderr = a.Stderr()
+
+	return cmd.Run()
+}
+
+// Status returns the marker of a status, which respects --ascii
+func (a *AppContext) Status(s Status) string {
+	return FormatStatus(s, a.ASCIIOnly())
+}
+
+// StatusColors returns a function for Table.Colorize, which colors
+// the cells of a column, that contain the marker of a status
+func (a *AppContext) StatusColors(statusColumn int) func(row []string, column int) string {
+	return func(row []string, column int) string {
+		if column != statusColumn {
+			return ""
+		}
+
+		for status, color := range statusColors {
+			if row[column] == a.Status(status) {
+				return color
+			}
+		}
+
+		return ""
+	}
+}
+
 // Stderr returns standard error used by this app
 func (a *AppContext) Stderr() *os.File {
 	return a.stderr
@@ -292,6 +523,13 @@ func (a *AppContext) WriteLn(s string) *AppContext {
 	return a
 }
 
+// WriteTable renders a table to standard output
+// of this app
+func (a *AppContext) WriteTable(t *Table) *AppContext {
+	t.Render(a, a.Config().EOL, a.ColorsEnabled())
+	return a
+}
+
 // WriteString writes string data to standard output
 // of this app
 func (a *AppContext) WriteString(s string) *AppContext {
@@ -0,0 +1,216 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the “Software”), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package app
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/mkloubert/autark/utils"
+)
+
+func newTestAppContext() *AppContext {
+	return &AppContext{
+		config: &AppConfig{EOL: "\n"},
+	}
+}
+
+func TestPromptsWithClosedStdin(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	defer r.Close()
+
+	a := newTestAppContext()
+	a.stdin = r
+
+	if got := a.PromptYesNo("Continue?", true); !got {
+		t.Errorf("PromptYesNo() = %v, want default true", got)
+	}
+	if got := a.PromptYesNo("Continue?", false); got {
+		t.Errorf("PromptYesNo() = %v, want default false", got)
+	}
+	if got := a.PromptPort("Port?", 2222); got != 2222 {
+		t.Errorf("PromptPort() = %d, want default 2222", got)
+	}
+}
+
+func TestReadYesNo(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		defaultYes bool
+		want       bool
+	}{
+		{name: "closed reader with default yes", input: "", defaultYes: true, want: true},
+		{name: "closed reader with default no", input: "", defaultYes: false, want: false},
+		{name: "invalid answer, then closed", input: "maybe", defaultYes: true, want: true},
+		{name: "yes", input: "yes\n", defaultYes: false, want: true},
+		{name: "no after invalid answer", input: "maybe\nN\n", defaultYes: true, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newTestAppContext()
+			reader := bufio.NewReader(strings.NewReader(tt.input))
+
+			if got := a.readYesNo(reader, "Continue?", "[y/n]", tt.defaultYes); got != tt.want {
+				t.Errorf("readYesNo() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadPort(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  int
+	}{
+		{name: "closed reader", input: "", want: 2222},
+		{name: "invalid port, then closed", input: "abc", want: 2222},
+		{name: "port out of range, then closed", input: "70000\n", want: 2222},
+		{name: "valid port after invalid one", input: "abc\n2200\n", want: 2200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newTestAppContext()
+			reader := bufio.NewReader(strings.NewReader(tt.input))
+
+			if got := a.readPort(reader, "Port?", 2222); got != tt.want {
+				t.Errorf("readPort() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolvePath(t *testing.T) {
+	workDir := filepath.Join(t.TempDir(), "stack")
+	absPath := filepath.Join(t.TempDir(), "compose.yaml")
+
+	tests := []struct {
+		name    string
+		workDir string
+		path    string
+		want    string
+	}{
+		{name: "relative", workDir: workDir, path: "compose.yaml", want: filepath.Join(workDir, "compose.yaml")},
+		{name: "absolute", workDir: workDir, path: absPath, want: absPath},
+		{name: "empty", workDir: workDir, path: "", want: ""},
+		{name: "without work dir", workDir: "", path: "compose.yaml", want: "compose.yaml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newTestAppContext()
+			a.config.WorkDir = tt.workDir
+
+			if got := a.ResolvePath(tt.path); got != tt.want {
+				t.Errorf("ResolvePath(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunCommandInDir(t *testing.T) {
+	if runtime.GOOS == "windows" || !utils.CommandExists("sh") {
+		t.Skip("sh is not available")
+	}
+
+	workDir := t.TempDir()
+
+	stdout, err := os.Create(filepath.Join(t.TempDir(), "stdout"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stdout.Close()
+
+	a := newTestAppContext()
+	a.config.WorkDir = workDir
+	a.stdin = os.Stdin
+	a.stdout = stdout
+	a.stderr = os.Stderr
+
+	if err := a.RunCommandInDir("sh", "-c", "pwd -P"); err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := os.ReadFile(stdout.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := filepath.EvalSymlinks(workDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(string(output)); got != want {
+		t.Errorf("working directory = %q, want %q", got, want)
+	}
+}
+
+func TestPromptYesNoInCI(t *testing.T) {
+	t.Setenv("CI", "true")
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	defer r.Close()
+
+	stdout, err := os.Create(filepath.Join(t.TempDir(), "stdout"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stdout.Close()
+
+	a, err := NewAppContext()
+	if err != nil || a == nil {
+		t.Fatalf("NewAppContext() error = %v", err)
+	}
+	a.stdin = r
+	a.stdout = stdout
+
+	if !a.Config().Yes {
+		t.Fatal("Yes = false, want the default true inside CI systems")
+	}
+	if got := a.PromptYesNo("Would you like to recreate the registry container?", false); got {
+		t.Errorf("PromptYesNo() = %v, want the default false inside CI systems", got)
+	}
+	if got := a.PromptYesNo("Continue?", true); !got {
+		t.Errorf("PromptYesNo() = %v, want the default true inside CI systems", got)
+	}
+
+	if err := a.RootCommand().PersistentFlags().Set("yes", "true"); err != nil {
+		t.Fatal(err)
+	}
+	if got := a.PromptYesNo("Would you like to recreate the registry container?", false); !got {
+		t.Errorf("PromptYesNo() = %v, want true with an explicit --yes", got)
+	}
+}
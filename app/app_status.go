@@ -0,0 +1,65 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the “Software”), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package app
+
+import "fmt"
+
+// Status is the status of a check or a step, which
+// is written as marker in front of a line or in a table
+type Status string
+
+const (
+	StatusError   Status = "ERROR"
+	StatusInfo    Status = "INFO"
+	StatusOK      Status = "OK"
+	StatusSkipped Status = "SKIPPED"
+	StatusWarn    Status = "WARN"
+)
+
+// statusColors contains the colors of the known statuses
+var statusColors = map[Status]string{
+	StatusError: ColorRed,
+	StatusOK:    ColorGreen,
+	StatusWarn:  ColorYellow,
+}
+
+// statusGlyphs contains the symbols, which are written in front
+// of the known statuses, if output is not limited to ASCII
+var statusGlyphs = map[Status]string{
+	StatusError:   "✖",
+	StatusInfo:    "ℹ",
+	StatusOK:      "✔",
+	StatusSkipped: "»",
+	StatusWarn:    "⚠",
+}
+
+// FormatStatus returns the marker of a status, which is
+// "[OK]", "[ERROR]", etc. if ascii is true, or the status
+// with a symbol, like "✔ OK", otherwise
+func FormatStatus(s Status, ascii bool) string {
+	glyph, ok := statusGlyphs[s]
+	if ascii || !ok {
+		return fmt.Sprintf("[%s]", s)
+	}
+
+	return fmt.Sprintf("%s %s", glyph, s)
+}
@@ -0,0 +1,67 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the “Software”), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package app
+
+import (
+	"os"
+	"testing"
+	"unicode/utf8"
+)
+
+var allStatuses = []Status{StatusError, StatusInfo, StatusOK, StatusSkipped, StatusWarn}
+
+func TestFormatStatusASCII(t *testing.T) {
+	for _, s := range allStatuses {
+		got := FormatStatus(s, true)
+
+		if want := "[" + string(s) + "]"; got != want {
+			t.Errorf("FormatStatus(%q, true) = %q, want %q", s, got, want)
+		}
+		if utf8.RuneCountInString(got) != len(got) {
+			t.Errorf("FormatStatus(%q, true) = %q, contains multibyte characters", s, got)
+		}
+	}
+}
+
+func TestFormatStatusSymbols(t *testing.T) {
+	if got, want := FormatStatus(StatusOK, false), "✔ OK"; got != want {
+		t.Errorf("FormatStatus(%q, false) = %q, want %q", StatusOK, got, want)
+	}
+	if got, want := FormatStatus(Status("CUSTOM"), false), "[CUSTOM]"; got != want {
+		t.Errorf("FormatStatus(%q, false) = %q, want %q", "CUSTOM", got, want)
+	}
+}
+
+func TestASCIIOnlyIsDefault(t *testing.T) {
+	a := newTestAppContext()
+	a.stdout = os.Stdout
+
+	if !a.ASCIIOnly() {
+		t.Errorf("ASCIIOnly() = false, want true without --symbols")
+	}
+
+	a.config.Symbols = true
+	a.config.ASCII = true
+	if !a.ASCIIOnly() {
+		t.Errorf("ASCIIOnly() = false, want true with --ascii")
+	}
+}
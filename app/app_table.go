@@ -0,0 +1,140 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the “Software”), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package app
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// ColorGreen is the ANSI code for green text
+	ColorGreen = "\x1b[32m"
+	// ColorRed is the ANSI code for red text
+	ColorRed = "\x1b[31m"
+	// ColorYellow is the ANSI code for yellow text
+	ColorYellow = "\x1b[33m"
+
+	colorReset = "\x1b[0m"
+)
+
+// TableColumnSeparator is the string between two columns of a Table
+const TableColumnSeparator = "  "
+
+// Table renders rows of text in columns, which are aligned
+// by the widest cell of each column
+type Table struct {
+	// Colorize is an optional function, which returns the ANSI color
+	// code of a cell, or an empty string, if the cell should not be colored
+	Colorize func(row []string, column int) string
+
+	header []string
+	rows   [][]string
+}
+
+// NewTable creates a new instance of Table with an optional header
+func NewTable(header ...string) *Table {
+	return &Table{
+		header: header,
+		rows:   make([][]string, 0),
+	}
+}
+
+// AddRow adds a new row with cells to this table
+func (t *Table) AddRow(cells ...string) *Table {
+	t.rows = append(t.rows, cells)
+	return t
+}
+
+// ColumnWidths returns the width of each column, which is the
+// number of characters of its widest cell, including the header
+func (t *Table) ColumnWidths() []int {
+	widths := make([]int, 0)
+
+	measure := func(cells []string) {
+		for i, cell := range cells {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+
+			if width := utf8.RuneCountInString(cell); width > widths[i] {
+				widths[i] = width
+			}
+		}
+	}
+
+	measure(t.header)
+	for _, row := range t.rows {
+		measure(row)
+	}
+
+	return widths
+}
+
+// Render writes the header and all rows of this table to w,
+// each line terminated by eol
+//
+// Cells are only colored if colors is true
+func (t *Table) Render(w io.Writer, eol string, colors bool) error {
+	widths := t.ColumnWidths()
+
+	if len(t.header) > 0 {
+		if _, err := fmt.Fprint(w, t.renderRow(t.header, widths, false), eol); err != nil {
+			return err
+		}
+	}
+
+	for _, row := range t.rows {
+		if _, err := fmt.Fprint(w, t.renderRow(row, widths, colors), eol); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t *Table) renderRow(row []string, widths []int, colors bool) string {
+	var line strings.Builder
+
+	for i, cell := range row {
+		if i > 0 {
+			line.WriteString(TableColumnSeparator)
+		}
+
+		text := cell
+		if colors && t.Colorize != nil {
+			if color := t.Colorize(row, i); color != "" {
+				text = color + cell + colorReset
+			}
+		}
+		line.WriteString(text)
+
+		if i < len(row)-1 {
+			// no trailing spaces after the last column
+			line.WriteString(strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)))
+		}
+	}
+
+	return line.String()
+}
@@ -0,0 +1,93 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the “Software”), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package app
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTableColumnWidths(t *testing.T) {
+	table := NewTable("NAME", "STATUS").
+		AddRow("docker", "ok").
+		AddRow("git", "not installed", "extra")
+
+	want := []int{6, 13, 5}
+	if got := table.ColumnWidths(); !reflect.DeepEqual(got, want) {
+		t.Errorf("ColumnWidths() = %v, want %v", got, want)
+	}
+}
+
+func TestTableColumnWidthsCountsRunes(t *testing.T) {
+	table := NewTable("A").AddRow("äöü")
+
+	if got := table.ColumnWidths(); !reflect.DeepEqual(got, []int{3}) {
+		t.Errorf("ColumnWidths() = %v, want [3]", got)
+	}
+}
+
+func TestTableRender(t *testing.T) {
+	table := NewTable("NAME", "STATUS").
+		AddRow("docker", "ok").
+		AddRow("git", "missing")
+
+	var out strings.Builder
+	if err := table.Render(&out, "\n", false); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "NAME    STATUS\n" +
+		"docker  ok\n" +
+		"git     missing\n"
+	if got := out.String(); got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+}
+
+func TestTableRenderColors(t *testing.T) {
+	table := NewTable("NAME", "STATUS").AddRow("git", "missing")
+	table.Colorize = func(row []string, column int) string {
+		if column == 1 {
+			return ColorRed
+		}
+		return ""
+	}
+
+	var plain strings.Builder
+	if err := table.Render(&plain, "\n", false); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(plain.String(), "\x1b[") {
+		t.Errorf("Render() without colors = %q, contains ANSI codes", plain.String())
+	}
+
+	var colored strings.Builder
+	if err := table.Render(&colored, "\n", true); err != nil {
+		t.Fatal(err)
+	}
+	want := "NAME  STATUS\n" +
+		"git   " + ColorRed + "missing" + colorReset + "\n"
+	if got := colored.String(); got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+}
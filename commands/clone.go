@@ -0,0 +1,150 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/mkloubert/autark/app"
+	"github.com/mkloubert/autark/utils"
+	"github.com/spf13/cobra"
+)
+
+// CloneOptions contains options for the clone command
+type CloneOptions struct {
+	Branch string
+	Depth  int
+}
+
+// buildGitCloneArgs builds the arguments for a "git clone" call
+func buildGitCloneArgs(opts *CloneOptions, repoURL string, dir string) []string {
+	args := []string{"clone"}
+
+	if opts.Branch != "" {
+		args = append(args, "--branch", opts.Branch)
+	}
+	if opts.Depth > 0 {
+		args = append(args, "--depth", strconv.Itoa(opts.Depth))
+	}
+
+	return append(args, "--", repoURL, dir)
+}
+
+// getCloneDirectory returns the directory, which git uses for
+// a repository by default, like "stack" for "https://host/org/stack.git"
+func getCloneDirectory(repoURL string) string {
+	name := strings.TrimRight(repoURL, "/")
+	name = strings.TrimSuffix(name, "/.git")
+
+	// scp-like syntax, like "git@host:org/stack.git"
+	if i := strings.LastIndexAny(name, "/:"); i >= 0 {
+		name = name[i+1:]
+	}
+
+	return strings.TrimSuffix(path.Base(name), ".git")
+}
+
+func initCloneCommand(a *app.AppContext) {
+	rootCmd := a.RootCommand()
+
+	opts := &CloneOptions{}
+
+	cloneCmd := &cobra.Command{
+		Use:   "clone <repo-url> [dir]",
+		Short: "Clone a stack repository",
+		Long:  `Clones the git repository of a Docker Compose stack, which can be deployed with 'autark compose up' afterwards.`,
+		Example: `  autark clone https://github.com/example/mystack.git
+  autark clone --branch production --depth 1 https://github.com/example/mystack.git ./stack`,
+		Args: cobra.RangeArgs(1, 2),
+		Run: func(cmd *cobra.Command, args []string) {
+			dir := ""
+			if len(args) > 1 {
+				dir = args[1]
+			}
+
+			exitOnError(a, runClone(a, opts, args[0], dir))
+		},
+	}
+
+	cloneCmd.Flags().StringVarP(&opts.Branch, "branch", "b", "", "Branch or tag to check out")
+	cloneCmd.Flags().IntVarP(&opts.Depth, "depth", "", 0, "Create a shallow clone with this number of commits")
+
+	rootCmd.AddCommand(cloneCmd)
+}
+
+func runClone(a *app.AppContext, opts *CloneOptions, repoURL string, dir string) error {
+	if opts.Depth < 0 {
+		return fmt.Errorf("invalid --depth value %d: expected a positive number", opts.Depth)
+	}
+
+	if gitResult := checkGit(); !gitResult.Installed {
+		return fmt.Errorf("git is not installed. Please run 'autark doctor --repair' first")
+	}
+
+	if dir == "" {
+		dir = getCloneDirectory(repoURL)
+		if dir == "" {
+			return fmt.Errorf("could not determine a directory for %s, please specify one", utils.RedactValue(repoURL))
+		}
+	} else {
+		expandedDir, err := utils.ExpandPath(dir)
+		if err != nil {
+			return fmt.Errorf("invalid directory: %w", err)
+		}
+		dir = expandedDir
+	}
+	dir = a.ResolvePath(dir)
+
+	// git only clones into empty directories
+	if entries, err := os.ReadDir(dir); err == nil && len(entries) > 0 {
+		return fmt.Errorf("directory %s already exists and is not empty, please specify another one", dir)
+	} else if err == nil {
+		a.D("Cloning into existing empty directory %s", dir)
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("directory %s cannot be used: %w", dir, err)
+	}
+
+	args := buildGitCloneArgs(opts, repoURL, dir)
+	a.D("Running: %s", utils.RedactCommandLine("git", args...))
+
+	if err := a.RunCommandInDir("git", args...); err != nil {
+		return fmt.Errorf("git clone failed: %w", err)
+	}
+
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		absDir = dir
+	}
+
+	a.WriteLn("")
+	a.WriteF("Cloned into %s", absDir)
+	a.WriteLn("")
+	a.WriteF("Run 'cd %s && autark compose up' to deploy the stack.", dir)
+	a.WriteLn("")
+
+	return nil
+}
@@ -0,0 +1,85 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package commands
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBuildGitCloneArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		opts *CloneOptions
+		dir  string
+		want []string
+	}{
+		{
+			name: "defaults",
+			opts: &CloneOptions{},
+			dir:  "stack",
+			want: []string{"clone", "--", "https://github.com/example/stack.git", "stack"},
+		},
+		{
+			name: "branch and depth",
+			opts: &CloneOptions{Branch: "production", Depth: 1},
+			dir:  "./stack",
+			want: []string{"clone", "--branch", "production", "--depth", "1", "--", "https://github.com/example/stack.git", "./stack"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := buildGitCloneArgs(tt.opts, "https://github.com/example/stack.git", tt.dir)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("buildGitCloneArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildGitCloneArgsOptionLikeURL(t *testing.T) {
+	got := buildGitCloneArgs(&CloneOptions{}, "--upload-pack=touch /tmp/pwned", "stack")
+
+	separator := slices.Index(got, "--")
+	if separator == -1 || slices.Index(got, "--upload-pack=touch /tmp/pwned") < separator {
+		t.Errorf("buildGitCloneArgs() = %q, want the URL after --", got)
+	}
+}
+
+func TestGetCloneDirectory(t *testing.T) {
+	tests := []struct {
+		repoURL string
+		want    string
+	}{
+		{repoURL: "https://github.com/example/stack.git", want: "stack"},
+		{repoURL: "https://github.com/example/stack/", want: "stack"},
+		{repoURL: "git@github.com:example/stack.git", want: "stack"},
+		{repoURL: "git@host:stack.git", want: "stack"},
+	}
+
+	for _, tt := range tests {
+		if got := getCloneDirectory(tt.repoURL); got != tt.want {
+			t.Errorf("getCloneDirectory(%q) = %q, want %q", tt.repoURL, got, tt.want)
+		}
+	}
+}
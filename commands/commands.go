@@ -22,12 +22,174 @@
 package commands
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"syscall"
+
 	"github.com/mkloubert/autark/app"
+	"github.com/mkloubert/autark/utils"
 )
 
+// lockFileName is the name of the lock file inside the state directory,
+// which prevents concurrent runs of commands changing the system
+const lockFileName = "autark.lock"
+
 // InitCommands initializes all commands
 // for a specific app
 func InitCommands(a *app.AppContext) {
+	initCloneCommand(a)
+	initComposeCommand(a)
+	initConfigCommand(a)
 	initDoctorCommand(a)
+	initInstallCommand(a)
+	initPruneCommand(a)
+	initRegistryCommand(a)
 	initSetupCommand(a)
+	initStatusCommand(a)
+	initSupportedCommand(a)
+}
+
+// confirmPrivilegeEscalation asks the user on a terminal, if the
+// current command should be run again with escalationCmd
+func confirmPrivilegeEscalation(a *app.AppContext, escalationCmd string) bool {
+	return a.ConfirmInteractive(fmt.Sprintf("Run this command again with %s?", escalationCmd))
+}
+
+// dropFileOwnership gives files, which have been created in the home
+// directory of the user, who has run autark with sudo, back to that user
+func dropFileOwnership(a *app.AppContext, paths ...string) {
+	for _, path := range paths {
+		if err := utils.DropFileOwnership(path); err != nil {
+			a.D("Could not change the owner of %s: %s", path, err.Error())
+		}
+	}
+}
+
+// exitOnError writes an error to standard error
+// and exits with code 1, if err is not nil
+//
+// If err is caused by missing root privileges, the user is asked
+// to run the same command again with sudo or doas
+func exitOnError(a *app.AppContext, err error) {
+	if err == nil {
+		return
+	}
+
+	a.WriteErrLn(err.Error())
+
+	var privilegesErr *rootPrivilegesError
+	if errors.As(err, &privilegesErr) {
+		if exitCode, ok := rerunWithPrivileges(a); ok {
+			os.Exit(exitCode)
+		}
+	}
+
+	os.Exit(1)
+}
+
+// rootPrivilegesError describes, that an action requires
+// root/administrator privileges
+type rootPrivilegesError struct {
+	message string
+}
+
+func (e *rootPrivilegesError) Error() string {
+	return e.message
+}
+
+// newRootPrivilegesError creates an error, which describes that
+// an action requires root/administrator privileges
+func newRootPrivilegesError(a *app.AppContext, action string) error {
+	eol := a.Config().EOL
+
+	if runtime.GOOS == "windows" {
+		return &rootPrivilegesError{
+			message: fmt.Sprintf("Error: %s requires administrator privileges.%sPlease run this command as Administrator.", action, eol),
+		}
+	}
+
+	return &rootPrivilegesError{
+		message: fmt.Sprintf("Error: %s requires root privileges.%s%s", action, eol, getRootPrivilegesHint()),
+	}
+}
+
+// rerunWithPrivileges asks, if the current command should be run again
+// with the available privilege escalation command, and returns its exit
+// code, if it has been run
+//
+// The command is only run again, if the user has confirmed it on a
+// terminal, because an escalation is never approved by --yes
+func rerunWithPrivileges(a *app.AppContext) (int, bool) {
+	if runtime.GOOS == "windows" || utils.IsRoot() {
+		return 0, false
+	}
+
+	escalationCmd := utils.PrivilegeEscalationCommand()
+	if escalationCmd == "" {
+		return 0, false
+	}
+
+	a.WriteLn("")
+	if !confirmPrivilegeEscalation(a, escalationCmd) {
+		return 0, false
+	}
+
+	exitCode, err := utils.RerunWithPrivileges(escalationCmd)
+	if err != nil {
+		a.WriteErrLn(fmt.Sprintf("Failed to run this command with %s: %s", escalationCmd, err.Error()))
+		return 1, true
+	}
+
+	return exitCode, true
+}
+
+// runWithLock runs an action, which changes the system, while holding
+// the lock file in the state directory, so concurrent runs fail fast
+//
+// The lock is also released if the process is interrupted by a signal
+func runWithLock(a *app.AppContext, action func() error) error {
+	stateDir, err := utils.StateDir()
+	if err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	lock, err := utils.AcquireFileLock(filepath.Join(stateDir, lockFileName))
+	if err != nil {
+		var heldErr *utils.LockHeldError
+		if errors.As(err, &heldErr) {
+			return fmt.Errorf("Another instance of autark (PID %d) is already running. Please wait until it has finished or remove %s if that process does not exist anymore", heldErr.PID, heldErr.Path)
+		}
+
+		return fmt.Errorf("failed to acquire lock: %w", err)
+	}
+
+	a.D("Acquired lock: %s", lock.Path())
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-signals:
+			lock.Release()
+			os.Exit(130)
+		case <-done:
+		}
+	}()
+
+	defer func() {
+		signal.Stop(signals)
+		close(done)
+
+		if err := lock.Release(); err != nil {
+			a.W("Failed to release lock %s: %s", lock.Path(), err.Error())
+		}
+	}()
+
+	return action()
 }
@@ -0,0 +1,89 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the “Software”), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED “AS IS”, WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package commands
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/mkloubert/autark/app"
+)
+
+// newTestAppContext creates an app for tests, which answers
+// all prompts with their defaults
+func newTestAppContext(t *testing.T) *app.AppContext {
+	t.Helper()
+
+	a, err := app.NewAppContext()
+	if err != nil || a == nil {
+		t.Fatalf("NewAppContext() error = %v", err)
+	}
+
+	return a
+}
+
+func TestCommandsHaveExamples(t *testing.T) {
+	a := newTestAppContext(t)
+	InitCommands(a)
+
+	commands := a.RootCommand().Commands()
+	if len(commands) == 0 {
+		t.Fatal("InitCommands() has not added any commands")
+	}
+
+	for len(commands) > 0 {
+		cmd := commands[0]
+		commands = append(commands[1:], cmd.Commands()...)
+
+		if strings.TrimSpace(cmd.Example) == "" {
+			t.Errorf("command %q has no example", cmd.CommandPath())
+		}
+	}
+}
+
+func TestConfirmPrivilegeEscalationIgnoresYes(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// an answer is available, but no terminal
+	w.WriteString("y\n")
+	w.Close()
+	defer r.Close()
+
+	stdin := os.Stdin
+	os.Stdin = r
+	a, _ := newTestAppContextWithOutput(t)
+	os.Stdin = stdin
+
+	if err := a.RootCommand().PersistentFlags().Set("yes", "true"); err != nil {
+		t.Fatal(err)
+	}
+	if !a.PromptYesNo("Continue?", false) {
+		t.Fatal("PromptYesNo() = false, want true with an explicit --yes")
+	}
+
+	if confirmPrivilegeEscalation(a, "sudo") {
+		t.Error("confirmPrivilegeEscalation() = true, want false with --yes and without a terminal")
+	}
+}
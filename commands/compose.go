@@ -0,0 +1,316 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/mkloubert/autark/app"
+	"github.com/mkloubert/autark/utils"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// ComposeOptions contains options for the compose command
+type ComposeOptions struct {
+	EnvFile     string
+	File        string
+	ProjectName string
+}
+
+// ComposeDownOptions contains options for the compose down command
+type ComposeDownOptions struct {
+	// Volumes indicates if the named volumes of the stack
+	// should be removed, too
+	Volumes bool
+}
+
+// ComposeLogsOptions contains options for the compose logs command
+type ComposeLogsOptions struct {
+	// Follow indicates if new log lines should be streamed
+	Follow bool
+	// Tail is the number of lines per service from the end
+	// of the logs, or "all"
+	Tail string
+}
+
+// defaultEnvFileName is the name of the env file, which is read by
+// Docker Compose from the directory of the project automatically
+const defaultEnvFileName = ".env"
+
+// composeFileNames contains the names of the compose files,
+// which are searched without --file, in order of precedence
+var composeFileNames = []string{"compose.yaml", "compose.yml", "docker-compose.yaml", "docker-compose.yml"}
+
+// buildComposeArgs builds the arguments for a "docker compose" call
+// with the project settings of opts and a specific subcommand
+func buildComposeArgs(opts *ComposeOptions, subcommand ...string) []string {
+	args := []string{"compose"}
+
+	if opts.File != "" {
+		args = append(args, "--file", opts.File)
+	}
+	if opts.EnvFile != "" {
+		args = append(args, "--env-file", opts.EnvFile)
+	}
+	if opts.ProjectName != "" {
+		args = append(args, "--project-name", opts.ProjectName)
+	}
+
+	return append(args, subcommand...)
+}
+
+// buildComposeDownArgs builds the subcommand of "docker compose",
+// which stops and removes the stack
+func buildComposeDownArgs(opts *ComposeDownOptions) []string {
+	args := []string{"down"}
+
+	if opts.Volumes {
+		args = append(args, "--volumes")
+	}
+
+	return args
+}
+
+// buildComposeLogsArgs builds the subcommand of "docker compose",
+// which shows the logs of all or specific services of the stack
+func buildComposeLogsArgs(opts *ComposeLogsOptions, services []string) ([]string, error) {
+	args := []string{"logs"}
+
+	if opts.Follow {
+		args = append(args, "--follow")
+	}
+	if opts.Tail != "" && opts.Tail != "all" {
+		if n, err := strconv.Atoi(opts.Tail); err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid --tail value %q: expected a number of lines or all", opts.Tail)
+		}
+		args = append(args, "--tail", opts.Tail)
+	}
+
+	return append(args, services...), nil
+}
+
+// discoverComposeFile returns the path of the first file of
+// composeFileNames, which exists in dir
+func discoverComposeFile(dir string, exists func(path string) bool) (string, error) {
+	if dir == "" {
+		dir = "."
+	}
+
+	for _, name := range composeFileNames {
+		composeFile := filepath.Join(dir, name)
+		if exists(composeFile) {
+			return composeFile, nil
+		}
+	}
+
+	return "", fmt.Errorf("no compose file found in %s, tried %s", dir, strings.Join(composeFileNames, ", "))
+}
+
+// discoverEnvFile returns the path of the .env file in the directory
+// of the compose file, or workDir, if there is no compose file, or
+// an empty string, if it does not exist
+func discoverEnvFile(composeFile string, workDir string, exists func(path string) bool) string {
+	dir := workDir
+	if dir == "" {
+		dir = "."
+	}
+	if composeFile != "" {
+		dir = filepath.Dir(composeFile)
+	}
+
+	envFile := filepath.Join(dir, defaultEnvFileName)
+	if !exists(envFile) {
+		return ""
+	}
+
+	return envFile
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+func initComposeCommand(a *app.AppContext) {
+	rootCmd := a.RootCommand()
+
+	opts := &ComposeOptions{}
+
+	composeCmd := &cobra.Command{
+		Use:     "compose",
+		Aliases: []string{"c"},
+		Short:   "Manage the Docker Compose stack",
+		Long:    `Manages the Docker Compose stack of the current directory.`,
+		Example: `  autark compose up
+  autark compose --file ./stack/compose.yaml --project-name mystack up
+  autark compose logs --follow web`,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+
+	initComposeFlags(composeCmd.PersistentFlags(), opts)
+
+	upCmd := &cobra.Command{
+		Use:   "up",
+		Short: "Deploy the stack",
+		Long:  `Creates and starts the services of the Docker Compose stack in the background.`,
+		Example: `  autark compose up
+  autark compose --env-file ./production.env up`,
+		Run: func(cmd *cobra.Command, args []string) {
+			exitOnError(a, runComposeUp(a, opts))
+		},
+	}
+
+	downOpts := &ComposeDownOptions{}
+
+	downCmd := &cobra.Command{
+		Use:   "down",
+		Short: "Remove the stack",
+		Long:  `Stops and removes the containers and networks of the Docker Compose stack and, with --volumes, its named volumes.`,
+		Example: `  autark compose down
+  autark compose down --volumes`,
+		Run: func(cmd *cobra.Command, args []string) {
+			exitOnError(a, runComposeDown(a, opts, downOpts))
+		},
+	}
+
+	downCmd.Flags().BoolVarP(&downOpts.Volumes, "volumes", "v", false, "Remove the named volumes of the stack, too")
+
+	logsOpts := &ComposeLogsOptions{}
+
+	logsCmd := &cobra.Command{
+		Use:   "logs [service...]",
+		Short: "Show the logs of the stack",
+		Long:  `Shows the logs of all services of the Docker Compose stack or only of the specified ones.`,
+		Example: `  autark compose logs
+  autark compose logs --follow --tail 100 web`,
+		Run: func(cmd *cobra.Command, args []string) {
+			exitOnError(a, runComposeLogs(a, opts, logsOpts, args))
+		},
+	}
+
+	logsFlags := logsCmd.Flags()
+	logsFlags.BoolVarP(&logsOpts.Follow, "follow", "", false, "Stream new log lines until Ctrl-C is pressed")
+	logsFlags.StringVarP(&logsOpts.Tail, "tail", "", "all", "Number of lines per service from the end of the logs, or all")
+
+	composeCmd.AddCommand(downCmd)
+	composeCmd.AddCommand(logsCmd)
+	composeCmd.AddCommand(upCmd)
+
+	rootCmd.AddCommand(composeCmd)
+}
+
+// initComposeFlags registers the flags for ComposeOptions, which are
+// shared by all commands running Docker Compose
+func initComposeFlags(flags *pflag.FlagSet, opts *ComposeOptions) {
+	flags.StringVarP(&opts.File, "file", "f", "", fmt.Sprintf("Compose file to use (default: first of %s)", strings.Join(composeFileNames, ", ")))
+	flags.StringVarP(&opts.EnvFile, "env-file", "", "", "Env file to use (default: .env next to the compose file)")
+	flags.StringVarP(&opts.ProjectName, "project-name", "p", "", "Project name (default: name of the directory)")
+}
+
+func runCompose(a *app.AppContext, opts *ComposeOptions, subcommand ...string) error {
+	if err := checkDockerAvailable(); err != nil {
+		return err
+	}
+
+	file, err := utils.ExpandPath(opts.File)
+	if err != nil {
+		return fmt.Errorf("invalid --file: %w", err)
+	}
+	file = a.ResolvePath(file)
+
+	if file == "" {
+		file, err = discoverComposeFile(a.Config().WorkDir, fileExists)
+		if err != nil {
+			return err
+		}
+		a.D("Detected compose file: %s", file)
+	}
+
+	envFile, err := utils.ExpandPath(opts.EnvFile)
+	if err != nil {
+		return fmt.Errorf("invalid --env-file: %w", err)
+	}
+	envFile = a.ResolvePath(envFile)
+
+	if envFile != "" {
+		if _, err := os.Stat(envFile); err != nil {
+			return fmt.Errorf("invalid --env-file: %w", err)
+		}
+		a.D("Using env file: %s", envFile)
+	} else if discoveredEnvFile := discoverEnvFile(file, a.Config().WorkDir, fileExists); discoveredEnvFile != "" {
+		// Docker Compose reads it automatically
+		a.D("Detected env file: %s", discoveredEnvFile)
+	} else {
+		a.D("No env file detected")
+	}
+
+	expandedOpts := *opts
+	expandedOpts.File = file
+	expandedOpts.EnvFile = envFile
+
+	args := buildComposeArgs(&expandedOpts, subcommand...)
+	a.D("Running: %s", utils.RedactCommandLine("docker", args...))
+
+	if err := a.RunCommandInDir("docker", args...); err != nil {
+		return fmt.Errorf("docker compose %s failed: %w", subcommand[0], err)
+	}
+
+	return nil
+}
+
+func runComposeDown(a *app.AppContext, opts *ComposeOptions, downOpts *ComposeDownOptions) error {
+	a.WriteLn("Removing stack...")
+
+	if err := runCompose(a, opts, buildComposeDownArgs(downOpts)...); err != nil {
+		return err
+	}
+
+	a.WriteLn("Stack removed successfully.")
+	return nil
+}
+
+func runComposeLogs(a *app.AppContext, opts *ComposeOptions, logsOpts *ComposeLogsOptions, services []string) error {
+	args, err := buildComposeLogsArgs(logsOpts, services)
+	if err != nil {
+		return err
+	}
+
+	return runCompose(a, opts, args...)
+}
+
+func runComposeUp(a *app.AppContext, opts *ComposeOptions) error {
+	a.WriteLn("Deploying stack...")
+
+	if err := runCompose(a, opts, "up", "--detach"); err != nil {
+		return err
+	}
+
+	a.WriteLn("Stack deployed successfully.")
+	return nil
+}
@@ -0,0 +1,192 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package commands
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// existingFiles returns a function for the exists parameter,
+// which only reports the specific paths as existing
+func existingFiles(paths ...string) func(path string) bool {
+	return func(path string) bool {
+		return slices.Contains(paths, path)
+	}
+}
+
+func TestBuildComposeArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		opts *ComposeOptions
+		want []string
+	}{
+		{
+			name: "defaults",
+			opts: &ComposeOptions{},
+			want: []string{"compose", "up", "-d"},
+		},
+		{
+			name: "all project settings",
+			opts: &ComposeOptions{File: "stack/compose.yaml", EnvFile: "stack/.env", ProjectName: "shop"},
+			want: []string{"compose", "--file", "stack/compose.yaml", "--env-file", "stack/.env", "--project-name", "shop", "up", "-d"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := buildComposeArgs(tt.opts, "up", "-d"); !slices.Equal(got, tt.want) {
+				t.Errorf("buildComposeArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiscoverEnvFile(t *testing.T) {
+	tests := []struct {
+		name        string
+		composeFile string
+		workDir     string
+		existing    []string
+		want        string
+	}{
+		{
+			name:        "next to the compose file",
+			composeFile: filepath.Join("stack", "compose.yaml"),
+			workDir:     "other",
+			existing:    []string{filepath.Join("stack", ".env"), filepath.Join("other", ".env")},
+			want:        filepath.Join("stack", ".env"),
+		},
+		{
+			name:     "in the work dir",
+			workDir:  "other",
+			existing: []string{filepath.Join("other", ".env")},
+			want:     filepath.Join("other", ".env"),
+		},
+		{
+			name:     "in the current dir",
+			existing: []string{".env"},
+			want:     ".env",
+		},
+		{
+			name:        "missing",
+			composeFile: filepath.Join("stack", "compose.yaml"),
+			want:        "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := discoverEnvFile(tt.composeFile, tt.workDir, existingFiles(tt.existing...)); got != tt.want {
+				t.Errorf("discoverEnvFile() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiscoverComposeFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		dir      string
+		existing []string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "compose.yaml first",
+			dir:      "stack",
+			existing: []string{filepath.Join("stack", "docker-compose.yml"), filepath.Join("stack", "compose.yml"), filepath.Join("stack", "compose.yaml")},
+			want:     filepath.Join("stack", "compose.yaml"),
+		},
+		{
+			name:     "compose.yml before docker-compose.yaml",
+			dir:      "stack",
+			existing: []string{filepath.Join("stack", "docker-compose.yaml"), filepath.Join("stack", "compose.yml")},
+			want:     filepath.Join("stack", "compose.yml"),
+		},
+		{
+			name:     "docker-compose.yml in the current dir",
+			existing: []string{"docker-compose.yml"},
+			want:     "docker-compose.yml",
+		},
+		{
+			name:    "none",
+			dir:     "stack",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := discoverComposeFile(tt.dir, existingFiles(tt.existing...))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("discoverComposeFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("discoverComposeFile() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildComposeDownArgs(t *testing.T) {
+	if got, want := buildComposeDownArgs(&ComposeDownOptions{}), []string{"down"}; !slices.Equal(got, want) {
+		t.Errorf("buildComposeDownArgs() = %q, want %q", got, want)
+	}
+
+	if got, want := buildComposeDownArgs(&ComposeDownOptions{Volumes: true}), []string{"down", "--volumes"}; !slices.Equal(got, want) {
+		t.Errorf("buildComposeDownArgs(Volumes) = %q, want %q", got, want)
+	}
+}
+
+func TestBuildComposeLogsArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     *ComposeLogsOptions
+		services []string
+		want     []string
+	}{
+		{name: "defaults", opts: &ComposeLogsOptions{}, want: []string{"logs"}},
+		{name: "all lines", opts: &ComposeLogsOptions{Tail: "all"}, want: []string{"logs"}},
+		{name: "follow", opts: &ComposeLogsOptions{Follow: true, Tail: "100"}, want: []string{"logs", "--follow", "--tail", "100"}},
+		{name: "services", opts: &ComposeLogsOptions{Tail: "0"}, services: []string{"web", "db"}, want: []string{"logs", "--tail", "0", "web", "db"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildComposeLogsArgs(tt.opts, tt.services)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("buildComposeLogsArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	for _, tail := range []string{"-1", "ten", "1.5"} {
+		if _, err := buildComposeLogsArgs(&ComposeLogsOptions{Tail: tail}, nil); err == nil {
+			t.Errorf("buildComposeLogsArgs(Tail: %q) error = nil, want an error", tail)
+		}
+	}
+}
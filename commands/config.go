@@ -0,0 +1,212 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/mkloubert/autark/app"
+	"github.com/mkloubert/autark/utils"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	configSourceDefault = "default"
+	configSourceEnv     = "env"
+	configSourceFile    = "file"
+	configSourceFlag    = "flag"
+)
+
+// ConfigOptions contains options for the config command
+type ConfigOptions struct {
+	JSON bool
+}
+
+// ConfigValue contains the effective value of a setting
+// and where it comes from
+type ConfigValue struct {
+	// Name is the name of the setting, like "verbose" or "setup.registry-port"
+	Name string `json:"name"`
+	// Value is the effective value
+	Value string `json:"value"`
+	// Source is "flag", "env", "file" or "default"
+	Source string `json:"source"`
+}
+
+// collectEffectiveConfig collects the effective values of the global
+// flags and of the flags of all commands, which have a section
+// in the config file
+func collectEffectiveConfig(a *app.AppContext) ([]*ConfigValue, error) {
+	rootCmd := a.RootCommand()
+	values := make([]*ConfigValue, 0)
+
+	fileFlags := a.FlagsFromConfigFile()
+	rootCmd.PersistentFlags().VisitAll(func(f *pflag.Flag) {
+		value := &ConfigValue{
+			Name:   f.Name,
+			Value:  f.Value.String(),
+			Source: getConfigValueSource(f.Changed, slices.Contains(fileFlags, f.Name), false),
+		}
+
+		if f.Name == "config" && value.Value == "" && a.Config().File != nil {
+			value.Value = app.DefaultConfigFileName
+		}
+		if f.Name == "no-color" && value.Value == "false" && os.Getenv("NO_COLOR") != "" {
+			value.Value = "true"
+			value.Source = getConfigValueSource(false, false, true)
+		}
+		if f.Name == "yes" && !f.Changed && !slices.Contains(fileFlags, f.Name) && utils.IsCI() {
+			value.Source = getConfigValueSource(false, false, true)
+		}
+
+		values = append(values, value)
+	})
+
+	for _, section := range app.ConfigFileSections() {
+		cmd, _, err := rootCmd.Find([]string{section})
+		if err != nil || cmd == rootCmd {
+			continue
+		}
+
+		sectionFlags := make([]string, 0)
+		if configFile := a.Config().File; configFile != nil {
+			sectionFlags, err = configFile.ApplyTo(cmd)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s section: %w", section, err)
+			}
+		}
+
+		cmd.LocalNonPersistentFlags().VisitAll(func(f *pflag.Flag) {
+			if f.Name == "help" {
+				return
+			}
+
+			// flags of other commands are never set on the command line
+			values = append(values, &ConfigValue{
+				Name:   section + "." + f.Name,
+				Value:  f.Value.String(),
+				Source: getConfigValueSource(false, slices.Contains(sectionFlags, f.Name), false),
+			})
+		})
+	}
+
+	return values, nil
+}
+
+// getConfigValueSource returns the source of the value of a setting,
+// where the command line wins over the config file and the config
+// file wins over environment variables
+func getConfigValueSource(changed bool, fromFile bool, fromEnv bool) string {
+	switch {
+	case changed:
+		return configSourceFlag
+	case fromFile:
+		return configSourceFile
+	case fromEnv:
+		return configSourceEnv
+	default:
+		return configSourceDefault
+	}
+}
+
+func initConfigCommand(a *app.AppContext) {
+	rootCmd := a.RootCommand()
+
+	opts := &ConfigOptions{}
+
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect the configuration",
+		Long:  `Inspects the configuration, which is merged from flags, environment variables and the config file.`,
+		Example: `  autark config effective
+  autark --config ./autark.yml config effective --json`,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+
+	effectiveCmd := &cobra.Command{
+		Use:   "effective",
+		Short: "Print the effective configuration",
+		Long:  `Prints the effective value of each setting and its source, which is flag, env, file or default.`,
+		Example: `  autark config effective
+  autark config effective --json`,
+		Run: func(cmd *cobra.Command, args []string) {
+			exitOnError(a, runConfigEffective(a, opts))
+		},
+	}
+
+	effectiveCmd.Flags().BoolVarP(&opts.JSON, "json", "", false, "Output as JSON")
+
+	configCmd.AddCommand(effectiveCmd)
+
+	rootCmd.AddCommand(configCmd)
+}
+
+// redactConfigValues returns a copy of configuration values, where
+// the values of settings, which contain secrets, are masked
+func redactConfigValues(values []*ConfigValue) []*ConfigValue {
+	redacted := make([]*ConfigValue, 0, len(values))
+
+	for _, v := range values {
+		value := *v
+		if value.Value != "" && utils.IsSecretName(value.Name) {
+			value.Value = utils.RedactedValue
+		} else {
+			value.Value = utils.RedactValue(value.Value)
+		}
+
+		redacted = append(redacted, &value)
+	}
+
+	return redacted
+}
+
+func runConfigEffective(a *app.AppContext, opts *ConfigOptions) error {
+	values, err := collectEffectiveConfig(a)
+	if err != nil {
+		return err
+	}
+	values = redactConfigValues(values)
+
+	if opts.JSON {
+		data, err := json.MarshalIndent(values, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		a.WriteLn(string(data))
+		return nil
+	}
+
+	table := app.NewTable("SETTING", "VALUE", "SOURCE")
+	for _, v := range values {
+		table.AddRow(v.Name, v.Value, v.Source)
+	}
+
+	a.WriteTable(table)
+	return nil
+}
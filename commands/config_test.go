@@ -0,0 +1,48 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package commands
+
+import "testing"
+
+func TestGetConfigValueSource(t *testing.T) {
+	tests := []struct {
+		name     string
+		changed  bool
+		fromFile bool
+		fromEnv  bool
+		want     string
+	}{
+		{name: "default", want: configSourceDefault},
+		{name: "env", fromEnv: true, want: configSourceEnv},
+		{name: "file wins over env", fromFile: true, fromEnv: true, want: configSourceFile},
+		{name: "flag wins over file", changed: true, fromFile: true, fromEnv: true, want: configSourceFlag},
+		{name: "flag", changed: true, want: configSourceFlag},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getConfigValueSource(tt.changed, tt.fromFile, tt.fromEnv); got != tt.want {
+				t.Errorf("getConfigValueSource() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
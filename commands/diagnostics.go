@@ -0,0 +1,172 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package commands
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mkloubert/autark/app"
+	"github.com/mkloubert/autark/utils"
+)
+
+// registryLogLines is the number of recent log lines of the
+// registry container, which are added to a diagnostics bundle
+const registryLogLines = 200
+
+// diagnosticsEntry is a file inside a diagnostics bundle
+type diagnosticsEntry struct {
+	Name string
+	Data []byte
+}
+
+// diagnosticsResult is the JSON representation of a DoctorResult
+type diagnosticsResult struct {
+	Name      string `json:"name"`
+	Installed bool   `json:"installed"`
+	Version   string `json:"version,omitempty"`
+	Error     string `json:"error,omitempty"`
+	Optional  bool   `json:"optional"`
+}
+
+// collectDiagnostics collects the entries of a diagnostics bundle,
+// which contains the logs of the registry container of opts
+func collectDiagnostics(a *app.AppContext, opts *DoctorOptions, results []*DoctorResult) ([]*diagnosticsEntry, error) {
+	entries := make([]*diagnosticsEntry, 0)
+
+	addJSON := func(name string, v any) error {
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to serialize %s: %w", name, err)
+		}
+
+		entries = append(entries, &diagnosticsEntry{Name: name, Data: data})
+		return nil
+	}
+
+	if err := addJSON("platform.json", a.Platform()); err != nil {
+		return nil, err
+	}
+
+	diagnosticsResults := make([]*diagnosticsResult, 0, len(results))
+	for _, r := range results {
+		dr := &diagnosticsResult{
+			Name:      r.Name,
+			Installed: r.Installed,
+			Version:   r.Version,
+			Optional:  r.Optional,
+		}
+		if r.Error != nil {
+			dr.Error = r.Error.Error()
+		}
+
+		diagnosticsResults = append(diagnosticsResults, dr)
+	}
+	if err := addJSON("doctor.json", diagnosticsResults); err != nil {
+		return nil, err
+	}
+
+	configValues, err := collectEffectiveConfig(a)
+	if err != nil {
+		return nil, err
+	}
+	if err := addJSON("config.json", redactConfigValues(configValues)); err != nil {
+		return nil, err
+	}
+
+	entries = append(entries,
+		newCommandDiagnosticsEntry("docker-info.txt", "docker", "info"),
+		newCommandDiagnosticsEntry("registry-logs.txt", "docker", "logs", "--tail", fmt.Sprint(registryLogLines), opts.RegistryName),
+	)
+
+	return entries, nil
+}
+
+// newCommandDiagnosticsEntry creates an entry with the output of a command
+// and its error, so that a failing command does not fail the whole bundle
+func newCommandDiagnosticsEntry(name string, command string, args ...string) *diagnosticsEntry {
+	output, err := utils.RunCommand(command, args...)
+	if err != nil {
+		output = fmt.Appendf(output, "\n%s %s failed: %s\n", command, strings.Join(args, " "), err.Error())
+	}
+
+	return &diagnosticsEntry{Name: name, Data: output}
+}
+
+// writeDiagnosticsBundle writes a diagnostics bundle to the zip file
+// of opts.Bundle
+func writeDiagnosticsBundle(a *app.AppContext, opts *DoctorOptions, results []*DoctorResult) error {
+	bundlePath, err := utils.ExpandPath(opts.Bundle)
+	if err != nil {
+		return fmt.Errorf("invalid --bundle: %w", err)
+	}
+	bundlePath = a.ResolvePath(bundlePath)
+
+	entries, err := collectDiagnostics(a, opts, results)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(bundlePath)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", bundlePath, err)
+	}
+	defer file.Close()
+
+	if err := writeDiagnosticsZip(file, entries, time.Now()); err != nil {
+		return fmt.Errorf("failed to write %s: %w", bundlePath, err)
+	}
+	dropFileOwnership(a, bundlePath)
+
+	a.WriteF("Diagnostics bundle written to %s", bundlePath)
+	a.WriteLn("")
+
+	return nil
+}
+
+// writeDiagnosticsZip writes the entries of a diagnostics bundle
+// as zip archive to w
+func writeDiagnosticsZip(w io.Writer, entries []*diagnosticsEntry, modified time.Time) error {
+	zw := zip.NewWriter(w)
+
+	for _, entry := range entries {
+		fw, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     entry.Name,
+			Method:   zip.Deflate,
+			Modified: modified,
+		})
+		if err != nil {
+			return err
+		}
+
+		if _, err := fw.Write(entry.Data); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
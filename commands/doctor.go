@@ -22,20 +22,70 @@
 package commands
 
 import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"regexp"
 	"runtime"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
+	"unicode/utf16"
 
 	"github.com/mkloubert/autark/app"
 	"github.com/mkloubert/autark/utils"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 // DoctorOptions contains options for the doctor command
 type DoctorOptions struct {
 	Repair bool
+	// DockerVersion is the version of docker-ce, which should be
+	// installed instead of the latest one
+	DockerVersion string
+	// Bundle is the path of a zip file, where a diagnostics
+	// bundle should be written to
+	Bundle string
+	// CheckCompose indicates if it should be checked, that
+	// Docker Compose can parse the compose file
+	CheckCompose bool
+	// ComposeFile is the compose file of CheckCompose,
+	// which is discovered, if empty
+	ComposeFile string
+	// CheckGitConnectivity indicates if it should be checked,
+	// that git can reach GitTestURL
+	CheckGitConnectivity bool
+	// GitTestURL is the repository used by the git connectivity check
+	GitTestURL string
+	// PkgArgs contains extra arguments, which are appended
+	// to the install commands of the package manager
+	PkgArgs []string
+	// SkipDaemonStart indicates that --repair should not start
+	// the docker daemon, because it is managed externally
+	SkipDaemonStart bool
+	// JSON indicates that the commands run by --repair
+	// should be written as JSON
+	JSON bool
+	// Skip contains the names of the checks, which are
+	// not run, shown, counted and repaired
+	Skip []string
+	// SummaryOnly indicates that only the final verdict
+	// should be written instead of the result of each check
+	SummaryOnly bool
+	// RegistryName is the name of the registry container,
+	// which is checked
+	RegistryName string
 }
 
 // DoctorResult contains the result of a tool check
@@ -44,6 +94,515 @@ type DoctorResult struct {
 	Installed bool
 	Version   string
 	Error     error
+	// Optional indicates that a failed check is only reported
+	// as warning and does not count as issue
+	Optional bool
+}
+
+// aptPackagePolicy contains the relevant information of the output
+// of "apt-cache policy" for a single package
+type aptPackagePolicy struct {
+	// Installed is the installed version or empty if not installed
+	Installed string
+	// Sources contains the sources of the installed version
+	Sources []string
+}
+
+// dockerDaemonConfig contains the registry settings
+// of the daemon.json file of the docker daemon
+type dockerDaemonConfig struct {
+	// RegistryMirrors contains the mirrors of Docker Hub
+	RegistryMirrors []string `json:"registry-mirrors"`
+	// InsecureRegistries contains the registries, which are used
+	// via HTTP, like "192.168.1.20:5000" or "10.0.0.0/8"
+	InsecureRegistries []string `json:"insecure-registries"`
+}
+
+// doctorCheck contains the definition of a check of doctor
+type doctorCheck struct {
+	// name is the name of the result of the check,
+	// which is also used by --skip
+	name string
+	// run runs the check and returns its result or nil,
+	// if the check does not apply to this system
+	run func(r *doctorRun) *DoctorResult
+}
+
+// doctorRun contains the state of a run of the checks of doctor
+type doctorRun struct {
+	a        *app.AppContext
+	opts     *DoctorOptions
+	hostname string
+	checks   []doctorCheck
+	// results contains the results of the checks, which have been run,
+	// by their names, including skipped checks, which other checks need
+	results map[string]*DoctorResult
+}
+
+// needsRepair checks if the check with name has failed
+// and has not been skipped with --skip
+func (r *doctorRun) needsRepair(name string) bool {
+	result := r.results[name]
+	return result != nil && !result.Installed && !isDoctorCheckSkipped(r.opts.Skip, name)
+}
+
+// result returns the result of the check with name, which is run
+// once, even if it has been skipped, because other checks need it
+func (r *doctorRun) result(name string) *DoctorResult {
+	if result, ok := r.results[name]; ok {
+		return result
+	}
+
+	i := slices.IndexFunc(r.checks, func(check doctorCheck) bool {
+		return check.name == name
+	})
+	result := r.checks[i].run(r)
+	r.results[name] = result
+	return result
+}
+
+// mountEntry contains the fields of a line of /proc/mounts
+type mountEntry struct {
+	// MountPoint is the directory, like "/var/lib/docker"
+	MountPoint string
+	// FSType is the type of the filesystem, like "ext4"
+	FSType string
+	// Options contains the mount options, like "ro" or "rw"
+	Options []string
+}
+
+// wslStatus contains the information of the output of "wsl --status"
+type wslStatus struct {
+	// Installed indicates if WSL is installed
+	Installed bool
+	// DefaultVersion is the default WSL version, like "2"
+	DefaultVersion string
+}
+
+// hyperVInfo contains the Hyper-V information of the output of "systeminfo"
+type hyperVInfo struct {
+	// HypervisorDetected indicates if a hypervisor, like Hyper-V, is running
+	HypervisorDetected bool
+	// VirtualizationEnabled indicates if virtualization is enabled in the firmware
+	VirtualizationEnabled bool
+}
+
+// wslDefaultVersionRegex matches the line with the default version
+// in the output of "wsl --status", like "Default Version: 2"
+var wslDefaultVersionRegex = regexp.MustCompile(`(?im)version\s*:\s*(\d+)\s*$`)
+
+// linuxDockerInstallers contains the functions, which install docker
+// on specific Linux distributions
+var linuxDockerInstallers = map[utils.LinuxDistro]func(a *app.AppContext, opts *DoctorOptions) error{
+	utils.DistroDebian:   installDockerDebian,
+	utils.DistroUbuntu:   installDockerDebian,
+	utils.DistroFedora:   installDockerFedora,
+	utils.DistroRHEL:     installDockerFedora,
+	utils.DistroCentOS:   installDockerFedora,
+	utils.DistroArch:     installDockerArch,
+	utils.DistroAlpine:   installDockerAlpine,
+	utils.DistroOpenSUSE: installDockerOpenSUSE,
+	utils.DistroGentoo:   installDockerGentoo,
+	utils.DistroVoid:     installDockerVoid,
+}
+
+const (
+	// dockerAptKeyringFile is the file of the GPG key of the apt repository of Docker
+	dockerAptKeyringFile = "/etc/apt/keyrings/docker.asc"
+	// dockerAptSourcesFile is the file of the apt repository of Docker
+	dockerAptSourcesFile = "/etc/apt/sources.list.d/docker.list"
+)
+
+const (
+	// dockerProviderColima is the name of Colima as docker provider on macOS
+	dockerProviderColima = "Colima"
+	// dockerProviderDesktop is the name of Docker Desktop as docker provider
+	dockerProviderDesktop = "Docker Desktop"
+	// dockerDesktopAppPath is the path of the app of Docker Desktop on macOS
+	dockerDesktopAppPath = "/Applications/Docker.app"
+)
+
+const (
+	// dockerDesktopStartInterval is the time between two checks,
+	// if Docker Desktop is ready
+	dockerDesktopStartInterval = 2 * time.Second
+	// dockerDesktopStartTimeout is the maximum time to wait
+	// for Docker Desktop after launching it
+	dockerDesktopStartTimeout = 2 * time.Minute
+)
+
+const (
+	// aptLockRetryInterval is the time between two attempts of an
+	// apt command, which could not get the dpkg lock
+	aptLockRetryInterval = 5 * time.Second
+	// aptLockTimeout is the maximum time to wait for the dpkg lock
+	aptLockTimeout = 5 * time.Minute
+)
+
+// aptLockErrors contains the messages of apt and dpkg, if the lock
+// is held by another process, like unattended-upgrades; the paths of
+// the lock files are not translated, so they also match on systems
+// with another language
+var aptLockErrors = []string{
+	"Could not get lock",
+	"Unable to acquire the dpkg frontend lock",
+	"Unable to lock directory",
+	"dpkg frontend lock is locked by another process",
+	"/var/lib/dpkg/lock-frontend",
+	"/var/lib/dpkg/lock",
+	"/var/lib/apt/lists/lock",
+}
+
+// dockerVersionPinning describes how a package manager installs
+// a specific version of a package
+type dockerVersionPinning struct {
+	// Pattern matches valid versions
+	Pattern *regexp.Regexp
+	// Separator is written between package name and version
+	Separator string
+}
+
+// dockerVersionPinnings contains the package managers, which support
+// installing a specific version of docker-ce
+var dockerVersionPinnings = map[utils.PackageManager]dockerVersionPinning{
+	utils.PkgMgrApt: {
+		Pattern:   regexp.MustCompile(`^(\d+:)?\d[A-Za-z0-9.+~-]*$`),
+		Separator: "=",
+	},
+	utils.PkgMgrDnf: {
+		Pattern:   regexp.MustCompile(`^(\d+:)?\d[A-Za-z0-9._+~^-]*$`),
+		Separator: "-",
+	},
+}
+
+// caBundlePaths contains the paths of the system CA bundle
+// for each family of Linux distributions
+var caBundlePaths = map[utils.LinuxDistro][]string{
+	utils.DistroDebian:   {"/etc/ssl/certs/ca-certificates.crt"},
+	utils.DistroUbuntu:   {"/etc/ssl/certs/ca-certificates.crt"},
+	utils.DistroFedora:   {"/etc/pki/tls/certs/ca-bundle.crt", "/etc/pki/ca-trust/extracted/pem/tls-ca-bundle.pem"},
+	utils.DistroRHEL:     {"/etc/pki/tls/certs/ca-bundle.crt", "/etc/pki/ca-trust/extracted/pem/tls-ca-bundle.pem"},
+	utils.DistroCentOS:   {"/etc/pki/tls/certs/ca-bundle.crt", "/etc/pki/ca-trust/extracted/pem/tls-ca-bundle.pem"},
+	utils.DistroArch:     {"/etc/ssl/certs/ca-certificates.crt"},
+	utils.DistroAlpine:   {"/etc/ssl/certs/ca-certificates.crt"},
+	utils.DistroOpenSUSE: {"/etc/ssl/ca-bundle.pem", "/var/lib/ca-certificates/ca-bundle.pem"},
+	utils.DistroGentoo:   {"/etc/ssl/certs/ca-certificates.crt"},
+	utils.DistroVoid:     {"/etc/ssl/certs/ca-certificates.crt"},
+}
+
+const (
+	// defaultGitTestURL is the default repository of the git connectivity check
+	defaultGitTestURL = "https://github.com/git/git.git"
+	// gitConnectivityTimeout is the maximum time of the git connectivity check
+	gitConnectivityTimeout = 30 * time.Second
+)
+
+// gitErrorClasses contains the messages of git and its TLS libraries,
+// which identify the class of a connection error, in order of precedence
+var gitErrorClasses = []struct {
+	Class    string
+	Messages []string
+}{
+	{"DNS", []string{"Could not resolve host", "Name or service not known", "Temporary failure in name resolution", "nodename nor servname provided"}},
+	{"TLS", []string{"SSL certificate problem", "server certificate verification failed", "SSL_ERROR", "gnutls_handshake", "schannel", "SSL routines"}},
+	{"auth", []string{"Authentication failed", "could not read Username", "terminal prompts disabled", "Permission denied (publickey)", "returned error: 401", "returned error: 403"}},
+	{"network", []string{"Failed to connect", "Could not connect", "Connection refused", "Connection timed out", "Network is unreachable", "Operation timed out"}},
+}
+
+const (
+	// defaultDockerDataRoot is the data root of docker, if
+	// it cannot be determined with "docker info"
+	defaultDockerDataRoot = "/var/lib/docker"
+	// mountsPath is the file, which lists the mounted filesystems
+	mountsPath = "/proc/mounts"
+)
+
+// problematicFilesystems contains the filesystems, which are known to
+// cause problems as data root of docker with the overlay2 storage driver
+var problematicFilesystems = map[string]string{
+	"btrfs":    "overlay2 is not supported on btrfs without the btrfs storage driver",
+	"cifs":     "network filesystems are not supported by overlay2",
+	"ecryptfs": "overlay2 is not supported on ecryptfs",
+	"nfs":      "network filesystems are not supported by overlay2",
+	"nfs4":     "network filesystems are not supported by overlay2",
+	"smb3":     "network filesystems are not supported by overlay2",
+	"tmpfs":    "data is lost on reboot",
+	"zfs":      "overlay2 is not supported on zfs without the zfs storage driver",
+}
+
+const (
+	// resolvConfPath is the file, which configures the DNS resolvers
+	resolvConfPath = "/etc/resolv.conf"
+	// systemdResolvConfPath is the file, which contains the upstream
+	// DNS resolvers of systemd-resolved
+	systemdResolvConfPath = "/run/systemd/resolve/resolv.conf"
+	// systemdResolvedStub is the address of the local stub
+	// resolver of systemd-resolved
+	systemdResolvedStub = "127.0.0.53"
+)
+
+// cpuInfoPath is the file, which contains the
+// information of the CPUs of the Linux kernel
+const cpuInfoPath = "/proc/cpuinfo"
+
+// cpuVirtualizationFlags contains the flags of /proc/cpuinfo,
+// which indicate hardware virtualization, with their names
+var cpuVirtualizationFlags = map[string]string{
+	"vmx": "Intel VT-x",
+	"svm": "AMD-V",
+}
+
+// entropyAvailPath is the file, which contains the
+// available entropy of the Linux kernel
+const entropyAvailPath = "/proc/sys/kernel/random/entropy_avail"
+
+// minEntropy is the minimum available entropy, which is required to
+// generate keys and certificates without stalling
+//
+// Kernels since 5.18 always report 256
+const minEntropy = 200
+
+// dockerDaemonConfigPath is the config file of the docker daemon on Linux
+const dockerDaemonConfigPath = "/etc/docker/daemon.json"
+
+const (
+	// swapsPath is the file, which lists the active swap areas
+	swapsPath = "/proc/swaps"
+	// swappinessPath is the file, which contains the swappiness
+	// of the Linux kernel
+	swappinessPath = "/proc/sys/vm/swappiness"
+)
+
+const (
+	// appArmorEnabledPath is the file, which contains "Y",
+	// if AppArmor is enabled in the Linux kernel
+	appArmorEnabledPath = "/sys/module/apparmor/parameters/enabled"
+	// dockerAppArmorProfile is the AppArmor profile, which is
+	// loaded by the docker daemon and used for all containers
+	dockerAppArmorProfile = "docker-default"
+)
+
+const (
+	// defaultRegistryPort is the port of the registry, which is
+	// checked, if the registry container is not running
+	defaultRegistryPort = 5000
+	// registryAPIVersionHeader is the header, which is sent with
+	// every response of the API of a Docker registry
+	registryAPIVersionHeader = "Docker-Distribution-Api-Version"
+)
+
+// aaStatusModeRegex matches the headers of the profile lists in the
+// output of "aa-status", like "12 profiles are in enforce mode."
+var aaStatusModeRegex = regexp.MustCompile(`^\d+ profiles are in (\S+) mode\.?$`)
+
+// dockerHubRegistryKeys contains the keys, which are used for
+// Docker Hub in the "auths" and "credHelpers" of a docker config.json
+var dockerHubRegistryKeys = []string{
+	"https://index.docker.io/v1/",
+	"https://index.docker.io/v1",
+	"index.docker.io",
+	"docker.io",
+	"registry-1.docker.io",
+}
+
+// errRebootRequired is returned by repairs, which have installed
+// packages into a new snapshot of an immutable system
+var errRebootRequired = errors.New("a reboot is required to activate the installed packages")
+
+// kernelModules contains the kernel modules required
+// by Docker networking and overlay storage
+var kernelModules = []string{"overlay", "bridge", "br_netfilter"}
+
+// zoneinfoPath is the directory of the time zone database,
+// which is provided by the tzdata package
+const zoneinfoPath = "/usr/share/zoneinfo"
+
+// checkAppArmor checks if the docker-default profile is loaded,
+// if AppArmor is enabled, because containers may fail to start
+// without it
+func checkAppArmor(dockerResult *DoctorResult) *DoctorResult {
+	result := &DoctorResult{
+		Name:      "apparmor",
+		Installed: false,
+		Optional:  true,
+	}
+
+	enabled, err := os.ReadFile(appArmorEnabledPath)
+	if err != nil || strings.TrimSpace(string(enabled)) != "Y" {
+		result.Installed = true
+		result.Version = "not enabled"
+		return result
+	}
+
+	// If docker is not installed, profile check is not applicable
+	if !dockerResult.Installed {
+		result.Error = fmt.Errorf("docker not installed")
+		return result
+	}
+
+	if !utils.CommandExists("aa-status") {
+		result.Error = fmt.Errorf("AppArmor is enabled, but aa-status is not installed to check the %s profile", dockerAppArmorProfile)
+		return result
+	}
+
+	output, err := utils.RunCommand("aa-status")
+	if err != nil {
+		if utils.IsRoot() {
+			result.Error = fmt.Errorf("could not check the %s profile: %w", dockerAppArmorProfile, newInstallCommandError("aa-status", err, output))
+		} else {
+			result.Error = fmt.Errorf("could not check the %s profile, aa-status requires root privileges", dockerAppArmorProfile)
+		}
+		return result
+	}
+
+	mode, ok := parseAaStatus(string(output))[dockerAppArmorProfile]
+	if !ok {
+		result.Error = fmt.Errorf("AppArmor is enabled, but the %s profile is not loaded, containers may fail to start, restart docker to load it", dockerAppArmorProfile)
+		return result
+	}
+
+	result.Installed = true
+	result.Version = fmt.Sprintf("%s (%s)", dockerAppArmorProfile, mode)
+	return result
+}
+
+// checkBuildKit checks if "docker buildx" is available and
+// BuildKit is not disabled by DOCKER_BUILDKIT
+func checkBuildKit(dockerResult *DoctorResult) *DoctorResult {
+	result := &DoctorResult{
+		Name:      "buildkit",
+		Installed: false,
+		Optional:  true,
+	}
+
+	// If docker is not installed, BuildKit check is not applicable
+	if !dockerResult.Installed {
+		result.Error = fmt.Errorf("docker not installed")
+		return result
+	}
+
+	output, err := utils.CommandVersion("docker", "buildx", "version")
+	if err != nil {
+		result.Error = fmt.Errorf("docker buildx not available, builds of compose stacks may fail")
+		return result
+	}
+
+	version := parseBuildxVersion(output)
+	if version == "" {
+		version = "unknown version"
+	}
+
+	if isBuildKitDisabled(os.Getenv("DOCKER_BUILDKIT")) {
+		result.Error = fmt.Errorf("buildx %s, but disabled by DOCKER_BUILDKIT", version)
+		return result
+	}
+
+	result.Installed = true
+	result.Version = fmt.Sprintf("buildx %s", version)
+	return result
+}
+
+// checkCACertificates reports, if the system CA bundle of a
+// Linux distribution exists, which is required for TLS connections
+func checkCACertificates(distro utils.LinuxDistro) *DoctorResult {
+	result := &DoctorResult{
+		Name:      "ca-certificates",
+		Installed: false,
+	}
+
+	paths := getCABundlePaths(distro)
+	for _, path := range paths {
+		if _, err := os.Stat(path); err == nil {
+			result.Installed = true
+			result.Version = path
+			return result
+		}
+	}
+
+	result.Error = fmt.Errorf("no CA bundle found (%s)", strings.Join(paths, ", "))
+	return result
+}
+
+// checkDNSResolvers reports the DNS resolvers of the host, which are
+// also used by containers, where the stub resolver of systemd-resolved
+// is replaced by its upstream resolvers
+func checkDNSResolvers() *DoctorResult {
+	result := &DoctorResult{
+		Name:      "dns resolvers",
+		Installed: false,
+		Optional:  true,
+	}
+
+	data, err := os.ReadFile(resolvConfPath)
+	if err != nil {
+		result.Error = fmt.Errorf("could not read %s: %w", resolvConfPath, err)
+		return result
+	}
+
+	nameservers := parseResolvConf(string(data))
+	if slices.Contains(nameservers, systemdResolvedStub) {
+		if upstreamData, err := os.ReadFile(systemdResolvConfPath); err == nil {
+			if upstream := parseResolvConf(string(upstreamData)); len(upstream) > 0 {
+				nameservers = upstream
+			}
+		}
+	}
+
+	if len(nameservers) == 0 {
+		result.Error = fmt.Errorf("no nameserver in %s", resolvConfPath)
+		return result
+	}
+
+	result.Installed = true
+	result.Version = strings.Join(nameservers, ", ")
+	return result
+}
+
+// checkComposeConfig checks if Docker Compose can parse a compose file
+// with "docker compose config -q" and reports the error line otherwise
+func checkComposeConfig(dockerResult *DoctorResult, composeFile string, workDir string) *DoctorResult {
+	result := &DoctorResult{
+		Name:      "compose file",
+		Installed: false,
+	}
+
+	// If docker is not installed, compose check is not applicable
+	if !dockerResult.Installed {
+		result.Error = fmt.Errorf("docker not installed")
+		return result
+	}
+
+	cmd := exec.Command("docker", "compose", "--file", composeFile, "config", "-q")
+	cmd.Dir = workDir
+	cmd.Env = utils.ParsedCommandEnv()
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		result.Error = fmt.Errorf("%s is invalid: %s", composeFile, getComposeConfigError(string(output), err))
+		return result
+	}
+
+	result.Installed = true
+	result.Version = composeFile
+	return result
+}
+
+// checkComposeProject checks the compose file of --file or the
+// discovered one, which is resolved against --work-dir
+func checkComposeProject(a *app.AppContext, opts *DoctorOptions, dockerResult *DoctorResult) *DoctorResult {
+	workDir := a.Config().WorkDir
+
+	composeFile, err := utils.ExpandPath(opts.ComposeFile)
+	if err == nil && composeFile == "" {
+		composeFile, err = discoverComposeFile(workDir, fileExists)
+	}
+	if err != nil {
+		return &DoctorResult{
+			Name:  "compose file",
+			Error: err,
+		}
+	}
+
+	return checkComposeConfig(dockerResult, a.ResolvePath(composeFile), workDir)
 }
 
 func checkDocker() *DoctorResult {
@@ -56,18 +615,18 @@ func checkDocker() *DoctorResult {
 		return result
 	}
 
-	output, err := utils.RunCommand("docker", "--version")
+	version, err := utils.CommandVersion("docker", "--version")
 	if err != nil {
 		result.Error = err
 		return result
 	}
 
 	result.Installed = true
-	result.Version = strings.TrimSpace(string(output))
+	result.Version = version
 	return result
 }
 
-func checkDockerDaemon(dockerResult *DoctorResult) *DoctorResult {
+func checkDockerDaemon(dockerResult *DoctorResult, provider string) *DoctorResult {
 	result := &DoctorResult{
 		Name:      "docker daemon",
 		Installed: false,
@@ -86,73 +645,1340 @@ func checkDockerDaemon(dockerResult *DoctorResult) *DoctorResult {
 		result.Error = fmt.Errorf("not running")
 	}
 
+	if provider != "" {
+		result.Version = strings.TrimSpace(fmt.Sprintf("%s (%s)", result.Version, provider))
+	}
+
 	return result
 }
 
-func checkGit() *DoctorResult {
+// checkDockerDaemonConfig reports the registry mirrors and insecure
+// registries of the daemon.json file and checks if registryAddress,
+// which is served via HTTP, is listed as insecure registry
+func checkDockerDaemonConfig(path string, registryAddress string) *DoctorResult {
 	result := &DoctorResult{
-		Name:      "git",
+		Name:      "docker daemon config",
+		Installed: false,
+		Optional:  true,
+	}
+
+	config := &dockerDaemonConfig{}
+
+	data, err := os.ReadFile(path)
+	if err == nil {
+		config, err = parseDockerDaemonConfig(data)
+		if err != nil {
+			result.Error = fmt.Errorf("invalid %s: %w", path, err)
+			return result
+		}
+	} else if !os.IsNotExist(err) {
+		result.Error = fmt.Errorf("could not read %s: %w", path, err)
+		return result
+	}
+
+	if registryAddress != "" && !isInsecureRegistryListed(registryAddress, config.InsecureRegistries) {
+		result.Error = fmt.Errorf(`registry %s uses HTTP, but is not in "insecure-registries" of %s`, registryAddress, path)
+		return result
+	}
+
+	mirrors := "none"
+	if len(config.RegistryMirrors) > 0 {
+		mirrors = strings.Join(config.RegistryMirrors, ", ")
+	}
+	insecure := "none"
+	if len(config.InsecureRegistries) > 0 {
+		insecure = strings.Join(config.InsecureRegistries, ", ")
+	}
+
+	result.Installed = true
+	result.Version = fmt.Sprintf("mirrors: %s; insecure: %s", mirrors, insecure)
+	return result
+}
+
+// checkDockerContext reports the active docker context and its endpoint
+// and warns, if it is a remote one, because the registry and port
+// handling of autark assumes a local daemon
+func checkDockerContext(dockerResult *DoctorResult) *DoctorResult {
+	result := &DoctorResult{
+		Name:      "docker context",
+		Installed: false,
+		Optional:  true,
+	}
+
+	// If docker is not installed, context check is not applicable
+	if !dockerResult.Installed {
+		result.Error = fmt.Errorf("docker not installed")
+		return result
+	}
+
+	output, err := utils.RunCommand("docker", "context", "inspect", "--format", "{{.Name}} {{.Endpoints.docker.Host}}")
+	if err != nil {
+		result.Error = newDockerError("context inspect", err, output)
+		return result
+	}
+
+	name, endpoint := parseDockerContext(string(output))
+	if name == "" {
+		result.Error = fmt.Errorf("could not determine the active docker context")
+		return result
+	}
+
+	if isRemoteDockerEndpoint(endpoint) {
+		result.Error = fmt.Errorf("%s points to the remote daemon %s, but autark assumes a local one", name, utils.RedactValue(endpoint))
+		return result
+	}
+
+	result.Installed = true
+	result.Version = name
+	if endpoint != "" {
+		result.Version = fmt.Sprintf("%s (%s)", name, endpoint)
+	}
+	return result
+}
+
+// checkDockerDataRootFilesystem reports the filesystem of the data
+// root of docker, which must work with overlay2 (optional)
+func checkDockerDataRootFilesystem(dockerResult *DoctorResult) *DoctorResult {
+	result := &DoctorResult{
+		Name:      "docker data root",
+		Installed: false,
+		Optional:  true,
+	}
+
+	dataRoot := defaultDockerDataRoot
+	if dockerResult.Installed {
+		if output, err := utils.RunCommand("docker", "info", "--format", "{{.DockerRootDir}}"); err == nil {
+			if dir := strings.TrimSpace(string(output)); dir != "" {
+				dataRoot = dir
+			}
+		}
+	}
+
+	mounts, err := os.ReadFile(mountsPath)
+	if err != nil {
+		result.Error = fmt.Errorf("could not read %s: %w", mountsPath, err)
+		return result
+	}
+
+	mount := findMount(string(mounts), dataRoot)
+	if mount == nil {
+		result.Error = fmt.Errorf("could not determine filesystem of %s", dataRoot)
+		return result
+	}
+	mountPoint, fsType := mount.MountPoint, mount.FSType
+
+	status := fmt.Sprintf("%s on %s (%s)", dataRoot, fsType, mountPoint)
+	if reason := getFilesystemProblem(fsType); reason != "" {
+		result.Error = fmt.Errorf("%s: %s", status, reason)
+		return result
+	}
+
+	result.Installed = true
+	result.Version = status
+	return result
+}
+
+// checkDockerHubLogin checks if docker is logged in to Docker Hub,
+// because anonymous pulls, like the one of the registry image, are
+// limited per IP address and time period
+func checkDockerHubLogin(dockerResult *DoctorResult) *DoctorResult {
+	result := &DoctorResult{
+		Name:      "docker hub login",
 		Installed: false,
+		Optional:  true,
+	}
+
+	// If docker is not installed, login check is not applicable
+	if !dockerResult.Installed {
+		result.Error = fmt.Errorf("docker not installed")
+		return result
+	}
+
+	configPath, err := getDockerConfigPath()
+	if err != nil {
+		result.Error = err
+		return result
+	}
+
+	loggedIn := false
+	if data, err := os.ReadFile(configPath); err == nil {
+		loggedIn, err = isDockerHubLoggedIn(data)
+		if err != nil {
+			result.Error = fmt.Errorf("invalid %s: %w", configPath, err)
+			return result
+		}
+	} else if !os.IsNotExist(err) {
+		result.Error = fmt.Errorf("failed to read %s: %w", configPath, err)
+		return result
+	}
+
+	if !loggedIn {
+		result.Error = fmt.Errorf("not logged in, anonymous pulls from Docker Hub are rate-limited, run 'docker login' to raise the limit")
+		return result
+	}
+
+	result.Installed = true
+	result.Version = "logged in"
+	return result
+}
+
+// checkDockerPackage reports the apt package of docker, like docker-ce
+// or docker.io, with its version and the sources it comes from
+func checkDockerPackage(dockerResult *DoctorResult) *DoctorResult {
+	result := &DoctorResult{
+		Name:      "docker package",
+		Installed: true,
+		Optional:  true,
+	}
+
+	if !dockerResult.Installed {
+		result.Version = "docker not installed"
+		return result
+	}
+
+	for _, pkg := range []string{"docker-ce", "docker.io"} {
+		output, err := utils.RunCommand("apt-cache", "policy", pkg)
+		if err != nil {
+			continue
+		}
+
+		policy := parseAptCachePolicy(string(output))
+		if policy.Installed == "" {
+			continue
+		}
+
+		result.Version = fmt.Sprintf("%s %s (%s)", pkg, policy.Installed, describeAptPackageSources(policy.Sources))
+		return result
+	}
+
+	result.Version = "not installed via apt"
+	return result
+}
+
+// checkEntropy reports, if the kernel has enough entropy
+// available, so that keys and certificates can be generated
+// without stalling (optional)
+func checkEntropy() *DoctorResult {
+	result := &DoctorResult{
+		Name:      "entropy",
+		Installed: false,
+		Optional:  true,
+	}
+
+	data, err := os.ReadFile(entropyAvailPath)
+	if err != nil {
+		result.Error = fmt.Errorf("could not read %s: %w", entropyAvailPath, err)
+		return result
+	}
+
+	entropy, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		result.Error = fmt.Errorf("invalid value in %s: %w", entropyAvailPath, err)
+		return result
+	}
+
+	result.Version = strconv.Itoa(entropy)
+	if isEntropyLow(entropy) {
+		result.Error = fmt.Errorf("%d is below %d, generating keys and certificates may stall (install haveged or rng-tools)", entropy, minEntropy)
+	} else {
+		result.Installed = true
+	}
+
+	return result
+}
+
+// checkHardwareVirtualization reports, if hardware virtualization
+// is enabled in the firmware, which is required to run VMs besides
+// the containers (informational)
+func checkHardwareVirtualization() *DoctorResult {
+	result := &DoctorResult{
+		Name:      "hardware virtualization",
+		Installed: true,
+		Optional:  true,
+	}
+
+	data, err := os.ReadFile(cpuInfoPath)
+	if err != nil {
+		result.Version = fmt.Sprintf("unknown (could not read %s)", cpuInfoPath)
+		return result
+	}
+
+	flag := parseCPUInfoVirtualizationFlag(string(data))
+	if flag == "" {
+		result.Version = "not available (no vmx or svm flag, disabled in the firmware or not passed to this VM)"
+		return result
+	}
+
+	result.Version = fmt.Sprintf("%s (%s)", cpuVirtualizationFlags[flag], flag)
+	return result
+}
+
+// checkExecutableDirectory warns, if the directory of the running
+// binary is world-writable, like /tmp, because other users could
+// replace it, before it is run as root, like with --repair
+func checkExecutableDirectory() *DoctorResult {
+	result := &DoctorResult{
+		Name:      "executable location",
+		Installed: false,
+		Optional:  true,
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		result.Error = fmt.Errorf("could not determine the path of autark: %w", err)
+		return result
+	}
+	if resolved, err := filepath.EvalSymlinks(executable); err == nil {
+		executable = resolved
+	}
+
+	dir := filepath.Dir(executable)
+	info, err := os.Stat(dir)
+	if err != nil {
+		result.Error = fmt.Errorf("could not read %s: %w", dir, err)
+		return result
+	}
+
+	if isWorldWritable(info.Mode()) {
+		result.Error = fmt.Errorf("%s is world-writable, other users could replace autark before it runs as root, please move it to a directory like /usr/local/bin", dir)
+		return result
+	}
+
+	result.Installed = true
+	result.Version = dir
+	return result
+}
+
+// checkGitConnectivity checks if git can reach a remote repository
+// and reports the class of the error, if not (optional)
+func checkGitConnectivity(gitResult *DoctorResult, testURL string) *DoctorResult {
+	result := &DoctorResult{
+		Name:      "git connectivity",
+		Installed: false,
+		Optional:  true,
+	}
+
+	if !gitResult.Installed {
+		result.Error = fmt.Errorf("git not installed")
+		return result
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), gitConnectivityTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", "ls-remote", "--heads", testURL)
+	cmd.Env = utils.ParsedCommandEnv("GIT_TERMINAL_PROMPT=0")
+
+	output, err := cmd.CombinedOutput()
+	if ctx.Err() != nil {
+		result.Error = fmt.Errorf("%s not reachable (timeout after %s)", utils.RedactValue(testURL), gitConnectivityTimeout)
+		return result
+	}
+	if err != nil {
+		result.Error = fmt.Errorf("%s not reachable (%s error): %s", utils.RedactValue(testURL), classifyGitError(string(output)), strings.TrimSpace(string(output)))
+		return result
+	}
+
+	result.Installed = true
+	result.Version = fmt.Sprintf("%s reachable", utils.RedactValue(testURL))
+	return result
+}
+
+// checkHostnameResolution checks if the hostname of this host can be
+// resolved, which is required by docker and many stacks
+func checkHostnameResolution(hostname string, lookupHost func(host string) ([]string, error)) *DoctorResult {
+	result := &DoctorResult{
+		Name:      "hostname resolution",
+		Installed: false,
+		Optional:  true,
+	}
+
+	addrs, err := lookupHost(hostname)
+	if err != nil || len(addrs) == 0 {
+		result.Error = fmt.Errorf("%s cannot be resolved, add it to %s", hostname, utils.HostsFilePath())
+		return result
+	}
+
+	result.Installed = true
+	result.Version = fmt.Sprintf("%s -> %s", hostname, strings.Join(addrs, ", "))
+	return result
+}
+
+func checkGit() *DoctorResult {
+	result := &DoctorResult{
+		Name:      "git",
+		Installed: false,
+	}
+
+	if !utils.CommandExists("git") {
+		return result
+	}
+
+	version, err := utils.CommandVersion("git", "--version")
+	if err != nil {
+		result.Error = err
+		return result
+	}
+
+	result.Installed = true
+	result.Version = version
+	return result
+}
+
+// checkIptablesBackend reports the backend of iptables, which is
+// nf_tables or legacy, and if the other backend contains rules too
+func checkIptablesBackend(dockerResult *DoctorResult) *DoctorResult {
+	result := &DoctorResult{
+		Name:      "iptables backend",
+		Installed: false,
+		Optional:  true,
+	}
+
+	if !utils.CommandExists("iptables") {
+		result.Error = fmt.Errorf("iptables not found")
+		return result
+	}
+
+	output, err := utils.CommandVersion("iptables", "--version")
+	if err != nil {
+		result.Error = err
+		return result
+	}
+
+	backend := parseIptablesBackend(output)
+	if backend == "" {
+		result.Error = fmt.Errorf("unknown backend: %s", output)
+		return result
+	}
+
+	// Docker only writes its rules to one backend, rules in the
+	// other one are evaluated too and can break container networking
+	otherSaveCommand := "iptables-legacy-save"
+	if backend == "legacy" {
+		otherSaveCommand = "iptables-nft-save"
+	}
+
+	if dockerResult.Installed && utils.CommandExists(otherSaveCommand) {
+		otherOutput, err := utils.RunCommand(otherSaveCommand)
+		if err == nil && hasIptablesRules(string(otherOutput)) {
+			result.Error = fmt.Errorf("%s, but %s contains rules too, mixing both backends can break Docker networking", backend, otherSaveCommand)
+			return result
+		}
+	}
+
+	result.Installed = true
+	result.Version = backend
+	return result
+}
+
+// checkKernelModules checks if the kernel modules, which are
+// required by the networking of docker, are loaded or built in
+func checkKernelModules() *DoctorResult {
+	result := &DoctorResult{
+		Name:      "kernel modules",
+		Installed: false,
+		Optional:  true,
+	}
+
+	statuses, _ := getKernelModuleStatuses()
+
+	parts := make([]string, 0, len(kernelModules))
+	allLoaded := true
+	for _, module := range kernelModules {
+		parts = append(parts, fmt.Sprintf("%s: %s", module, statuses[module]))
+		if statuses[module] != "loaded" {
+			allLoaded = false
+		}
+	}
+
+	status := strings.Join(parts, ", ")
+	if allLoaded {
+		result.Installed = true
+		result.Version = status
+	} else {
+		result.Error = fmt.Errorf("%s", status)
+	}
+
+	return result
+}
+
+// checkRegistryPort checks if the port of the registry is served by
+// a Docker registry and not by another service, which has bound it
+// before, like the AirPlay Receiver of macOS on port 5000
+func checkRegistryPort(dockerDaemonResult *DoctorResult, name string) *DoctorResult {
+	result := &DoctorResult{
+		Name:      "registry port",
+		Installed: true,
+		Optional:  true,
+	}
+
+	port := defaultRegistryPort
+	scheme := "http"
+
+	running := false
+	if dockerDaemonResult.Installed {
+		running, _ = checkRegistryRunning(name)
+	}
+
+	if running {
+		config, err := inspectRegistryContainer(name)
+		if err != nil {
+			result.Installed = false
+			result.Error = err
+			return result
+		}
+		if config.Port == 0 {
+			result.Version = "port of registry unknown"
+			return result
+		}
+
+		port = config.Port
+		if _, ok := config.Env["REGISTRY_HTTP_TLS_CERTIFICATE"]; ok {
+			scheme = "https"
+		}
+	} else if isTCPPortAvailable(port) {
+		result.Version = "registry not set up"
+		return result
+	}
+
+	client := &http.Client{
+		Timeout: registryCheckTimeout,
+		Transport: &http.Transport{
+			// only the header is checked and a self-signed
+			// certificate is not trusted on this host necessarily
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+	}
+
+	return checkRegistryPortIdentity(result, client, fmt.Sprintf("%s://localhost:%d", scheme, port), port)
+}
+
+// checkRegistryPortIdentity updates result with the outcome of
+// verifyRegistryIdentity for the registry on baseURL, which is
+// published on port
+func checkRegistryPortIdentity(result *DoctorResult, client *http.Client, baseURL string, port int) *DoctorResult {
+	if err := verifyRegistryIdentity(client, baseURL); err != nil {
+		result.Installed = false
+		result.Error = fmt.Errorf("port %d: %w", port, err)
+		return result
+	}
+
+	result.Version = fmt.Sprintf("port %d", port)
+	return result
+}
+
+// checkSwap reports the size of all swap areas and the swappiness
+// of the kernel, which should not be an extreme value (optional)
+func checkSwap() *DoctorResult {
+	result := &DoctorResult{
+		Name:      "swap",
+		Installed: false,
+		Optional:  true,
+	}
+
+	swapsData, err := os.ReadFile(swapsPath)
+	if err != nil {
+		result.Error = fmt.Errorf("could not read %s: %w", swapsPath, err)
+		return result
+	}
+
+	swappinessData, err := os.ReadFile(swappinessPath)
+	if err != nil {
+		result.Error = fmt.Errorf("could not read %s: %w", swappinessPath, err)
+		return result
+	}
+
+	swapKB := parseProcSwaps(string(swapsData))
+	swappiness, err := parseSwappiness(string(swappinessData))
+	if err != nil {
+		result.Error = fmt.Errorf("invalid value in %s: %w", swappinessPath, err)
+		return result
+	}
+
+	status := fmt.Sprintf("%d MiB, swappiness: %d", swapKB/1024, swappiness)
+
+	switch {
+	case swapKB == 0:
+		result.Error = fmt.Errorf("no swap enabled, swappiness: %d", swappiness)
+	case swappiness == 0 || swappiness == 100:
+		result.Error = fmt.Errorf("%s is an extreme value", status)
+	default:
+		result.Installed = true
+		result.Version = status
+	}
+
+	return result
+}
+
+// checkTimeZoneData checks if the time zone database exists, which
+// is required by containers, that mount it or the TZ variable
+func checkTimeZoneData(path string) *DoctorResult {
+	result := &DoctorResult{
+		Name:      "tzdata",
+		Installed: false,
+		Optional:  true,
+	}
+
+	if !isZoneinfoDir(path) {
+		result.Error = fmt.Errorf("%s not found", path)
+		return result
+	}
+
+	result.Installed = true
+	result.Version = fmt.Sprintf("present (%s)", path)
+	return result
+}
+
+// checkVirtualization checks if WSL 2 or Hyper-V is available
+// on Windows, which is required by Docker Desktop
+func checkVirtualization() *DoctorResult {
+	result := &DoctorResult{
+		Name:      "WSL 2 / Hyper-V",
+		Installed: false,
+	}
+
+	if output, err := utils.RunCommand("wsl", "--status"); err == nil {
+		if status := parseWSLStatus(output); status.DefaultVersion == "2" {
+			result.Installed = true
+			result.Version = "WSL 2"
+			return result
+		}
+	}
+
+	output, err := utils.RunCommand("systeminfo")
+	if err != nil {
+		result.Error = fmt.Errorf("could not run systeminfo: %w", err)
+		return result
+	}
+
+	info := parseSysteminfoHyperV(string(output))
+	switch {
+	case info.HypervisorDetected:
+		result.Installed = true
+		result.Version = "Hyper-V"
+	case info.VirtualizationEnabled:
+		result.Error = fmt.Errorf("WSL 2 is not set up, run 'wsl --install' or 'wsl --set-default-version 2' and restart Windows")
+	default:
+		result.Error = fmt.Errorf("virtualization is not available, enable it in the BIOS/UEFI and run 'wsl --install'")
+	}
+
+	return result
+}
+
+// checkWritableFilesystem returns an error, if path is on a read-only
+// filesystem, like the root of an immutable system, so a config file
+// is not changed with a confusing error of the write
+//
+// Without /proc/mounts, like on macOS, nothing is checked
+func checkWritableFilesystem(path string) error {
+	return checkWritableFilesystemIn(mountsPath, path)
+}
+
+// checkWritableFilesystemIn returns an error, if path is on a
+// filesystem, which is mounted read-only by the mount table of
+// mountsFile, and nil, if mountsFile cannot be read
+func checkWritableFilesystemIn(mountsFile string, path string) error {
+	mounts, err := os.ReadFile(mountsFile)
+	if err != nil {
+		return nil
+	}
+
+	return getReadOnlyFilesystemError(string(mounts), path)
+}
+
+func checkRootPrivileges() *DoctorResult {
+	result := &DoctorResult{
+		Name:      "root/admin privileges",
+		Installed: false,
+	}
+
+	if utils.IsRoot() {
+		result.Installed = true
+		if runtime.GOOS == "windows" {
+			result.Version = "administrator"
+		} else {
+			result.Version = "root"
+		}
+	} else {
+		if runtime.GOOS == "windows" {
+			result.Error = fmt.Errorf("not running as administrator")
+		} else {
+			result.Error = fmt.Errorf("not running as root")
+		}
+	}
+
+	return result
+}
+
+// classifyGitError returns the class of a connection error from
+// the output of git, which is "DNS", "TLS", "auth", "network" or "unknown"
+func classifyGitError(output string) string {
+	lowerOutput := strings.ToLower(output)
+
+	for _, errorClass := range gitErrorClasses {
+		for _, msg := range errorClass.Messages {
+			if strings.Contains(lowerOutput, strings.ToLower(msg)) {
+				return errorClass.Class
+			}
+		}
+	}
+
+	return "unknown"
+}
+
+// findMount returns the mount from the content of /proc/mounts,
+// which contains a path, or nil, if there is none
+func findMount(mounts string, path string) *mountEntry {
+	path = filepath.Clean(path)
+
+	var mount *mountEntry
+	for _, line := range strings.Split(mounts, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+
+		// spaces and other special characters are escaped as octal values
+		mp := unescapeMountField(fields[1])
+
+		isParent := mp == "/" || path == mp || strings.HasPrefix(path, mp+"/")
+		// later mounts over the same mount point win
+		if isParent && (mount == nil || len(mp) >= len(mount.MountPoint)) {
+			mount = &mountEntry{
+				MountPoint: mp,
+				FSType:     fields[2],
+			}
+			if len(fields) > 3 {
+				mount.Options = strings.Split(fields[3], ",")
+			}
+		}
+	}
+
+	return mount
+}
+
+// decodeWSLOutput decodes the output of wsl.exe, which is
+// UTF-16LE, if it contains NUL bytes
+func decodeWSLOutput(output []byte) string {
+	if len(output) < 2 || len(output)%2 != 0 || !bytes.Contains(output, []byte{0}) {
+		return string(output)
+	}
+
+	units := make([]uint16, 0, len(output)/2)
+	for i := 0; i+1 < len(output); i += 2 {
+		units = append(units, uint16(output[i])|uint16(output[i+1])<<8)
+	}
+
+	return strings.TrimPrefix(string(utf16.Decode(units)), "\ufeff")
+}
+
+// describeAptPackageSources returns a readable description
+// of the sources of an installed apt package
+func describeAptPackageSources(sources []string) string {
+	for _, source := range sources {
+		switch {
+		case strings.Contains(source, "download.docker.com"):
+			return "Docker repository"
+		case strings.Contains(source, "esm.ubuntu.com"):
+			return "Ubuntu Pro/ESM"
+		}
+	}
+
+	for _, source := range sources {
+		if strings.Contains(source, "://") {
+			return "distribution repository"
+		}
+	}
+
+	return "local package"
+}
+
+func ensureDockerDaemonRunning(a *app.AppContext) error {
+	if isDockerDaemonRunning() {
+		a.D("Docker daemon is already running")
+		return nil
+	}
+
+	a.WriteLn("Docker daemon is not running. Attempting to start...")
+
+	if err := startDockerDaemon(a); err != nil {
+		return fmt.Errorf("failed to start docker daemon: %w", err)
+	}
+
+	// Verify daemon is now running
+	if !isDockerDaemonRunning() {
+		return fmt.Errorf("docker daemon failed to start")
+	}
+
+	a.WriteLn("Docker daemon started successfully.")
+	return nil
+}
+
+// countDoctorIssues returns the number of failed checks, which are
+// required, and the number of failed checks, which are optional
+func countDoctorIssues(results []*DoctorResult) (int, int) {
+	issues := 0
+	warnings := 0
+	for _, r := range results {
+		if !r.Installed {
+			if r.Optional {
+				warnings++
+			} else {
+				issues++
+			}
+		}
+	}
+
+	return issues, warnings
+}
+
+// getCABundlePaths returns the possible paths of the system CA bundle
+// of a Linux distribution, or all known paths for unknown distributions
+func getCABundlePaths(distro utils.LinuxDistro) []string {
+	if paths, ok := caBundlePaths[distro]; ok {
+		return paths
+	}
+
+	allPaths := make([]string, 0)
+	for _, knownDistro := range utils.KnownLinuxDistros {
+		for _, path := range caBundlePaths[knownDistro] {
+			if !slices.Contains(allPaths, path) {
+				allPaths = append(allPaths, path)
+			}
+		}
+	}
+
+	return allPaths
+}
+
+// getCACertificatesInstallCommand returns the commands, which install
+// the ca-certificates package with a package manager
+func getCACertificatesInstallCommand(pkgMgr utils.PackageManager, pkgArgs []string) ([][]string, error) {
+	var cmd []string
+
+	switch pkgMgr {
+	case utils.PkgMgrApt:
+		cmd = []string{"apt-get", "install", "-y", "-qq", "ca-certificates"}
+	case utils.PkgMgrDnf:
+		cmd = []string{"dnf", "install", "-y", "-q", "ca-certificates"}
+	case utils.PkgMgrPacman:
+		cmd = []string{"pacman", "-Sy", "--noconfirm", "ca-certificates"}
+	case utils.PkgMgrApk:
+		cmd = []string{"apk", "add", "--quiet", "ca-certificates"}
+	case utils.PkgMgrZypper:
+		cmd = []string{"zypper", "install", "-y", "-q", "ca-certificates"}
+	case utils.PkgMgrEmerge:
+		cmd = []string{"emerge", "--quiet", "app-misc/ca-certificates"}
+	case utils.PkgMgrXbpsInstall:
+		cmd = []string{"xbps-install", "-y", "ca-certificates"}
+	default:
+		return nil, fmt.Errorf("unsupported package manager: %s", pkgMgr)
+	}
+
+	cmd = append(cmd, pkgArgs...)
+
+	if pkgMgr == utils.PkgMgrApt {
+		// the package lists have to be updated first
+		return [][]string{{"apt-get", "update", "-qq"}, cmd}, nil
+	}
+
+	return [][]string{cmd}, nil
+}
+
+// getComposeConfigError returns the most relevant line of the output
+// of "docker compose config", which is the first one with a line
+// number, like "yaml: line 3: mapping values are not allowed"
+func getComposeConfigError(output string, err error) string {
+	firstLine := ""
+
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if strings.Contains(line, "line ") {
+			return line
+		}
+		if firstLine == "" {
+			firstLine = line
+		}
+	}
+
+	if firstLine == "" {
+		return err.Error()
+	}
+	return firstLine
+}
+
+// getDarwinDockerProvider returns the installed provider of docker
+// on macOS, or an empty string if there is none
+func getDarwinDockerProvider() string {
+	_, err := os.Stat(dockerDesktopAppPath)
+
+	return selectDockerProvider(err == nil, utils.CommandExists("colima"))
+}
+
+// getDockerConfigPath returns the path of the config.json of the
+// docker CLI, which is inside DOCKER_CONFIG, if set
+func getDockerConfigPath() (string, error) {
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		return filepath.Join(dir, "config.json"), nil
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("could not determine home directory: %w", err)
+	}
+
+	return filepath.Join(homeDir, ".docker", "config.json"), nil
+}
+
+// getDockerPackages returns the docker-ce packages for a package manager,
+// which are pinned to a specific version, if version is not empty
+func getDockerPackages(pkgMgr utils.PackageManager, version string) ([]string, error) {
+	packages := []string{"docker-ce", "docker-ce-cli"}
+	if version == "" {
+		return packages, nil
+	}
+
+	pinning, ok := dockerVersionPinnings[pkgMgr]
+	if !ok {
+		return nil, fmt.Errorf("--docker-version is not supported for package manager: %s", pkgMgr)
+	}
+	if !pinning.Pattern.MatchString(version) {
+		return nil, fmt.Errorf("invalid --docker-version for %s: %s", pkgMgr, version)
+	}
+
+	for i, pkg := range packages {
+		packages[i] = pkg + pinning.Separator + version
+	}
+
+	return packages, nil
+}
+
+// getDoctorCheckNames returns the names of all checks,
+// which can be skipped with --skip
+func getDoctorCheckNames() []string {
+	checks := getDoctorChecks()
+
+	names := make([]string, 0, len(checks))
+	for _, check := range checks {
+		names = append(names, check.name)
+	}
+	return names
+}
+
+// getDoctorChecks returns the definitions of all checks of doctor
+// in the order, in which they are run and reported
+func getDoctorChecks() []doctorCheck {
+	return []doctorCheck{
+		{name: "root/admin privileges", run: func(r *doctorRun) *DoctorResult {
+			return checkRootPrivileges()
+		}},
+		// Check if the binary can be replaced by other users
+		{name: "executable location", run: func(r *doctorRun) *DoctorResult {
+			if r.a.Platform().OS == utils.OSWindows {
+				return nil
+			}
+			return checkExecutableDirectory()
+		}},
+		{name: "git", run: func(r *doctorRun) *DoctorResult {
+			return checkGit()
+		}},
+		// Check if git can reach remote repositories
+		{name: "git connectivity", run: func(r *doctorRun) *DoctorResult {
+			if !r.opts.CheckGitConnectivity {
+				return nil
+			}
+			if r.a.Config().Offline {
+				r.a.D("Skipping git connectivity check because of --offline")
+				return nil
+			}
+			return checkGitConnectivity(r.result("git"), r.opts.GitTestURL)
+		}},
+		{name: "docker", run: func(r *doctorRun) *DoctorResult {
+			return checkDocker()
+		}},
+		// Check docker daemon status and the provider, which runs it
+		{name: "docker daemon", run: func(r *doctorRun) *DoctorResult {
+			dockerProvider := ""
+			if r.a.Platform().OS == utils.OSDarwin {
+				dockerProvider = getDarwinDockerProvider()
+			}
+			return checkDockerDaemon(r.result("docker"), dockerProvider)
+		}},
+		// Check if the active docker context points to a local daemon
+		{name: "docker context", run: func(r *doctorRun) *DoctorResult {
+			return checkDockerContext(r.result("docker"))
+		}},
+		// Check if the port of the registry is not used by another service
+		{name: "registry port", run: func(r *doctorRun) *DoctorResult {
+			return checkRegistryPort(r.result("docker daemon"), r.opts.RegistryName)
+		}},
+		// Check if the own hostname can be resolved
+		{name: "hostname resolution", run: func(r *doctorRun) *DoctorResult {
+			if r.hostname == "" || r.a.Platform().OS == utils.OSWindows {
+				return nil
+			}
+			return checkHostnameResolution(r.hostname, net.LookupHost)
+		}},
+		// Check if pulls from Docker Hub are rate-limited
+		{name: "docker hub login", run: func(r *doctorRun) *DoctorResult {
+			if r.a.Config().Offline {
+				r.a.D("Skipping Docker Hub login check because of --offline")
+				return nil
+			}
+			return checkDockerHubLogin(r.result("docker"))
+		}},
+		// Check where the docker package comes from
+		{name: "docker package", run: func(r *doctorRun) *DoctorResult {
+			if r.a.Platform().PackageManager != utils.PkgMgrApt {
+				return nil
+			}
+			return checkDockerPackage(r.result("docker"))
+		}},
+		// Check if the compose file can be parsed
+		{name: "compose file", run: func(r *doctorRun) *DoctorResult {
+			if !r.opts.CheckCompose {
+				return nil
+			}
+			return checkComposeProject(r.a, r.opts, r.result("docker"))
+		}},
+		// Check BuildKit for builds of compose stacks
+		{name: "buildkit", run: func(r *doctorRun) *DoctorResult {
+			return checkBuildKit(r.result("docker"))
+		}},
+		// Check WSL 2 / Hyper-V for Docker Desktop
+		{name: "WSL 2 / Hyper-V", run: func(r *doctorRun) *DoctorResult {
+			if r.a.Platform().OS != utils.OSWindows {
+				return nil
+			}
+			return checkVirtualization()
+		}},
+		// Check CA bundle for TLS to Docker Hub and package repositories
+		{name: "ca-certificates", run: func(r *doctorRun) *DoctorResult {
+			if r.a.Platform().OS != utils.OSLinux {
+				return nil
+			}
+			return checkCACertificates(r.a.Platform().LinuxDistro)
+		}},
+		// Check time zone database for containers, which mount it
+		{name: "tzdata", run: func(r *doctorRun) *DoctorResult {
+			if r.a.Platform().OS != utils.OSLinux {
+				return nil
+			}
+			return checkTimeZoneData(zoneinfoPath)
+		}},
+		// Check the AppArmor profile of the containers
+		{name: "apparmor", run: func(r *doctorRun) *DoctorResult {
+			if r.a.Platform().OS != utils.OSLinux {
+				return nil
+			}
+			return checkAppArmor(r.result("docker"))
+		}},
+		// Check iptables backend for Docker networking
+		{name: "iptables backend", run: func(r *doctorRun) *DoctorResult {
+			if r.a.Platform().OS != utils.OSLinux {
+				return nil
+			}
+			return checkIptablesBackend(r.result("docker"))
+		}},
+		// Check kernel modules for Docker networking and storage
+		{name: "kernel modules", run: func(r *doctorRun) *DoctorResult {
+			if r.a.Platform().OS != utils.OSLinux {
+				return nil
+			}
+			return checkKernelModules()
+		}},
+		// Check entropy for generating keys and certificates
+		{name: "entropy", run: func(r *doctorRun) *DoctorResult {
+			if r.a.Platform().OS != utils.OSLinux {
+				return nil
+			}
+			return checkEntropy()
+		}},
+		// Check swap for databases of the stack
+		{name: "swap", run: func(r *doctorRun) *DoctorResult {
+			if r.a.Platform().OS != utils.OSLinux {
+				return nil
+			}
+			return checkSwap()
+		}},
+		// Check filesystem of the data root for the overlay2 storage driver
+		{name: "docker data root", run: func(r *doctorRun) *DoctorResult {
+			if r.a.Platform().OS != utils.OSLinux {
+				return nil
+			}
+			return checkDockerDataRootFilesystem(r.result("docker"))
+		}},
+		// Check the registry settings of the docker daemon, which are
+		// only reported without a problem with --verbose or --bundle
+		{name: "docker daemon config", run: func(r *doctorRun) *DoctorResult {
+			if r.a.Platform().OS != utils.OSLinux {
+				return nil
+			}
+
+			result := checkDockerDaemonConfig(dockerDaemonConfigPath, getHTTPRegistryAddress(r.result("docker daemon"), r.opts.RegistryName))
+			if !result.Installed || r.a.Config().Verbose || r.opts.Bundle != "" {
+				return result
+			}
+			return nil
+		}},
+		// Report hardware virtualization for VMs besides the containers (informational)
+		{name: "hardware virtualization", run: func(r *doctorRun) *DoctorResult {
+			if r.a.Platform().OS != utils.OSLinux || (!r.a.Config().Verbose && r.opts.Bundle == "") {
+				return nil
+			}
+			return checkHardwareVirtualization()
+		}},
+		// Report DNS resolvers, which are used by containers (informational)
+		{name: "dns resolvers", run: func(r *doctorRun) *DoctorResult {
+			if r.a.Platform().OS != utils.OSLinux || (!r.a.Config().Verbose && r.opts.Bundle == "") {
+				return nil
+			}
+			return checkDNSResolvers()
+		}},
+	}
+}
+
+// getFilesystemProblem returns the reason, why a filesystem is
+// problematic as data root of docker, or an empty string
+func getFilesystemProblem(fsType string) string {
+	if strings.HasPrefix(fsType, "fuse.") {
+		return "FUSE filesystems are not supported by overlay2"
+	}
+
+	return problematicFilesystems[fsType]
+}
+
+// getGitInstallCommand returns the commands, which install git with a
+// specific package manager, including the extra package manager arguments
+func getGitInstallCommand(pkgMgr utils.PackageManager, pkgArgs []string) ([][]string, error) {
+	var cmd []string
+
+	switch pkgMgr {
+	case utils.PkgMgrApt:
+		cmd = []string{"apt-get", "install", "-y", "-qq", "git"}
+	case utils.PkgMgrDnf:
+		cmd = []string{"dnf", "install", "-y", "-q", "git"}
+	case utils.PkgMgrPacman:
+		cmd = []string{"pacman", "-Sy", "--noconfirm", "git"}
+	case utils.PkgMgrApk:
+		cmd = []string{"apk", "add", "--quiet", "git"}
+	case utils.PkgMgrZypper:
+		cmd = []string{"zypper", "install", "-y", "-q", "git"}
+	case utils.PkgMgrEmerge:
+		cmd = []string{"emerge", "--quiet", "dev-vcs/git"}
+	case utils.PkgMgrXbpsInstall:
+		cmd = []string{"xbps-install", "-y", "git"}
+	case utils.PkgMgrSnap:
+		cmd = []string{"snap", "install", "git"}
+	case utils.PkgMgrFlatpak:
+		return nil, fmt.Errorf("git cannot be installed via flatpak, please install git manually")
+	case utils.PkgMgrBrew:
+		cmd = []string{"brew", "install", "git"}
+	case utils.PkgMgrPort:
+		cmd = []string{"port", "install", "git"}
+	case utils.PkgMgrPkg:
+		cmd = []string{"pkg", "install", "-y", "git"}
+	case utils.PkgMgrWinget:
+		cmd = []string{"winget", "install", "--id", "Git.Git", "-e", "--silent"}
+	case utils.PkgMgrChoco:
+		cmd = []string{"choco", "install", "git", "-y"}
+	default:
+		return nil, fmt.Errorf("unsupported package manager: %s", pkgMgr)
+	}
+
+	cmd = append(cmd, pkgArgs...)
+
+	if pkgMgr == utils.PkgMgrApt {
+		// the package lists have to be updated first
+		return [][]string{{"apt-get", "update", "-qq"}, cmd}, nil
+	}
+
+	return [][]string{cmd}, nil
+}
+
+// getHTTPRegistryAddress returns the address of the registry container,
+// like "192.168.1.20:5000", if it is running without TLS and is used
+// via a non-loopback address, which docker does not trust by default
+func getHTTPRegistryAddress(dockerDaemonResult *DoctorResult, name string) string {
+	if !dockerDaemonResult.Installed {
+		return ""
+	}
+
+	if running, _ := checkRegistryRunning(name); !running {
+		return ""
+	}
+
+	config, err := inspectRegistryContainer(name)
+	if err != nil || config.Port == 0 {
+		return ""
+	}
+	if _, ok := config.Env["REGISTRY_HTTP_TLS_CERTIFICATE"]; ok {
+		return ""
+	}
+
+	hostname := getDefaultRegistryHostname(utils.DetectPrimaryIPv4)
+	if isLoopbackHost(hostname) {
+		return ""
+	}
+
+	return net.JoinHostPort(hostname, strconv.Itoa(config.Port))
+}
+
+// getImmutableInstallCommand returns the command, which installs
+// packages on an immutable system, or nil, if the system is a
+// regular one
+func getImmutableInstallCommand(platform *utils.PlatformInfo, packages []string, pkgArgs []string) []string {
+	switch {
+	case platform.IsMicroOS():
+		return getTransactionalUpdateCommand(packages, pkgArgs)
+	case platform.IsFedoraOSTree():
+		return getRPMOSTreeInstallCommand(packages, pkgArgs)
+	default:
+		return nil
+	}
+}
+
+// getKernelModuleStatuses returns the status of each required kernel module,
+// which is "loaded", "available" (can be loaded with modprobe) or "missing",
+// and the output of modinfo for the missing ones
+func getKernelModuleStatuses() (map[string]string, map[string]string) {
+	var loadedModules map[string]bool
+	if utils.CommandExists("lsmod") {
+		output, err := utils.RunCommand("lsmod")
+		if err == nil {
+			loadedModules = parseLsmodOutput(string(output))
+		}
+	}
+	if loadedModules == nil {
+		// lsmod is a formatted version of /proc/modules
+		data, err := os.ReadFile("/proc/modules")
+		if err == nil {
+			loadedModules = parseLsmodOutput(string(data))
+		}
+	}
+
+	statuses := make(map[string]string, len(kernelModules))
+	reasons := make(map[string]string)
+	for _, module := range kernelModules {
+		if loadedModules[module] {
+			statuses[module] = "loaded"
+			continue
+		}
+		if _, err := os.Stat("/sys/module/" + module); err == nil {
+			// built into the kernel
+			statuses[module] = "loaded"
+			continue
+		}
+
+		statuses[module] = "missing"
+		if utils.CommandExists("modinfo") {
+			if output, err := utils.RunCommandSilentCapture("modinfo", module); err == nil {
+				statuses[module] = "available"
+			} else if len(output) > 0 {
+				reasons[module] = string(output)
+			}
+		}
+	}
+
+	return statuses, reasons
+}
+
+// getReadOnlyFilesystemError returns an error, if the mount from the
+// content of /proc/mounts, which contains path, is mounted read-only
+func getReadOnlyFilesystemError(mounts string, path string) error {
+	mount := findMount(mounts, path)
+	if mount == nil || !slices.Contains(mount.Options, "ro") {
+		return nil
 	}
 
-	if !utils.CommandExists("git") {
-		return result
+	if mount.MountPoint == "/" {
+		return fmt.Errorf("root filesystem is read-only, %s cannot be changed", path)
 	}
+	return fmt.Errorf("%s is on the read-only filesystem %s and cannot be changed", path, mount.MountPoint)
+}
 
-	output, err := utils.RunCommand("git", "--version")
-	if err != nil {
-		result.Error = err
-		return result
+// getRootNote returns an informational note, if doctor runs as root
+// without --repair, which does not need these privileges
+func getRootNote(isRoot bool, opts *DoctorOptions) string {
+	if !isRoot || opts.Repair {
+		return ""
 	}
 
-	result.Installed = true
-	result.Version = strings.TrimSpace(string(output))
-	return result
+	return "Running as root is only required for --repair. Checks without --repair can be run as a regular user, which avoids root-owned files in the home directory."
 }
 
-func checkRootPrivileges() *DoctorResult {
-	result := &DoctorResult{
-		Name:      "root/admin privileges",
-		Installed: false,
+// getRootPrivilegesHint returns a hint how to run a command with
+// root privileges, based on the available privilege escalation command
+func getRootPrivilegesHint() string {
+	escalationCmd := utils.PrivilegeEscalationCommand()
+	if escalationCmd == "" {
+		return "Please run this command as root."
 	}
 
-	if utils.IsRoot() {
-		result.Installed = true
-		if runtime.GOOS == "windows" {
-			result.Version = "administrator"
-		} else {
-			result.Version = "root"
-		}
-	} else {
-		if runtime.GOOS == "windows" {
-			result.Error = fmt.Errorf("not running as administrator")
-		} else {
-			result.Error = fmt.Errorf("not running as root")
-		}
-	}
+	return fmt.Sprintf("Please run this command with %s.", escalationCmd)
+}
 
-	return result
+// getRPMOSTreeInstallCommand returns the command, which layers
+// packages on an immutable Fedora variant, including the extra
+// package manager arguments
+//
+// --idempotent ignores packages, which are already layered
+func getRPMOSTreeInstallCommand(packages []string, pkgArgs []string) []string {
+	cmd := []string{"rpm-ostree", "install", "--idempotent", "--allow-inactive"}
+	cmd = append(cmd, packages...)
+
+	return append(cmd, pkgArgs...)
 }
 
-func ensureDockerDaemonRunning(a *app.AppContext) error {
-	if isDockerDaemonRunning() {
-		a.D("Docker daemon is already running")
-		return nil
+// getTimeZoneDataInstallCommand returns the commands, which install
+// the time zone database with a package manager
+func getTimeZoneDataInstallCommand(pkgMgr utils.PackageManager, pkgArgs []string) ([][]string, error) {
+	var cmd []string
+
+	switch pkgMgr {
+	case utils.PkgMgrApt:
+		cmd = []string{"apt-get", "install", "-y", "-qq", "tzdata"}
+	case utils.PkgMgrDnf:
+		cmd = []string{"dnf", "install", "-y", "-q", "tzdata"}
+	case utils.PkgMgrPacman:
+		cmd = []string{"pacman", "-Sy", "--noconfirm", "tzdata"}
+	case utils.PkgMgrApk:
+		cmd = []string{"apk", "add", "--quiet", "tzdata"}
+	case utils.PkgMgrZypper:
+		cmd = []string{"zypper", "install", "-y", "-q", "timezone"}
+	case utils.PkgMgrEmerge:
+		cmd = []string{"emerge", "--quiet", "sys-libs/timezone-data"}
+	case utils.PkgMgrXbpsInstall:
+		cmd = []string{"xbps-install", "-y", "tzdata"}
+	default:
+		return nil, fmt.Errorf("unsupported package manager: %s", pkgMgr)
 	}
 
-	a.WriteLn("Docker daemon is not running. Attempting to start...")
+	cmd = append(cmd, pkgArgs...)
 
-	if err := startDockerDaemon(a); err != nil {
-		return fmt.Errorf("failed to start docker daemon: %w", err)
+	if pkgMgr == utils.PkgMgrApt {
+		// the package lists have to be updated first
+		return [][]string{{"apt-get", "update", "-qq"}, cmd}, nil
 	}
 
-	// Verify daemon is now running
-	if !isDockerDaemonRunning() {
-		return fmt.Errorf("docker daemon failed to start")
-	}
+	return [][]string{cmd}, nil
+}
 
-	a.WriteLn("Docker daemon started successfully.")
-	return nil
+// getTransactionalUpdateCommand returns the command, which installs
+// packages into a new snapshot of openSUSE MicroOS, including the
+// extra package manager arguments
+//
+// --continue builds on the snapshot of a previous installation,
+// which would be discarded otherwise
+func getTransactionalUpdateCommand(packages []string, pkgArgs []string) []string {
+	cmd := []string{"transactional-update", "--non-interactive", "--continue", "pkg", "install", "--no-recommends"}
+	cmd = append(cmd, packages...)
+
+	return append(cmd, pkgArgs...)
 }
 
 func getVersionCodename() string {
@@ -182,27 +2008,56 @@ func initDoctorCommand(a *app.AppContext) {
 		Aliases: []string{"doc", "d"},
 		Short:   "Check system requirements",
 		Long:    `Checks if all required tools (git, docker) are installed and optionally repairs missing dependencies.`,
+		Example: `  autark doctor
+  sudo autark doctor --repair
+  sudo autark doctor --repair --skip-daemon-start --pkg-arg=--no-install-recommends
+  autark doctor --check-compose --bundle ./autark-diagnostics.zip`,
 		Run: func(cmd *cobra.Command, args []string) {
-			runDoctor(a, opts)
+			if !opts.Repair {
+				exitOnError(a, runDoctor(a, opts))
+				return
+			}
+
+			exitOnError(a, runWithLock(a, func() error {
+				return runDoctor(a, opts)
+			}))
 		},
 	}
 
-	doctorCmd.Flags().BoolVarP(&opts.Repair, "repair", "r", false, "Install missing dependencies")
+	initDoctorFlags(doctorCmd.Flags(), opts)
 
 	rootCmd.AddCommand(doctorCmd)
 }
 
-func installDockerAlpine(a *app.AppContext) error {
+// initDoctorFlags registers the flags for DoctorOptions, which are
+// shared by all commands running the doctor
+func initDoctorFlags(flags *pflag.FlagSet, opts *DoctorOptions) {
+	flags.BoolVarP(&opts.Repair, "repair", "r", false, "Install missing dependencies")
+	flags.StringVarP(&opts.Bundle, "bundle", "", "", "Write a diagnostics bundle (zip) for support to this path")
+	flags.BoolVarP(&opts.CheckCompose, "check-compose", "", false, "Check if Docker Compose can parse the compose file")
+	flags.StringVarP(&opts.ComposeFile, "file", "f", "", "Compose file for --check-compose (default: discovered like by the compose command)")
+	flags.BoolVarP(&opts.CheckGitConnectivity, "check-git-connectivity", "", false, "Check if git can reach a remote repository")
+	flags.StringVarP(&opts.GitTestURL, "git-test-url", "", defaultGitTestURL, "Repository used by --check-git-connectivity")
+	flags.StringVarP(&opts.DockerVersion, "docker-version", "", "", "Install a specific version of docker-ce (apt and dnf only)")
+	flags.StringArrayVarP(&opts.PkgArgs, "pkg-arg", "", nil, "Extra argument for the install commands of the package manager (repeatable)")
+	flags.BoolVarP(&opts.SkipDaemonStart, "skip-daemon-start", "", false, "Do not start the docker daemon with --repair")
+	flags.BoolVarP(&opts.JSON, "json", "", false, "Write the commands run by --repair as JSON")
+	flags.BoolVarP(&opts.SummaryOnly, "summary-only", "", false, "Only write the final verdict instead of the result of each check")
+	flags.StringArrayVarP(&opts.Skip, "skip", "", nil, "Skip the check with this name, like \"docker hub login\" (repeatable)")
+	flags.StringVarP(&opts.RegistryName, "registry-name", "", registryContainerName, "Name of the registry container, which is checked")
+}
+
+func installDockerAlpine(a *app.AppContext, opts *DoctorOptions) error {
 	a.D("Installing Docker on Alpine Linux...")
 
 	commands := [][]string{
-		{"apk", "add", "docker", "docker-cli", "containerd"},
+		append([]string{"apk", "add", "docker", "docker-cli", "containerd"}, opts.PkgArgs...),
 		{"rc-update", "add", "docker", "boot"},
 		{"service", "docker", "start"},
 	}
 
 	for _, cmd := range commands {
-		if err := runInstallCommandDirect(cmd[0], cmd[1:]...); err != nil {
+		if err := runInstallCommandDirect(a, cmd[0], cmd[1:]...); err != nil {
 			return fmt.Errorf("failed to run %s: %w", cmd[0], err)
 		}
 	}
@@ -210,16 +2065,16 @@ func installDockerAlpine(a *app.AppContext) error {
 	return nil
 }
 
-func installDockerArch(a *app.AppContext) error {
+func installDockerArch(a *app.AppContext, opts *DoctorOptions) error {
 	a.D("Installing Docker on Arch Linux...")
 
 	commands := [][]string{
-		{"pacman", "-Sy", "--noconfirm", "docker", "docker-compose"},
+		append([]string{"pacman", "-Sy", "--noconfirm", "docker", "docker-compose"}, opts.PkgArgs...),
 		{"systemctl", "enable", "--now", "docker"},
 	}
 
 	for _, cmd := range commands {
-		if err := runInstallCommandDirect(cmd[0], cmd[1:]...); err != nil {
+		if err := runInstallCommandDirect(a, cmd[0], cmd[1:]...); err != nil {
 			return fmt.Errorf("failed to run %s: %w", cmd[0], err)
 		}
 	}
@@ -227,12 +2082,12 @@ func installDockerArch(a *app.AppContext) error {
 	return nil
 }
 
-func installDockerByPackageManager(a *app.AppContext) error {
+func installDockerByPackageManager(a *app.AppContext, opts *DoctorOptions) error {
 	a.D("Installing Docker via package manager fallback...")
 
 	switch a.Platform().PackageManager {
 	case utils.PkgMgrSnap:
-		return runInstallCommandDirect("snap", "install", "docker")
+		return runInstallCommandDirect(a, "snap", append([]string{"install", "docker"}, opts.PkgArgs...)...)
 	case utils.PkgMgrFlatpak:
 		return fmt.Errorf("docker cannot be installed via flatpak, please install docker manually")
 	default:
@@ -240,33 +2095,52 @@ func installDockerByPackageManager(a *app.AppContext) error {
 	}
 }
 
-func installDockerDebian(a *app.AppContext) error {
+func installDockerDebian(a *app.AppContext, opts *DoctorOptions) error {
 	a.D("Installing Docker on Debian/Ubuntu...")
 
 	// Determine the correct distro name for Docker repo
 	distroName := "debian"
 	if a.Platform().LinuxDistro == utils.DistroUbuntu {
 		distroName = "ubuntu"
+	} else if a.Platform().LinuxDistroID == "raspbian" {
+		// 32-bit Raspberry Pi OS has its own repository
+		distroName = "raspbian"
+	}
+
+	for _, path := range []string{dockerAptKeyringFile, dockerAptSourcesFile} {
+		if err := checkWritableFilesystem(path); err != nil {
+			return err
+		}
 	}
 
 	commands := [][]string{
 		{"apt-get", "update", "-qq"},
-		{"apt-get", "install", "-y", "-qq", "ca-certificates", "curl", "gnupg"},
+		append([]string{"apt-get", "install", "-y", "-qq", "ca-certificates", "curl", "gnupg"}, opts.PkgArgs...),
 		{"install", "-m", "0755", "-d", "/etc/apt/keyrings"},
 	}
 
 	for _, cmd := range commands {
-		if err := runInstallCommandDirect(cmd[0], cmd[1:]...); err != nil {
-			return fmt.Errorf("failed to run %s: %w", cmd[0], err)
+		if err := runAptCommand(a, cmd[0], cmd[1:]...); err != nil {
+			return err
 		}
 	}
 
 	// Download GPG key
 	gpgURL := fmt.Sprintf("https://download.docker.com/linux/%s/gpg", distroName)
-	curlCmd := exec.Command("curl", "-fsSL", gpgURL, "-o", "/etc/apt/keyrings/docker.asc")
-	if err := curlCmd.Run(); err != nil {
+	gpgKey, err := exec.Command("curl", "-fsSL", gpgURL).Output()
+	a.RecordCommand("curl", []string{"-fsSL", gpgURL}, err)
+	if err != nil {
 		return fmt.Errorf("failed to download docker GPG key: %w", err)
 	}
+	if len(strings.TrimSpace(string(gpgKey))) == 0 {
+		return fmt.Errorf("downloaded docker GPG key from %s is empty", gpgURL)
+	}
+
+	if written, err := utils.WriteFileIfChanged(dockerAptKeyringFile, gpgKey, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", dockerAptKeyringFile, err)
+	} else if !written {
+		a.D("%s is up to date", dockerAptKeyringFile)
+	}
 
 	// Get version codename
 	versionCodename := getVersionCodename()
@@ -274,48 +2148,86 @@ func installDockerDebian(a *app.AppContext) error {
 		return fmt.Errorf("could not determine version codename")
 	}
 
-	// Get architecture
-	arch := runtime.GOARCH
-	if arch == "amd64" {
-		arch = "amd64"
-	} else if arch == "arm64" {
-		arch = "arm64"
-	}
+	// Get architecture, like "armhf" on a 32-bit Raspberry Pi
+	arch := a.Platform().DebianArch()
 
 	// Add Docker repository
-	repoLine := fmt.Sprintf("deb [arch=%s signed-by=/etc/apt/keyrings/docker.asc] https://download.docker.com/linux/%s %s stable",
-		arch, distroName, versionCodename)
+	repoLine := fmt.Sprintf("deb [arch=%s signed-by=%s] https://download.docker.com/linux/%s %s stable",
+		arch, dockerAptKeyringFile, distroName, versionCodename)
 
-	if err := os.WriteFile("/etc/apt/sources.list.d/docker.list", []byte(repoLine+"\n"), 0644); err != nil {
+	if written, err := utils.WriteFileIfChanged(dockerAptSourcesFile, []byte(repoLine+"\n"), 0644); err != nil {
 		return fmt.Errorf("failed to write docker.list: %w", err)
+	} else if !written {
+		a.D("%s is up to date", dockerAptSourcesFile)
+	}
+
+	dockerPackages, err := getDockerPackages(utils.PkgMgrApt, opts.DockerVersion)
+	if err != nil {
+		return err
 	}
 
 	// Update and install Docker
+	installArgs := []string{"apt-get", "install", "-y", "-qq"}
+	installArgs = append(installArgs, dockerPackages...)
+	installArgs = append(installArgs, "containerd.io", "docker-buildx-plugin", "docker-compose-plugin")
+
 	finalCommands := [][]string{
 		{"apt-get", "update", "-qq"},
-		{"apt-get", "install", "-y", "-qq", "docker-ce", "docker-ce-cli", "containerd.io", "docker-buildx-plugin", "docker-compose-plugin"},
+		append(installArgs, opts.PkgArgs...),
 	}
 
 	for _, cmd := range finalCommands {
-		if err := runInstallCommandDirect(cmd[0], cmd[1:]...); err != nil {
-			return fmt.Errorf("failed to run %s: %w", cmd[0], err)
+		if err := runAptCommand(a, cmd[0], cmd[1:]...); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
-func installDockerFedora(a *app.AppContext) error {
+// installDockerFedoraOSTree layers docker on an immutable Fedora
+// variant, which is only active after a reboot
+func installDockerFedoraOSTree(a *app.AppContext, opts *DoctorOptions) error {
+	a.D("Installing Docker on Fedora ostree variant...")
+
+	// Silverblue and its siblings ship podman, which may be enough
+	if utils.CommandExists("podman") {
+		a.W("podman is already installed. It can be used instead of docker, or with the podman-docker package as its replacement.")
+	}
+
+	cmd := getRPMOSTreeInstallCommand([]string{"moby-engine", "docker-compose"}, opts.PkgArgs)
+	if err := runInstallCommandDirect(a, cmd[0], cmd[1:]...); err != nil {
+		return fmt.Errorf("failed to run %s: %w", cmd[0], err)
+	}
+
+	a.W("After the reboot, enable the docker daemon with 'systemctl enable --now docker'.")
+	return errRebootRequired
+}
+
+func installDockerFedora(a *app.AppContext, opts *DoctorOptions) error {
+	if a.Platform().IsFedoraOSTree() {
+		return installDockerFedoraOSTree(a, opts)
+	}
+
 	a.D("Installing Docker on Fedora/RHEL...")
 
+	dockerPackages, err := getDockerPackages(utils.PkgMgrDnf, opts.DockerVersion)
+	if err != nil {
+		return err
+	}
+
+	installArgs := []string{"dnf", "install", "-y", "-q"}
+	installArgs = append(installArgs, dockerPackages...)
+	installArgs = append(installArgs, "containerd.io", "docker-buildx-plugin", "docker-compose-plugin")
+
 	commands := [][]string{
 		{"dnf", "config-manager", "addrepo", "--from-repofile=https://download.docker.com/linux/fedora/docker-ce.repo"},
-		{"dnf", "install", "-y", "-q", "docker-ce", "docker-ce-cli", "containerd.io", "docker-buildx-plugin", "docker-compose-plugin"},
+		append(installArgs, opts.PkgArgs...),
 		{"systemctl", "enable", "--now", "docker"},
 	}
 
 	for _, cmd := range commands {
-		if err := runInstallCommandDirect(cmd[0], cmd[1:]...); err != nil {
+		if err := runInstallCommandDirect(a, cmd[0], cmd[1:]...); err != nil {
 			return fmt.Errorf("failed to run %s: %w", cmd[0], err)
 		}
 	}
@@ -323,17 +2235,17 @@ func installDockerFedora(a *app.AppContext) error {
 	return nil
 }
 
-func installDockerGentoo(a *app.AppContext) error {
+func installDockerGentoo(a *app.AppContext, opts *DoctorOptions) error {
 	a.D("Installing Docker on Gentoo...")
 
 	commands := [][]string{
-		{"emerge", "--quiet", "app-containers/docker", "app-containers/docker-compose"},
+		append([]string{"emerge", "--quiet", "app-containers/docker", "app-containers/docker-compose"}, opts.PkgArgs...),
 		{"rc-update", "add", "docker", "default"},
 		{"service", "docker", "start"},
 	}
 
 	for _, cmd := range commands {
-		if err := runInstallCommandDirect(cmd[0], cmd[1:]...); err != nil {
+		if err := runInstallCommandDirect(a, cmd[0], cmd[1:]...); err != nil {
 			return fmt.Errorf("failed to run %s: %w", cmd[0], err)
 		}
 	}
@@ -341,16 +2253,34 @@ func installDockerGentoo(a *app.AppContext) error {
 	return nil
 }
 
-func installDockerOpenSUSE(a *app.AppContext) error {
+// installDockerMicroOS installs docker into a new snapshot of an
+// immutable openSUSE MicroOS, which is only active after a reboot
+func installDockerMicroOS(a *app.AppContext, opts *DoctorOptions) error {
+	a.D("Installing Docker on openSUSE MicroOS...")
+
+	cmd := getTransactionalUpdateCommand([]string{"docker", "docker-compose"}, opts.PkgArgs)
+	if err := runInstallCommandDirect(a, cmd[0], cmd[1:]...); err != nil {
+		return fmt.Errorf("failed to run %s: %w", cmd[0], err)
+	}
+
+	a.W("After the reboot, enable the docker daemon with 'systemctl enable --now docker'.")
+	return errRebootRequired
+}
+
+func installDockerOpenSUSE(a *app.AppContext, opts *DoctorOptions) error {
+	if a.Platform().IsMicroOS() {
+		return installDockerMicroOS(a, opts)
+	}
+
 	a.D("Installing Docker on openSUSE...")
 
 	commands := [][]string{
-		{"zypper", "install", "-y", "docker", "docker-compose"},
+		append([]string{"zypper", "install", "-y", "--no-recommends", "docker", "docker-compose"}, opts.PkgArgs...),
 		{"systemctl", "enable", "--now", "docker"},
 	}
 
 	for _, cmd := range commands {
-		if err := runInstallCommandDirect(cmd[0], cmd[1:]...); err != nil {
+		if err := runInstallCommandDirect(a, cmd[0], cmd[1:]...); err != nil {
 			return fmt.Errorf("failed to run %s: %w", cmd[0], err)
 		}
 	}
@@ -358,16 +2288,16 @@ func installDockerOpenSUSE(a *app.AppContext) error {
 	return nil
 }
 
-func installDockerVoid(a *app.AppContext) error {
+func installDockerVoid(a *app.AppContext, opts *DoctorOptions) error {
 	a.D("Installing Docker on Void Linux...")
 
 	commands := [][]string{
-		{"xbps-install", "-y", "docker", "docker-compose"},
+		append([]string{"xbps-install", "-y", "docker", "docker-compose"}, opts.PkgArgs...),
 		{"ln", "-s", "/etc/sv/docker", "/var/service/"},
 	}
 
 	for _, cmd := range commands {
-		if err := runInstallCommandDirect(cmd[0], cmd[1:]...); err != nil {
+		if err := runInstallCommandDirect(a, cmd[0], cmd[1:]...); err != nil {
 			return fmt.Errorf("failed to run %s: %w", cmd[0], err)
 		}
 	}
@@ -375,46 +2305,472 @@ func installDockerVoid(a *app.AppContext) error {
 	return nil
 }
 
+// hasIptablesRules checks if the output of iptables-save
+// contains at least one rule
+func hasIptablesRules(output string) bool {
+	for _, line := range strings.Split(output, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "-A ") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isAptLockError checks if the output of apt or dpkg reports,
+// that the lock is held by another process
+func isAptLockError(output string) bool {
+	for _, msg := range aptLockErrors {
+		if strings.Contains(output, msg) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isBuildKitDisabled checks if a value of the DOCKER_BUILDKIT
+// environment variable disables BuildKit
+func isBuildKitDisabled(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "0", "false", "no", "off":
+		return true
+	default:
+		return false
+	}
+}
+
+// isDockerHubLoggedIn checks if the content of a docker config.json
+// contains credentials or a credential helper for Docker Hub
+//
+// With a credential store, like "desktop" or "osxkeychain", the
+// entries of "auths" are empty, but still exist after "docker login"
+func isDockerHubLoggedIn(data []byte) (bool, error) {
+	var config struct {
+		Auths       map[string]json.RawMessage `json:"auths"`
+		CredHelpers map[string]string          `json:"credHelpers"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return false, err
+	}
+
+	for _, key := range dockerHubRegistryKeys {
+		if _, ok := config.Auths[key]; ok {
+			return true, nil
+		}
+		if _, ok := config.CredHelpers[key]; ok {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// isDoctorCheckSkipped checks if the check with
+// name has been skipped with --skip
+func isDoctorCheckSkipped(skip []string, name string) bool {
+	return slices.ContainsFunc(skip, func(skipped string) bool {
+		return strings.EqualFold(skipped, name)
+	})
+}
+
+// isEntropyLow checks if the available entropy is
+// below minEntropy
+func isEntropyLow(entropy int) bool {
+	return entropy < minEntropy
+}
+
 func isDockerDaemonRunning() bool {
 	cmd := exec.Command("docker", "info")
 	return cmd.Run() == nil
 }
 
-func printResult(a *app.AppContext, r *DoctorResult) {
-	if r.Installed {
-		version := r.Version
-		if version == "" {
-			version = "installed"
+// isRemoteDockerEndpoint checks if the endpoint of a docker context,
+// like "unix:///var/run/docker.sock" or "ssh://user@host", belongs
+// to a daemon on another host
+func isRemoteDockerEndpoint(endpoint string) bool {
+	if endpoint == "" {
+		return false
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return false
+	}
+
+	switch u.Scheme {
+	case "unix", "npipe":
+		return false
+	case "tcp", "http", "https":
+		host := u.Hostname()
+		if host == "localhost" {
+			return false
+		}
+		if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+			return false
+		}
+		return true
+	default:
+		return true // ssh and unknown schemes
+	}
+}
+
+// isInsecureRegistryListed checks if a registry address, like
+// "192.168.1.20:5000", matches an entry of "insecure-registries",
+// which is an address or a CIDR, like "192.168.1.0/24"
+func isInsecureRegistryListed(address string, insecureRegistries []string) bool {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		host = address
+	}
+	ip := net.ParseIP(host)
+
+	for _, entry := range insecureRegistries {
+		entry = strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(entry, "http://"), "https://"), "/")
+		if entry == address {
+			return true
 		}
-		a.WriteF("[OK] %s: %s", r.Name, version)
-	} else {
+
+		if _, network, err := net.ParseCIDR(entry); err == nil && ip != nil && network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isWorldWritable checks if the permissions of a file or
+// directory allow all users to write
+func isWorldWritable(mode os.FileMode) bool {
+	return mode.Perm()&0o002 != 0
+}
+
+// isZoneinfoDir checks if a path is an existing directory
+// of a time zone database
+func isZoneinfoDir(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+
+	return info.IsDir()
+}
+
+// newDoctorResultsTable creates a table with the status, the name
+// and the details of each result
+func newDoctorResultsTable(a *app.AppContext, results []*DoctorResult) *app.Table {
+	table := app.NewTable()
+	table.Colorize = a.StatusColors(0)
+
+	for _, r := range results {
+		if r.Installed {
+			version := r.Version
+			if version == "" {
+				version = "installed"
+			}
+			table.AddRow(a.Status(app.StatusOK), r.Name, version)
+			continue
+		}
+
 		msg := "not found"
 		if r.Error != nil {
 			msg = r.Error.Error()
 		}
-		a.WriteErrF("[ERROR] %s: %s", r.Name, msg)
+		if r.Optional {
+			table.AddRow(a.Status(app.StatusWarn), r.Name, msg)
+		} else {
+			table.AddRow(a.Status(app.StatusError), r.Name, msg)
+		}
+	}
+
+	return table
+}
+
+// newInstallCommandError creates an error for a failed install command,
+// which includes the last line of its output
+func newInstallCommandError(name string, err error, output []byte) error {
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	lastLine := strings.TrimSpace(lines[len(lines)-1])
+
+	if lastLine == "" {
+		return fmt.Errorf("failed to run %s: %w", name, err)
+	}
+
+	return fmt.Errorf("failed to run %s: %w (%s)", name, err, lastLine)
+}
+
+// parseAaStatus parses the output of "aa-status" and returns the
+// loaded profiles with their modes, like "enforce" or "complain"
+func parseAaStatus(output string) map[string]string {
+	profiles := map[string]string{}
+
+	mode := ""
+	for _, line := range strings.Split(output, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+
+		// headers are not indented, profiles are
+		if trimmed == line {
+			mode = ""
+			if match := aaStatusModeRegex.FindStringSubmatch(trimmed); match != nil {
+				mode = match[1]
+			}
+			continue
+		}
+
+		if mode != "" {
+			profiles[trimmed] = mode
+		}
+	}
+
+	return profiles
+}
+
+// parseAptCachePolicy parses the output of "apt-cache policy <package>"
+func parseAptCachePolicy(output string) *aptPackagePolicy {
+	policy := &aptPackagePolicy{
+		Sources: []string{},
+	}
+
+	inInstalledVersion := false
+	for _, line := range strings.Split(output, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if value, ok := strings.CutPrefix(trimmed, "Installed:"); ok {
+			value = strings.TrimSpace(value)
+			if value != "(none)" {
+				policy.Installed = value
+			}
+			continue
+		}
+
+		if policy.Installed == "" {
+			continue
+		}
+
+		// the installed version is marked in the version table,
+		// like " *** 5:24.0.7-1~ubuntu.22.04~jammy 500"
+		fields := strings.Fields(trimmed)
+		if len(fields) >= 2 && fields[0] == "***" {
+			inInstalledVersion = fields[1] == policy.Installed
+			continue
+		}
+
+		if inInstalledVersion && len(fields) >= 2 {
+			if _, err := strconv.Atoi(fields[0]); err == nil {
+				// source line, like "500 https://download.docker.com/linux/ubuntu jammy/stable amd64 Packages"
+				policy.Sources = append(policy.Sources, strings.Join(fields[1:], " "))
+			} else {
+				inInstalledVersion = false
+			}
+		}
+	}
+
+	return policy
+}
+
+// parseCPUInfoVirtualizationFlag returns the flag of the content of
+// /proc/cpuinfo, which indicates hardware virtualization, like "vmx"
+// or "svm", or an empty string, if there is none
+func parseCPUInfoVirtualizationFlag(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || strings.TrimSpace(key) != "flags" {
+			continue
+		}
+
+		for _, flag := range strings.Fields(value) {
+			if _, ok := cpuVirtualizationFlags[flag]; ok {
+				return flag
+			}
+		}
+	}
+
+	return ""
+}
+
+// parseDockerDaemonConfig parses the registry settings of the
+// content of a daemon.json file
+func parseDockerDaemonConfig(data []byte) (*dockerDaemonConfig, error) {
+	config := &dockerDaemonConfig{}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return config, nil // docker accepts an empty file
+	}
+
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// parseResolvConf returns the addresses of the nameserver
+// lines of the content of a resolv.conf file
+func parseResolvConf(content string) []string {
+	nameservers := make([]string, 0)
+
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[0] == "nameserver" {
+			nameservers = append(nameservers, fields[1])
+		}
+	}
+
+	return nameservers
+}
+
+// parseSysteminfoHyperV parses the Hyper-V section
+// of the output of "systeminfo"
+func parseSysteminfoHyperV(output string) *hyperVInfo {
+	info := &hyperVInfo{}
+
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+
+		if strings.Contains(line, "A hypervisor has been detected") {
+			info.HypervisorDetected = true
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if ok && strings.TrimSpace(key) == "Virtualization Enabled In Firmware" {
+			info.VirtualizationEnabled = strings.EqualFold(strings.TrimSpace(value), "Yes")
+		}
+	}
+
+	return info
+}
+
+// parseWSLStatus parses the output of "wsl --status", which is
+// written as UTF-16 by older versions of wsl.exe
+func parseWSLStatus(output []byte) *wslStatus {
+	text := decodeWSLOutput(output)
+	status := &wslStatus{}
+
+	if match := wslDefaultVersionRegex.FindStringSubmatch(text); match != nil {
+		status.Installed = true
+		status.DefaultVersion = match[1]
+	} else if strings.Contains(text, "Default Distribution") {
+		status.Installed = true
+	}
+
+	return status
+}
+
+// parseBuildxVersion extracts the version from the output of
+// "docker buildx version", like "github.com/docker/buildx v0.12.1 d4f088e"
+func parseBuildxVersion(output string) string {
+	if version := utils.ParseSemver(output); version != "" {
+		return "v" + version
+	}
+
+	return ""
+}
+
+// parseDockerContext parses the output of
+// "docker context inspect --format '{{.Name}} {{.Endpoints.docker.Host}}'"
+// and returns the name and the endpoint of the context
+func parseDockerContext(output string) (string, string) {
+	name, endpoint, _ := strings.Cut(strings.TrimSpace(output), " ")
+
+	return strings.TrimSpace(name), strings.TrimSpace(endpoint)
+}
+
+// parseIptablesBackend extracts the backend from the output of
+// "iptables --version", like "iptables v1.8.7 (nf_tables)", which is
+// "legacy" for old versions without a backend suffix
+func parseIptablesBackend(output string) string {
+	output = strings.TrimSpace(output)
+	if !strings.HasPrefix(output, "iptables v") {
+		return ""
+	}
+
+	start := strings.LastIndex(output, "(")
+	end := strings.LastIndex(output, ")")
+	if start == -1 || end < start {
+		return "legacy"
+	}
+
+	return output[start+1 : end]
+}
+
+// parseProcSwaps returns the total size in KiB of all
+// swap areas from the content of /proc/swaps
+func parseProcSwaps(content string) int64 {
+	var totalKB int64
+
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		fields := strings.Fields(line)
+		if i == 0 || len(fields) < 3 {
+			continue // header or empty line
+		}
+
+		if size, err := strconv.ParseInt(fields[2], 10, 64); err == nil {
+			totalKB += size
+		}
+	}
+
+	return totalKB
+}
+
+// parseSwappiness parses the content of /proc/sys/vm/swappiness
+func parseSwappiness(content string) (int, error) {
+	return strconv.Atoi(strings.TrimSpace(content))
+}
+
+// parseLsmodOutput parses the output of lsmod or the content of
+// /proc/modules and returns the names of the loaded modules
+func parseLsmodOutput(output string) map[string]bool {
+	modules := map[string]bool{}
+
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || fields[0] == "Module" {
+			continue // empty line or header of lsmod
+		}
+
+		modules[fields[0]] = true
+	}
+
+	return modules
+}
+
+func repairCACertificates(a *app.AppContext, opts *DoctorOptions) error {
+	a.WriteLn("Installing ca-certificates...")
+
+	cmds, err := getCACertificatesInstallCommand(a.Platform().PackageManager, opts.PkgArgs)
+	if err != nil {
+		return err
 	}
-	a.WriteLn("")
+
+	return runInstallCommands(a, cmds)
 }
 
-func repairDocker(a *app.AppContext) error {
+func repairDocker(a *app.AppContext, opts *DoctorOptions) error {
 	a.WriteLn("Installing docker...")
 
 	switch a.Platform().OS {
 	case utils.OSLinux:
-		return repairDockerLinux(a)
+		return repairDockerLinux(a, opts)
 	case utils.OSDarwin:
-		return repairDockerDarwin(a)
+		return repairDockerDarwin(a, opts)
 	case utils.OSWindows:
-		return repairDockerWindows(a)
+		return repairDockerWindows(a, opts)
 	case utils.OSFreeBSD:
-		return repairDockerBSD(a)
+		return repairDockerBSD(a, opts)
 	default:
 		return fmt.Errorf("docker installation not supported on %s", a.Platform().OS)
 	}
 }
 
-func repairDockerBSD(a *app.AppContext) error {
+func repairDockerBSD(a *app.AppContext, opts *DoctorOptions) error {
 	a.D("Installing Docker on BSD...")
 
 	if a.Platform().PackageManager != utils.PkgMgrPkg {
@@ -423,11 +2779,11 @@ func repairDockerBSD(a *app.AppContext) error {
 
 	// Note: Docker has limited support on BSD, this installs the available packages
 	commands := [][]string{
-		{"pkg", "install", "-y", "docker"},
+		append([]string{"pkg", "install", "-y", "docker"}, opts.PkgArgs...),
 	}
 
 	for _, cmd := range commands {
-		if err := runInstallCommandDirect(cmd[0], cmd[1:]...); err != nil {
+		if err := runInstallCommandDirect(a, cmd[0], cmd[1:]...); err != nil {
 			return fmt.Errorf("failed to run %s: %w", cmd[0], err)
 		}
 	}
@@ -436,20 +2792,35 @@ func repairDockerBSD(a *app.AppContext) error {
 	return nil
 }
 
-func repairDockerDarwin(a *app.AppContext) error {
+// repairDockerDaemon starts the docker daemon with start, unless a
+// reboot is required first or --skip-daemon-start is set
+func repairDockerDaemon(a *app.AppContext, opts *DoctorOptions, rebootRequired bool, start func(a *app.AppContext) error) error {
+	switch {
+	case rebootRequired:
+		a.W("Docker daemon is not running. Skipping start because a reboot is required first.")
+	case opts.SkipDaemonStart:
+		a.W("Docker daemon is not running. Skipping start because of --skip-daemon-start, please start it manually.")
+	default:
+		return start(a)
+	}
+
+	return nil
+}
+
+func repairDockerDarwin(a *app.AppContext, opts *DoctorOptions) error {
 	a.D("Installing Docker on macOS...")
 
 	switch a.Platform().PackageManager {
 	case utils.PkgMgrBrew:
 		// Install Docker Desktop via brew cask
-		if err := runInstallCommandDirect("brew", "install", "--cask", "docker"); err != nil {
+		if err := runInstallCommandDirect(a, "brew", append([]string{"install", "--cask", "docker"}, opts.PkgArgs...)...); err != nil {
 			return fmt.Errorf("failed to install Docker Desktop: %w", err)
 		}
 		a.WriteLn("Docker Desktop installed. Please open Docker Desktop from Applications to complete setup.")
 		return nil
 	case utils.PkgMgrPort:
 		// MacPorts has docker available
-		if err := runInstallCommandDirect("port", "install", "docker"); err != nil {
+		if err := runInstallCommandDirect(a, "port", append([]string{"install", "docker"}, opts.PkgArgs...)...); err != nil {
 			return fmt.Errorf("failed to install docker via MacPorts: %w", err)
 		}
 		a.WriteLn("Docker installed via MacPorts. You may need to configure it manually.")
@@ -459,162 +2830,279 @@ func repairDockerDarwin(a *app.AppContext) error {
 	}
 }
 
-func repairDockerLinux(a *app.AppContext) error {
-	switch a.Platform().LinuxDistro {
-	case utils.DistroDebian, utils.DistroUbuntu:
-		return installDockerDebian(a)
-	case utils.DistroFedora, utils.DistroRHEL, utils.DistroCentOS:
-		return installDockerFedora(a)
-	case utils.DistroArch:
-		return installDockerArch(a)
-	case utils.DistroAlpine:
-		return installDockerAlpine(a)
-	case utils.DistroOpenSUSE:
-		return installDockerOpenSUSE(a)
-	case utils.DistroGentoo:
-		return installDockerGentoo(a)
-	case utils.DistroVoid:
-		return installDockerVoid(a)
-	default:
-		// Try fallback based on package manager
-		return installDockerByPackageManager(a)
+func repairDockerLinux(a *app.AppContext, opts *DoctorOptions) error {
+	if install, ok := linuxDockerInstallers[a.Platform().LinuxDistro]; ok {
+		return install(a, opts)
 	}
+
+	// Try fallback based on package manager
+	return installDockerByPackageManager(a, opts)
 }
 
-func repairDockerWindows(a *app.AppContext) error {
+func repairDockerWindows(a *app.AppContext, opts *DoctorOptions) error {
 	a.D("Installing Docker on Windows...")
 
+	// Docker Desktop cannot run without one of them
+	if virtualizationResult := checkVirtualization(); !virtualizationResult.Installed {
+		return fmt.Errorf("Docker Desktop requires WSL 2 or Hyper-V: %w", virtualizationResult.Error)
+	}
+
 	switch a.Platform().PackageManager {
 	case utils.PkgMgrWinget:
-		return runInstallCommandDirect("winget", "install", "--id", "Docker.DockerDesktop", "-e", "--silent")
+		return runInstallCommandDirect(a, "winget", append([]string{"install", "--id", "Docker.DockerDesktop", "-e", "--silent"}, opts.PkgArgs...)...)
 	case utils.PkgMgrChoco:
-		return runInstallCommandDirect("choco", "install", "docker-desktop", "-y")
+		return runInstallCommandDirect(a, "choco", append([]string{"install", "docker-desktop", "-y"}, opts.PkgArgs...)...)
 	default:
 		return fmt.Errorf("winget or chocolatey is required to install Docker on Windows")
 	}
 }
 
-func repairGit(a *app.AppContext) error {
-	a.WriteLn("Installing git...")
+// repairHostnameResolution maps the hostname of this host to a
+// loopback address in the hosts file
+//
+// Like Debian, 127.0.1.1 is used, so the entry of localhost is kept,
+// except on macOS, where only 127.0.0.1 is assigned to the loopback
+func repairHostnameResolution(a *app.AppContext, hostname string) error {
+	hostsFile := utils.HostsFilePath()
 
-	switch a.Platform().PackageManager {
-	case utils.PkgMgrApt:
-		return runInstallCommand("apt-get", "update", "-qq", "&&", "apt-get", "install", "-y", "-qq", "git")
-	case utils.PkgMgrDnf:
-		return runInstallCommand("dnf", "install", "-y", "-q", "git")
-	case utils.PkgMgrPacman:
-		return runInstallCommand("pacman", "-Sy", "--noconfirm", "git")
-	case utils.PkgMgrApk:
-		return runInstallCommand("apk", "add", "--quiet", "git")
-	case utils.PkgMgrZypper:
-		return runInstallCommand("zypper", "install", "-y", "-q", "git")
-	case utils.PkgMgrEmerge:
-		return runInstallCommandDirect("emerge", "--quiet", "dev-vcs/git")
-	case utils.PkgMgrXbpsInstall:
-		return runInstallCommandDirect("xbps-install", "-y", "git")
-	case utils.PkgMgrSnap:
-		return runInstallCommandDirect("snap", "install", "git")
-	case utils.PkgMgrFlatpak:
-		return fmt.Errorf("git cannot be installed via flatpak, please install git manually")
-	case utils.PkgMgrBrew:
-		return runInstallCommandDirect("brew", "install", "git")
-	case utils.PkgMgrPort:
-		return runInstallCommandDirect("port", "install", "git")
-	case utils.PkgMgrPkg:
-		return runInstallCommandDirect("pkg", "install", "-y", "git")
-	case utils.PkgMgrWinget:
-		return runInstallCommandDirect("winget", "install", "--id", "Git.Git", "-e", "--silent")
-	case utils.PkgMgrChoco:
-		return runInstallCommandDirect("choco", "install", "git", "-y")
-	default:
-		return fmt.Errorf("unsupported package manager: %s", a.Platform().PackageManager)
+	ip := "127.0.1.1"
+	if a.Platform().OS == utils.OSDarwin {
+		ip = "127.0.0.1"
+	}
+
+	if err := checkWritableFilesystem(hostsFile); err != nil {
+		return err
+	}
+
+	content, err := os.ReadFile(hostsFile)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", hostsFile, err)
 	}
-}
 
-func runDoctor(a *app.AppContext, opts *DoctorOptions) {
-	a.WriteLn("Checking system requirements...")
+	newContent, changed := utils.SetHostsEntry(string(content), hostname, ip)
+	if !changed {
+		return nil
+	}
+
+	if _, err := utils.WriteFileIfChanged(hostsFile, []byte(newContent), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", hostsFile, err)
+	}
+
+	a.WriteF("%s now points to %s. To undo this, remove the line marked with '%s' from %s.", hostname, ip, utils.HostsEntryMarker, hostsFile)
 	a.WriteLn("")
+	return nil
+}
 
-	platform := a.Platform()
+func repairKernelModules(a *app.AppContext) error {
+	a.WriteLn("Loading kernel modules...")
+
+	statuses, reasons := getKernelModuleStatuses()
+
+	missingModules := make([]string, 0)
+	for _, module := range kernelModules {
+		switch statuses[module] {
+		case "available":
+			if err := runInstallCommandDirect(a, "modprobe", module); err != nil {
+				return fmt.Errorf("failed to load kernel module %s: %w", module, err)
+			}
+		case "missing":
+			if reason, ok := reasons[module]; ok {
+				module = fmt.Sprintf("%s (%s)", module, reason)
+			}
+			missingModules = append(missingModules, module)
+		}
+	}
 
-	a.D("Detected OS: %s", platform.OS)
-	a.D("Detected Arch: %s", platform.Arch)
-	if platform.OS == utils.OSLinux {
-		a.D("Detected Linux Distro: %s (%s)", platform.LinuxDistro, platform.LinuxDistroID)
+	if len(missingModules) > 0 {
+		return fmt.Errorf("kernel module(s) not available: %s", strings.Join(missingModules, ", "))
 	}
-	a.D("Detected Package Manager: %s", platform.PackageManager)
-	a.D("")
 
-	results := make([]*DoctorResult, 0)
+	return nil
+}
+
+func repairGit(a *app.AppContext, opts *DoctorOptions) error {
+	a.WriteLn("Installing git...")
+
+	if cmd := getImmutableInstallCommand(a.Platform(), []string{"git"}, opts.PkgArgs); cmd != nil {
+		if err := runInstallCommandDirect(a, cmd[0], cmd[1:]...); err != nil {
+			return fmt.Errorf("failed to run %s: %w", cmd[0], err)
+		}
+
+		return errRebootRequired
+	}
 
-	// Check root/admin privileges
-	rootResult := checkRootPrivileges()
-	results = append(results, rootResult)
-	printResult(a, rootResult)
+	cmds, err := getGitInstallCommand(a.Platform().PackageManager, opts.PkgArgs)
+	if err != nil {
+		return err
+	}
 
-	// Check git
-	gitResult := checkGit()
-	results = append(results, gitResult)
-	printResult(a, gitResult)
+	return runInstallCommands(a, cmds)
+}
 
-	// Check docker
-	dockerResult := checkDocker()
-	results = append(results, dockerResult)
-	printResult(a, dockerResult)
+func repairTimeZoneData(a *app.AppContext, opts *DoctorOptions) error {
+	a.WriteLn("Installing tzdata...")
 
-	// Check docker daemon status
-	dockerDaemonResult := checkDockerDaemon(dockerResult)
-	results = append(results, dockerDaemonResult)
-	printResult(a, dockerDaemonResult)
+	pkg := "tzdata"
+	if a.Platform().IsMicroOS() {
+		pkg = "timezone"
+	}
 
-	a.WriteLn("")
+	if cmd := getImmutableInstallCommand(a.Platform(), []string{pkg}, opts.PkgArgs); cmd != nil {
+		if err := runInstallCommandDirect(a, cmd[0], cmd[1:]...); err != nil {
+			return fmt.Errorf("failed to run %s: %w", cmd[0], err)
+		}
 
-	// Count issues
-	issues := 0
-	for _, r := range results {
-		if !r.Installed {
-			issues++
+		return errRebootRequired
+	}
+
+	cmds, err := getTimeZoneDataInstallCommand(a.Platform().PackageManager, opts.PkgArgs)
+	if err != nil {
+		return err
+	}
+
+	return runInstallCommands(a, cmds)
+}
+
+// reportDoctorResults writes the results of the checks, unless only the
+// summary is requested with --summary-only, and the final verdict, and
+// returns if problems should be repaired or the error of the verdict
+func reportDoctorResults(a *app.AppContext, opts *DoctorOptions, results []*DoctorResult) (bool, error) {
+	if !opts.SummaryOnly {
+		a.WriteTable(newDoctorResultsTable(a, results))
+		a.WriteLn("")
+	}
+
+	if opts.Bundle != "" {
+		if err := writeDiagnosticsBundle(a, opts, results); err != nil {
+			return false, err
 		}
+		a.WriteLn("")
 	}
 
-	if issues == 0 {
+	if note := getRootNote(utils.IsRoot(), opts); note != "" && !opts.SummaryOnly {
+		a.WriteF("%s %s", a.Status(app.StatusInfo), note)
+		a.WriteLn("")
+		a.WriteLn("")
+	}
+
+	// Count issues and warnings
+	issues, warnings := countDoctorIssues(results)
+
+	if issues == 0 && warnings == 0 {
 		a.WriteLn("All requirements satisfied!")
-		return
+		return false, nil
 	}
 
-	a.WriteF("Found %d issue(s).", issues)
-	a.WriteLn("")
+	if issues == 0 {
+		a.WriteF("All requirements satisfied, but found %d warning(s).", warnings)
+		a.WriteLn("")
 
-	if !opts.Repair {
+		if !opts.Repair {
+			return false, nil
+		}
+	} else {
+		a.WriteF("Found %d issue(s).", issues)
 		a.WriteLn("")
-		a.WriteLn("Run 'autark doctor --repair' to fix missing dependencies.")
-		os.Exit(1)
-		return
+
+		if !opts.Repair {
+			a.WriteLn("")
+			return false, fmt.Errorf("Run 'autark doctor --repair' to fix missing dependencies")
+		}
+	}
+
+	return true, nil
+}
+
+func runDoctor(a *app.AppContext, opts *DoctorOptions) error {
+	platform := a.Platform()
+
+	if opts.DockerVersion != "" {
+		if _, err := getDockerPackages(platform.PackageManager, opts.DockerVersion); err != nil {
+			return err
+		}
+	}
+
+	if err := validateDoctorSkip(opts.Skip); err != nil {
+		return err
+	}
+
+	if !opts.SummaryOnly {
+		a.WriteLn("Checking system requirements...")
+		a.WriteLn("")
+	}
+
+	a.D("Detected OS: %s", platform.OS)
+	a.D("Detected Arch: %s", platform.Arch)
+	if platform.ARMVariant != "" {
+		a.D("Detected ARM Variant: %s", platform.ARMVariant)
+	}
+	if platform.OS == utils.OSLinux {
+		a.D("Detected Linux Distro: %s (%s)", platform.LinuxDistro, platform.LinuxDistroID)
+
+		if !platform.Detected && a.Config().Verbose {
+			a.W("Could not detect the Linux distribution, because /etc/os-release is missing or unknown (e.g. in distroless or scratch containers). --repair will not work on this system.")
+		}
+	}
+	a.D("Detected Package Manager: %s", platform.PackageManager)
+	a.D("")
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		a.D("Skipping hostname resolution check: %s", err.Error())
+	}
+
+	run := &doctorRun{
+		a:        a,
+		opts:     opts,
+		hostname: hostname,
+		checks:   getDoctorChecks(),
+		results:  map[string]*DoctorResult{},
+	}
+	results := runDoctorChecks(run)
+
+	repair, err := reportDoctorResults(a, opts, results)
+	if !repair {
+		return err
 	}
 
 	// Check for root/admin privileges before attempting repair
 	if !utils.IsRoot() {
 		a.WriteLn("")
-		if runtime.GOOS == "windows" {
-			a.WriteErrLn("Error: --repair requires administrator privileges.")
-			a.WriteErrLn("Please run this command as Administrator.")
-		} else {
-			a.WriteErrLn("Error: --repair requires root privileges.")
-			a.WriteErrLn("Please run this command with sudo.")
-		}
-		os.Exit(1)
-		return
+		return newRootPrivilegesError(a, "--repair")
+	}
+
+	if run.needsRepair("executable location") {
+		a.W("Running as root from an unsafe location: %s", run.results["executable location"].Error.Error())
 	}
 
 	a.WriteLn("")
 	a.WriteLn("Attempting to repair...")
 	a.WriteLn("")
 
+	defer writeRepairAuditLog(a, opts)
+
 	repairErrors := 0
 
+	// Repair CA bundle first, which is required for the downloads of other repairs
+	if run.needsRepair("ca-certificates") {
+		if err := repairCACertificates(a, opts); err != nil {
+			a.WriteErrLn(fmt.Sprintf("Failed to install ca-certificates: %s", err.Error()))
+			repairErrors++
+		} else {
+			a.WriteLn("ca-certificates installed successfully.")
+		}
+	}
+
+	// Immutable systems install packages into a new snapshot,
+	// which is only active after a reboot
+	rebootRequired := false
+
 	// Repair git if needed
-	if !gitResult.Installed {
-		if err := repairGit(a); err != nil {
+	if run.needsRepair("git") {
+		if err := repairGit(a, opts); errors.Is(err, errRebootRequired) {
+			a.WriteLn("git installed into a new snapshot.")
+			rebootRequired = true
+		} else if err != nil {
 			a.WriteErrLn(fmt.Sprintf("Failed to install git: %s", err.Error()))
 			repairErrors++
 		} else {
@@ -622,9 +3110,25 @@ func runDoctor(a *app.AppContext, opts *DoctorOptions) {
 		}
 	}
 
+	// Repair time zone database if needed
+	if run.needsRepair("tzdata") {
+		if err := repairTimeZoneData(a, opts); errors.Is(err, errRebootRequired) {
+			a.WriteLn("tzdata installed into a new snapshot.")
+			rebootRequired = true
+		} else if err != nil {
+			a.WriteErrLn(fmt.Sprintf("Failed to install tzdata: %s", err.Error()))
+			repairErrors++
+		} else {
+			a.WriteLn("tzdata installed successfully.")
+		}
+	}
+
 	// Repair docker if needed
-	if !dockerResult.Installed {
-		if err := repairDocker(a); err != nil {
+	if run.needsRepair("docker") {
+		if err := repairDocker(a, opts); errors.Is(err, errRebootRequired) {
+			a.WriteLn("docker installed into a new snapshot.")
+			rebootRequired = true
+		} else if err != nil {
 			a.WriteErrLn(fmt.Sprintf("Failed to install docker: %s", err.Error()))
 			repairErrors++
 		} else {
@@ -633,42 +3137,144 @@ func runDoctor(a *app.AppContext, opts *DoctorOptions) {
 	}
 
 	// Start docker daemon if needed
-	if !dockerDaemonResult.Installed {
-		if err := ensureDockerDaemonRunning(a); err != nil {
+	if run.needsRepair("docker daemon") {
+		if err := repairDockerDaemon(a, opts, rebootRequired, ensureDockerDaemonRunning); err != nil {
 			a.WriteErrLn(fmt.Sprintf("Failed to start docker daemon: %s", err.Error()))
 			repairErrors++
 		}
 	}
 
+	// Add the hostname to the hosts file if needed
+	if run.needsRepair("hostname resolution") {
+		if !a.PromptYesNo(fmt.Sprintf("Would you like to add %s to %s?", hostname, utils.HostsFilePath()), true) {
+			a.WriteLn("Skipping hostname resolution.")
+		} else if err := repairHostnameResolution(a, hostname); err != nil {
+			a.WriteErrLn(fmt.Sprintf("Failed to add hostname: %s", err.Error()))
+			repairErrors++
+		}
+	}
+
+	// Load kernel modules if needed
+	if run.needsRepair("kernel modules") {
+		if err := repairKernelModules(a); err != nil {
+			a.WriteErrLn(fmt.Sprintf("Failed to load kernel modules: %s", err.Error()))
+			repairErrors++
+		} else {
+			a.WriteLn("kernel modules loaded successfully.")
+		}
+	}
+
+	if rebootRequired {
+		a.W("A reboot is required to activate the installed packages. Please reboot and run 'autark doctor' again.")
+	}
+
 	if repairErrors > 0 {
 		a.WriteLn("")
-		a.WriteErrF("Repair completed with %d error(s).", repairErrors)
-		a.WriteLn("")
-		os.Exit(1)
+		return fmt.Errorf("Repair completed with %d error(s)", repairErrors)
 	}
 
 	a.WriteLn("")
 	a.WriteLn("Repair completed successfully.")
+
+	return nil
+}
+
+// runDoctorChecks runs the checks, which have not been skipped with
+// --skip, and returns their results in the order of their definitions
+func runDoctorChecks(r *doctorRun) []*DoctorResult {
+	results := make([]*DoctorResult, 0, len(r.checks))
+
+	for _, check := range r.checks {
+		if isDoctorCheckSkipped(r.opts.Skip, check.name) {
+			r.a.D("Skipping %s check because of --skip", check.name)
+			continue
+		}
+
+		if result := r.result(check.name); result != nil {
+			results = append(results, result)
+		}
+	}
+
+	return results
 }
 
-func runInstallCommand(name string, args ...string) error {
-	// Handle commands with shell operators
-	cmdStr := name + " " + strings.Join(args, " ")
-	if strings.Contains(cmdStr, "&&") || strings.Contains(cmdStr, "|") {
-		cmd := exec.Command("sh", "-c", cmdStr)
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
-		return cmd.Run()
+// runAptCommand runs an install command of installDockerDebian and
+// retries it for a bounded time, as long as the dpkg lock is held
+// by another process, like unattended-upgrades
+func runAptCommand(a *app.AppContext, name string, args ...string) error {
+	var output []byte
+	var err error
+	waiting := false
+
+	pollErr := utils.PollUntil(aptLockTimeout, aptLockRetryInterval, func() (bool, error) {
+		output, err = runInstallCommandCaptured(a, name, args...)
+		if err == nil || !isAptLockError(string(output)) {
+			return true, nil
+		}
+
+		if !waiting {
+			a.W("The dpkg lock is held by another process, like unattended-upgrades. Waiting up to %s...", aptLockTimeout)
+			waiting = true
+		}
+		return false, err
+	})
+	if pollErr != nil {
+		return fmt.Errorf("%w: the dpkg lock is still held by another process, please wait until it has finished (see 'ps aux | grep -i apt') and run 'autark doctor --repair' again", newInstallCommandError(name, err, output))
+	}
+	if err != nil {
+		return newInstallCommandError(name, err, output)
 	}
 
-	return runInstallCommandDirect(name, args...)
+	return nil
+}
+
+// runInstallCommandCaptured runs an install command, which writes its output
+// to standard output of the app and also returns it for later analysis
+func runInstallCommandCaptured(a *app.AppContext, name string, args ...string) ([]byte, error) {
+	a.D("Running: %s", utils.RedactCommandLine(name, args...))
+
+	output, err := utils.RunCommandCombinedToWriter(a.Stdout(), name, args...)
+	a.RecordCommand(name, args, err)
+	return output, err
 }
 
-func runInstallCommandDirect(name string, args ...string) error {
+func runInstallCommandDirect(a *app.AppContext, name string, args ...string) error {
 	cmd := exec.Command(name, args...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
-	return cmd.Run()
+
+	err := cmd.Run()
+	a.RecordCommand(name, args, err)
+	return err
+}
+
+// runInstallCommands runs install commands one after another and stops
+// on the first failure
+//
+// No shell is involved, so the extra package manager arguments of
+// --pkg-arg are passed unchanged, even with spaces or shell characters
+func runInstallCommands(a *app.AppContext, cmds [][]string) error {
+	for _, cmd := range cmds {
+		if err := runInstallCommandDirect(a, cmd[0], cmd[1:]...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// selectDockerProvider returns the provider of docker on macOS, where
+// Docker Desktop is preferred over Colima, or an empty string if
+// none of them is installed
+func selectDockerProvider(desktopInstalled bool, colimaInstalled bool) string {
+	if desktopInstalled {
+		return dockerProviderDesktop
+	}
+	if colimaInstalled {
+		return dockerProviderColima
+	}
+
+	return ""
 }
 
 func startDockerDaemon(a *app.AppContext) error {
@@ -685,10 +3291,11 @@ func startDockerDaemon(a *app.AppContext) error {
 }
 
 func startDockerDaemonLinux(a *app.AppContext) error {
-	// Try systemd first (most common)
-	if utils.CommandExists("systemctl") {
+	// Try systemd first (most common), but not if systemctl is
+	// installed without systemd running, like in containers
+	if a.Platform().Capabilities().HasSystemd {
 		a.D("Attempting to start docker via systemctl...")
-		if err := runInstallCommandDirect("systemctl", "start", "docker"); err == nil {
+		if err := runInstallCommandDirect(a, "systemctl", "start", "docker"); err == nil {
 			return nil
 		}
 	}
@@ -696,7 +3303,7 @@ func startDockerDaemonLinux(a *app.AppContext) error {
 	// Try OpenRC (Alpine, Gentoo)
 	if utils.CommandExists("rc-service") {
 		a.D("Attempting to start docker via rc-service...")
-		if err := runInstallCommandDirect("rc-service", "docker", "start"); err == nil {
+		if err := runInstallCommandDirect(a, "rc-service", "docker", "start"); err == nil {
 			return nil
 		}
 	}
@@ -704,7 +3311,7 @@ func startDockerDaemonLinux(a *app.AppContext) error {
 	// Try service command (generic fallback)
 	if utils.CommandExists("service") {
 		a.D("Attempting to start docker via service...")
-		if err := runInstallCommandDirect("service", "docker", "start"); err == nil {
+		if err := runInstallCommandDirect(a, "service", "docker", "start"); err == nil {
 			return nil
 		}
 	}
@@ -712,7 +3319,9 @@ func startDockerDaemonLinux(a *app.AppContext) error {
 	// Try starting dockerd directly as last resort
 	a.D("Attempting to start dockerd directly...")
 	cmd := exec.Command("dockerd")
-	if err := cmd.Start(); err != nil {
+	err := cmd.Start()
+	a.RecordCommand("dockerd", nil, err)
+	if err != nil {
 		return fmt.Errorf("could not start docker daemon: %w", err)
 	}
 
@@ -721,14 +3330,33 @@ func startDockerDaemonLinux(a *app.AppContext) error {
 }
 
 func startDockerDaemonDarwin(a *app.AppContext) error {
-	a.D("Attempting to start Docker Desktop on macOS...")
+	provider := getDarwinDockerProvider()
 
-	// Try to open Docker Desktop
-	if err := runInstallCommandDirect("open", "-a", "Docker"); err != nil {
-		return fmt.Errorf("failed to start Docker Desktop: %w", err)
+	switch provider {
+	case dockerProviderDesktop:
+		a.D("Attempting to start Docker Desktop on macOS...")
+
+		// Try to open Docker Desktop
+		if err := runInstallCommandDirect(a, "open", "-a", "Docker"); err != nil {
+			return fmt.Errorf("failed to start Docker Desktop: %w", err)
+		}
+	case dockerProviderColima:
+		a.D("Attempting to start Colima on macOS...")
+
+		if err := runInstallCommandDirect(a, "colima", "start"); err != nil {
+			return fmt.Errorf("failed to start Colima: %w", err)
+		}
+	default:
+		return fmt.Errorf("neither Docker Desktop nor Colima is installed")
 	}
 
-	a.WriteLn("Docker Desktop is starting. Please wait for it to initialize...")
+	a.WriteString(fmt.Sprintf("%s is starting. Waiting for it to initialize...", provider))
+	err := waitForDockerDaemon(a, dockerDesktopStartTimeout, dockerDesktopStartInterval, isDockerDaemonRunning)
+	a.WriteLn("")
+
+	if err != nil {
+		return fmt.Errorf("%s is not ready: %w", provider, err)
+	}
 	return nil
 }
 
@@ -737,10 +3365,124 @@ func startDockerDaemonWindows(a *app.AppContext) error {
 
 	// Try to start Docker Desktop via PowerShell
 	cmd := exec.Command("powershell", "-Command", "Start-Process 'C:\\Program Files\\Docker\\Docker\\Docker Desktop.exe'")
-	if err := cmd.Run(); err != nil {
+	err := cmd.Run()
+	a.RecordCommand("powershell", cmd.Args[1:], err)
+	if err != nil {
 		return fmt.Errorf("failed to start Docker Desktop: %w", err)
 	}
 
 	a.WriteLn("Docker Desktop is starting. Please wait for it to initialize...")
 	return nil
 }
+
+// unescapeMountField replaces the octal escapes of a field of
+// /proc/mounts, like "\\040" for a space
+func unescapeMountField(field string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(field); i++ {
+		if field[i] == '\\' && i+3 < len(field) {
+			if value, err := strconv.ParseUint(field[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(value))
+				i += 3
+				continue
+			}
+		}
+
+		b.WriteByte(field[i])
+	}
+
+	return b.String()
+}
+
+// validateDoctorSkip checks if all names of --skip are
+// names of known checks
+func validateDoctorSkip(skip []string) error {
+	names := getDoctorCheckNames()
+
+	for _, name := range skip {
+		if !slices.ContainsFunc(names, func(known string) bool {
+			return strings.EqualFold(known, name)
+		}) {
+			return fmt.Errorf("unknown check for --skip: %q (known checks: %s)", name, strings.Join(names, ", "))
+		}
+	}
+
+	return nil
+}
+
+// verifyRegistryIdentity checks with the Docker-Distribution-Api-Version
+// header of the /v2/ endpoint, if a Docker registry answers on baseURL
+//
+// The header is also sent, if the registry requires authentication
+func verifyRegistryIdentity(client *http.Client, baseURL string) error {
+	resp, err := client.Get(strings.TrimRight(baseURL, "/") + "/v2/")
+	if err != nil {
+		return fmt.Errorf("registry does not answer: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.Header.Get(registryAPIVersionHeader) != "" {
+		return nil
+	}
+
+	service := "another service"
+	if server := resp.Header.Get("Server"); server != "" {
+		service = fmt.Sprintf("another service (%s)", server)
+	}
+
+	return fmt.Errorf("%s header is missing, the port seems to be served by %s, like the AirPlay Receiver of macOS", registryAPIVersionHeader, service)
+}
+
+// waitForDockerDaemon waits until isRunning, which checks if
+// "docker info" succeeds, returns true and writes a dot for
+// each failed check as progress
+func waitForDockerDaemon(a *app.AppContext, timeout time.Duration, interval time.Duration, isRunning func() bool) error {
+	return utils.PollUntil(timeout, interval, func() (bool, error) {
+		if isRunning() {
+			return true, nil
+		}
+
+		a.WriteString(".")
+		return false, nil
+	})
+}
+
+// writeRepairAuditLog writes the commands, which have been run
+// by --repair, with their exit codes, as table or as JSON
+func writeRepairAuditLog(a *app.AppContext, opts *DoctorOptions) {
+	entries := a.AuditLog()
+
+	a.WriteLn("")
+
+	if opts.JSON {
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			a.W("Failed to write the executed commands: %s", err.Error())
+			return
+		}
+
+		a.WriteLn(string(data))
+		return
+	}
+
+	if len(entries) == 0 {
+		a.WriteLn("No commands have been executed.")
+		return
+	}
+
+	a.WriteLn("Executed commands:")
+	a.WriteLn("")
+
+	table := app.NewTable("STATUS", "EXIT CODE", "COMMAND")
+	table.Colorize = a.StatusColors(0)
+	for _, entry := range entries {
+		status := a.Status(app.StatusOK)
+		if entry.ExitCode != 0 {
+			status = a.Status(app.StatusError)
+		}
+
+		table.AddRow(status, strconv.Itoa(entry.ExitCode), entry.Command)
+	}
+	a.WriteTable(table)
+}
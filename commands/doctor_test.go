@@ -0,0 +1,1231 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package commands
+
+import (
+	"errors"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf16"
+
+	"github.com/mkloubert/autark/app"
+	"github.com/mkloubert/autark/utils"
+	"github.com/spf13/pflag"
+)
+
+func TestParseBuildxVersion(t *testing.T) {
+	tests := []struct {
+		output string
+		want   string
+	}{
+		{output: "github.com/docker/buildx v0.12.1 d4f088e", want: "v0.12.1"},
+		{output: "github.com/docker/buildx 0.11.2+azure-1 9872040b6626fb7d87ef7296fd5b832e8cc2ad17", want: "v0.11.2"},
+		{output: "github.com/docker/buildx v0.10.0-docker\n", want: "v0.10.0"},
+		{output: "docker: 'buildx' is not a docker command.", want: ""},
+		{output: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := parseBuildxVersion(tt.output); got != tt.want {
+			t.Errorf("parseBuildxVersion(%q) = %q, want %q", tt.output, got, tt.want)
+		}
+	}
+}
+
+func TestIsBuildKitDisabled(t *testing.T) {
+	for _, value := range []string{"0", "false", "FALSE", " no ", "off"} {
+		if !isBuildKitDisabled(value) {
+			t.Errorf("isBuildKitDisabled(%q) = false, want true", value)
+		}
+	}
+	for _, value := range []string{"", "1", "true"} {
+		if isBuildKitDisabled(value) {
+			t.Errorf("isBuildKitDisabled(%q) = true, want false", value)
+		}
+	}
+}
+
+func TestParseLsmodOutput(t *testing.T) {
+	lsmod := `Module                  Size  Used by
+overlay               151552  0
+br_netfilter           32768  0
+bridge                311296  1 br_netfilter
+`
+	procModules := "overlay 151552 0 - Live 0x0000000000000000\nbridge 311296 1 br_netfilter, Live 0x0000000000000000\n"
+
+	tests := []struct {
+		name   string
+		output string
+		want   []string
+		absent []string
+	}{
+		{name: "lsmod", output: lsmod, want: []string{"overlay", "br_netfilter", "bridge"}, absent: []string{"Module", "nf_nat"}},
+		{name: "/proc/modules", output: procModules, want: []string{"overlay", "bridge"}, absent: []string{"br_netfilter"}},
+		{name: "empty", output: "", absent: []string{"overlay"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			modules := parseLsmodOutput(tt.output)
+
+			if len(modules) != len(tt.want) {
+				t.Errorf("parseLsmodOutput() returned %d modules, want %d", len(modules), len(tt.want))
+			}
+			for _, name := range tt.want {
+				if !modules[name] {
+					t.Errorf("module %s is missing", name)
+				}
+			}
+			for _, name := range tt.absent {
+				if modules[name] {
+					t.Errorf("module %s should not be loaded", name)
+				}
+			}
+		})
+	}
+}
+
+func TestInstallCommandsWithPkgArgs(t *testing.T) {
+	pkgArgs := []string{"--no-install-recommends", "-o Acquire::http::Proxy=http://proxy:3128", "; rm -rf /"}
+
+	builders := map[string]func(utils.PackageManager, []string) ([][]string, error){
+		"ca-certificates": getCACertificatesInstallCommand,
+		"git":             getGitInstallCommand,
+		"tzdata":          getTimeZoneDataInstallCommand,
+	}
+
+	for pkg, build := range builders {
+		t.Run(pkg, func(t *testing.T) {
+			cmds, err := build(utils.PkgMgrApt, pkgArgs)
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+
+			if len(cmds) != 2 {
+				t.Fatalf("got %d commands, want apt-get update and apt-get install", len(cmds))
+			}
+			if want := []string{"apt-get", "update", "-qq"}; !slices.Equal(cmds[0], want) {
+				t.Errorf("first command = %v, want %v", cmds[0], want)
+			}
+
+			install := cmds[1]
+			if install[0] != "apt-get" || install[1] != "install" {
+				t.Errorf("second command = %v, want apt-get install", install)
+			}
+			if got := install[len(install)-len(pkgArgs):]; !slices.Equal(got, pkgArgs) {
+				t.Errorf("extra arguments = %q, want %q", got, pkgArgs)
+			}
+
+			for _, cmd := range cmds {
+				if slices.Contains(cmd, "&&") {
+					t.Errorf("command %v contains a shell operator", cmd)
+				}
+			}
+
+			cmds, err = build(utils.PkgMgrDnf, pkgArgs)
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if len(cmds) != 1 || !slices.Equal(cmds[0][len(cmds[0])-len(pkgArgs):], pkgArgs) {
+				t.Errorf("dnf commands = %q, want one command ending with %q", cmds, pkgArgs)
+			}
+		})
+	}
+}
+
+func TestParseAptCachePolicy(t *testing.T) {
+	output := `docker-ce:
+  Installed: 5:24.0.7-1~ubuntu.22.04~jammy
+  Candidate: 5:25.0.0-1~ubuntu.22.04~jammy
+  Version table:
+     5:25.0.0-1~ubuntu.22.04~jammy 500
+        500 https://download.docker.com/linux/ubuntu jammy/stable amd64 Packages
+ *** 5:24.0.7-1~ubuntu.22.04~jammy 500
+        500 https://download.docker.com/linux/ubuntu jammy/stable amd64 Packages
+        100 /var/lib/dpkg/status
+     5:24.0.6-1~ubuntu.22.04~jammy 500
+        500 https://download.docker.com/linux/ubuntu jammy/stable amd64 Packages
+`
+
+	policy := parseAptCachePolicy(output)
+	if policy.Installed != "5:24.0.7-1~ubuntu.22.04~jammy" {
+		t.Errorf("Installed = %q", policy.Installed)
+	}
+
+	wantSources := []string{
+		"https://download.docker.com/linux/ubuntu jammy/stable amd64 Packages",
+		"/var/lib/dpkg/status",
+	}
+	if !slices.Equal(policy.Sources, wantSources) {
+		t.Errorf("Sources = %q, want %q", policy.Sources, wantSources)
+	}
+
+	notInstalled := parseAptCachePolicy("docker.io:\n  Installed: (none)\n  Candidate: 24.0.5-0ubuntu1\n")
+	if notInstalled.Installed != "" || len(notInstalled.Sources) != 0 {
+		t.Errorf("parseAptCachePolicy() = %+v, want a package, which is not installed", notInstalled)
+	}
+}
+
+func TestParseIptablesBackend(t *testing.T) {
+	tests := []struct {
+		output string
+		want   string
+	}{
+		{output: "iptables v1.8.7 (nf_tables)", want: "nf_tables"},
+		{output: "iptables v1.8.7 (legacy)\n", want: "legacy"},
+		{output: "iptables v1.6.1", want: "legacy"},
+		{output: "command not found", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := parseIptablesBackend(tt.output); got != tt.want {
+			t.Errorf("parseIptablesBackend(%q) = %q, want %q", tt.output, got, tt.want)
+		}
+	}
+}
+
+func TestHasIptablesRules(t *testing.T) {
+	empty := "# Generated by iptables-save v1.8.7\n*filter\n:INPUT ACCEPT [0:0]\nCOMMIT\n"
+	if hasIptablesRules(empty) {
+		t.Error("hasIptablesRules() = true for tables without rules")
+	}
+
+	withRules := "*filter\n:INPUT ACCEPT [0:0]\n-A INPUT -p tcp --dport 22 -j ACCEPT\nCOMMIT\n"
+	if !hasIptablesRules(withRules) {
+		t.Error("hasIptablesRules() = false for a table with a rule")
+	}
+}
+
+func TestGetRootNote(t *testing.T) {
+	tests := []struct {
+		name     string
+		isRoot   bool
+		repair   bool
+		wantNote bool
+	}{
+		{name: "root without --repair", isRoot: true, repair: false, wantNote: true},
+		{name: "root with --repair", isRoot: true, repair: true, wantNote: false},
+		{name: "user without --repair", isRoot: false, repair: false, wantNote: false},
+		{name: "user with --repair", isRoot: false, repair: true, wantNote: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			note := getRootNote(tt.isRoot, &DoctorOptions{Repair: tt.repair})
+			if (note != "") != tt.wantNote {
+				t.Errorf("getRootNote() = %q, want note: %v", note, tt.wantNote)
+			}
+		})
+	}
+}
+
+func TestIsEntropyLow(t *testing.T) {
+	tests := []struct {
+		entropy int
+		want    bool
+	}{
+		{entropy: 0, want: true},
+		{entropy: minEntropy - 1, want: true},
+		{entropy: minEntropy, want: false},
+		{entropy: 256, want: false},
+		{entropy: 3500, want: false},
+	}
+
+	for _, tt := range tests {
+		if got := isEntropyLow(tt.entropy); got != tt.want {
+			t.Errorf("isEntropyLow(%d) = %v, want %v", tt.entropy, got, tt.want)
+		}
+	}
+}
+
+func TestGetDockerPackages(t *testing.T) {
+	tests := []struct {
+		name    string
+		pkgMgr  utils.PackageManager
+		version string
+		want    []string
+		wantErr bool
+	}{
+		{name: "latest", pkgMgr: utils.PkgMgrPacman, version: "", want: []string{"docker-ce", "docker-ce-cli"}},
+		{name: "apt", pkgMgr: utils.PkgMgrApt, version: "5:27.3.1-1~ubuntu.24.04~noble", want: []string{"docker-ce=5:27.3.1-1~ubuntu.24.04~noble", "docker-ce-cli=5:27.3.1-1~ubuntu.24.04~noble"}},
+		{name: "dnf", pkgMgr: utils.PkgMgrDnf, version: "3:27.3.1-1.fc41", want: []string{"docker-ce-3:27.3.1-1.fc41", "docker-ce-cli-3:27.3.1-1.fc41"}},
+		{name: "invalid version", pkgMgr: utils.PkgMgrApt, version: "27.3.1; rm -rf /", wantErr: true},
+		{name: "unsupported package manager", pkgMgr: utils.PkgMgrPacman, version: "27.3.1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getDockerPackages(tt.pkgMgr, tt.version)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getDockerPackages() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("getDockerPackages() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetCABundlePaths(t *testing.T) {
+	tests := []struct {
+		distro utils.LinuxDistro
+		want   string
+	}{
+		{distro: utils.DistroDebian, want: "/etc/ssl/certs/ca-certificates.crt"},
+		{distro: utils.DistroUbuntu, want: "/etc/ssl/certs/ca-certificates.crt"},
+		{distro: utils.DistroFedora, want: "/etc/pki/tls/certs/ca-bundle.crt"},
+		{distro: utils.DistroRHEL, want: "/etc/pki/tls/certs/ca-bundle.crt"},
+		{distro: utils.DistroOpenSUSE, want: "/etc/ssl/ca-bundle.pem"},
+		{distro: utils.DistroAlpine, want: "/etc/ssl/certs/ca-certificates.crt"},
+	}
+
+	for _, tt := range tests {
+		if got := getCABundlePaths(tt.distro); !slices.Contains(got, tt.want) {
+			t.Errorf("getCABundlePaths(%q) = %q, want to contain %q", tt.distro, got, tt.want)
+		}
+	}
+}
+
+func TestGetCABundlePathsUnknownDistro(t *testing.T) {
+	got := getCABundlePaths(utils.DistroUnknown)
+
+	for _, want := range []string{"/etc/ssl/certs/ca-certificates.crt", "/etc/pki/tls/certs/ca-bundle.crt", "/etc/ssl/ca-bundle.pem"} {
+		if !slices.Contains(got, want) {
+			t.Errorf("getCABundlePaths(%q) = %q, want to contain %q", utils.DistroUnknown, got, want)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, path := range got {
+		if seen[path] {
+			t.Errorf("getCABundlePaths(%q) contains %q twice", utils.DistroUnknown, path)
+		}
+		seen[path] = true
+	}
+}
+
+func TestParseProcSwaps(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    int64
+	}{
+		{
+			name:    "no swap",
+			content: "Filename\t\t\t\tType\t\tSize\t\tUsed\t\tPriority\n",
+			want:    0,
+		},
+		{
+			name: "partition and file",
+			content: "Filename\t\t\t\tType\t\tSize\t\tUsed\t\tPriority\n" +
+				"/dev/sda2                               partition\t8388604\t\t0\t\t-2\n" +
+				"/swapfile                               file\t\t2097148\t\t1024\t\t-3\n",
+			want: 10485752,
+		},
+		{
+			name:    "empty",
+			content: "",
+			want:    0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseProcSwaps(tt.content); got != tt.want {
+				t.Errorf("parseProcSwaps() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSwappiness(t *testing.T) {
+	got, err := parseSwappiness("60\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 60 {
+		t.Errorf("parseSwappiness() = %d, want 60", got)
+	}
+
+	if _, err := parseSwappiness("sixty\n"); err == nil {
+		t.Error("parseSwappiness() error = nil for an invalid value")
+	}
+}
+
+// encodeUTF16LE encodes s as UTF-16 with byte order mark, like
+// older versions of wsl.exe write their output
+func encodeUTF16LE(s string) []byte {
+	units := utf16.Encode([]rune("\ufeff" + s))
+
+	data := make([]byte, 0, len(units)*2)
+	for _, unit := range units {
+		data = append(data, byte(unit), byte(unit>>8))
+	}
+	return data
+}
+
+func TestParseWSLStatus(t *testing.T) {
+	tests := []struct {
+		name          string
+		output        []byte
+		wantInstalled bool
+		wantVersion   string
+	}{
+		{
+			name:          "utf-8",
+			output:        []byte("Default Distribution: Ubuntu\nDefault Version: 2\n"),
+			wantInstalled: true,
+			wantVersion:   "2",
+		},
+		{
+			name:          "utf-16",
+			output:        encodeUTF16LE("Default Distribution: Ubuntu\r\nDefault Version: 1\r\n"),
+			wantInstalled: true,
+			wantVersion:   "1",
+		},
+		{
+			name:          "without version",
+			output:        []byte("Default Distribution: Debian\n"),
+			wantInstalled: true,
+			wantVersion:   "",
+		},
+		{
+			name:          "not installed",
+			output:        []byte("The Windows Subsystem for Linux is not installed.\n"),
+			wantInstalled: false,
+			wantVersion:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := parseWSLStatus(tt.output)
+			if status.Installed != tt.wantInstalled || status.DefaultVersion != tt.wantVersion {
+				t.Errorf("parseWSLStatus() = %+v, want installed: %v, version: %q", status, tt.wantInstalled, tt.wantVersion)
+			}
+		})
+	}
+}
+
+func TestClassifyGitError(t *testing.T) {
+	tests := []struct {
+		output string
+		want   string
+	}{
+		{output: "fatal: unable to access 'https://github.com/git/git.git/': Could not resolve host: github.com", want: "DNS"},
+		{output: "fatal: unable to access 'https://github.com/git/git.git/': SSL certificate problem: unable to get local issuer certificate", want: "TLS"},
+		{output: "fatal: could not read Username for 'https://github.com': terminal prompts disabled", want: "auth"},
+		{output: "fatal: unable to access 'https://github.com/git/git.git/': The requested URL returned error: 403", want: "auth"},
+		{output: "fatal: unable to access 'https://github.com/git/git.git/': Failed to connect to github.com port 443 after 129 ms: Connection refused", want: "network"},
+		{output: "fatal: repository 'https://github.com/git/nope.git/' not found", want: "unknown"},
+		{output: "", want: "unknown"},
+	}
+
+	for _, tt := range tests {
+		if got := classifyGitError(tt.output); got != tt.want {
+			t.Errorf("classifyGitError(%q) = %q, want %q", tt.output, got, tt.want)
+		}
+	}
+}
+
+func TestFindMount(t *testing.T) {
+	mounts := "sysfs /sys sysfs rw,nosuid,nodev,noexec,relatime 0 0\n" +
+		"/dev/sda1 / ext4 rw,relatime 0 0\n" +
+		"/dev/sdb1 /var/lib ext4 rw,relatime 0 0\n" +
+		"/dev/sdc1 /var/lib/docker xfs rw,relatime 0 0\n" +
+		"tmpfs /var/lib/docker tmpfs rw,nosuid 0 0\n" +
+		"/dev/sdd1 /mnt/data\\040disk btrfs ro,relatime 0 0\n"
+
+	tests := []struct {
+		path           string
+		wantMountPoint string
+		wantFSType     string
+	}{
+		{path: "/var/lib/docker", wantMountPoint: "/var/lib/docker", wantFSType: "tmpfs"},
+		{path: "/var/lib/docker/overlay2/", wantMountPoint: "/var/lib/docker", wantFSType: "tmpfs"},
+		{path: "/var/lib/dockerd", wantMountPoint: "/var/lib", wantFSType: "ext4"},
+		{path: "/home/user", wantMountPoint: "/", wantFSType: "ext4"},
+		{path: "/mnt/data disk/docker", wantMountPoint: "/mnt/data disk", wantFSType: "btrfs"},
+	}
+
+	for _, tt := range tests {
+		mount := findMount(mounts, tt.path)
+		if mount == nil {
+			t.Errorf("findMount(%q) = nil, want %s", tt.path, tt.wantMountPoint)
+			continue
+		}
+		if mount.MountPoint != tt.wantMountPoint || mount.FSType != tt.wantFSType {
+			t.Errorf("findMount(%q) = %s (%s), want %s (%s)", tt.path, mount.MountPoint, mount.FSType, tt.wantMountPoint, tt.wantFSType)
+		}
+	}
+
+	if mount := findMount("", "/var/lib/docker"); mount != nil {
+		t.Errorf("findMount() = %+v for an empty mount table, want nil", mount)
+	}
+}
+
+func TestUnescapeMountField(t *testing.T) {
+	tests := []struct {
+		field string
+		want  string
+	}{
+		{field: "/var/lib/docker", want: "/var/lib/docker"},
+		{field: "/mnt/my\\040disk", want: "/mnt/my disk"},
+		{field: "/mnt/tab\\011and\\134backslash", want: "/mnt/tab\tand\\backslash"},
+		{field: "/mnt/broken\\04", want: "/mnt/broken\\04"},
+		{field: "/mnt/invalid\\999", want: "/mnt/invalid\\999"},
+	}
+
+	for _, tt := range tests {
+		if got := unescapeMountField(tt.field); got != tt.want {
+			t.Errorf("unescapeMountField(%q) = %q, want %q", tt.field, got, tt.want)
+		}
+	}
+}
+
+func TestIsAptLockError(t *testing.T) {
+	tests := []struct {
+		stderr string
+		want   bool
+	}{
+		{stderr: "E: Could not get lock /var/lib/dpkg/lock-frontend. It is held by process 1234 (unattended-upgr)\nN: Be aware that removing the lock file is not a solution and may break your system.\nE: Unable to acquire the dpkg frontend lock (/var/lib/dpkg/lock-frontend), is another process using it?\n", want: true},
+		{stderr: "E: Could not get lock /var/lib/apt/lists/lock. It is held by process 4321 (apt-get)\nE: Unable to lock directory /var/lib/apt/lists/\n", want: true},
+		{stderr: "E: Konnte Sperre /var/lib/dpkg/lock-frontend nicht bekommen. Sie wird von Prozess 1234 (unattended-upgr) gehalten.\n", want: true},
+		{stderr: "E: Impossible d'obtenir le verrou /var/lib/apt/lists/lock. Il est tenu par le processus 4321 (apt-get)\n", want: true},
+		{stderr: "E: Unable to locate package docker-ce\n", want: false},
+		{stderr: "", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := isAptLockError(tt.stderr); got != tt.want {
+			t.Errorf("isAptLockError(%q) = %v, want %v", tt.stderr, got, tt.want)
+		}
+	}
+}
+
+func TestRepairDockerDaemon(t *testing.T) {
+	errStart := errors.New("start failed")
+
+	tests := []struct {
+		name           string
+		opts           *DoctorOptions
+		rebootRequired bool
+		wantStarted    bool
+		wantErr        error
+	}{
+		{name: "start", opts: &DoctorOptions{}, wantStarted: true, wantErr: errStart},
+		{name: "--skip-daemon-start", opts: &DoctorOptions{SkipDaemonStart: true}, wantStarted: false},
+		{name: "reboot required", opts: &DoctorOptions{}, rebootRequired: true, wantStarted: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			started := false
+			start := func(a *app.AppContext) error {
+				started = true
+				return errStart
+			}
+
+			err := repairDockerDaemon(newTestAppContext(t), tt.opts, tt.rebootRequired, start)
+			if started != tt.wantStarted {
+				t.Errorf("start called = %v, want %v", started, tt.wantStarted)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("repairDockerDaemon() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWaitForDockerDaemon(t *testing.T) {
+	checks := 0
+	isRunning := func() bool {
+		checks++
+		return checks == 3
+	}
+
+	if err := waitForDockerDaemon(newTestAppContext(t), time.Second, time.Millisecond, isRunning); err != nil {
+		t.Fatalf("waitForDockerDaemon() error = %v", err)
+	}
+	if checks != 3 {
+		t.Errorf("checks = %d, want 3", checks)
+	}
+}
+
+func TestWaitForDockerDaemonTimeout(t *testing.T) {
+	isRunning := func() bool {
+		return false
+	}
+
+	if err := waitForDockerDaemon(newTestAppContext(t), 5*time.Millisecond, time.Millisecond, isRunning); err == nil {
+		t.Error("waitForDockerDaemon() error = nil, want a timeout")
+	}
+}
+
+func TestSelectDockerProvider(t *testing.T) {
+	tests := []struct {
+		desktopInstalled bool
+		colimaInstalled  bool
+		want             string
+	}{
+		{desktopInstalled: true, colimaInstalled: true, want: dockerProviderDesktop},
+		{desktopInstalled: true, colimaInstalled: false, want: dockerProviderDesktop},
+		{desktopInstalled: false, colimaInstalled: true, want: dockerProviderColima},
+		{desktopInstalled: false, colimaInstalled: false, want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := selectDockerProvider(tt.desktopInstalled, tt.colimaInstalled); got != tt.want {
+			t.Errorf("selectDockerProvider(%v, %v) = %q, want %q", tt.desktopInstalled, tt.colimaInstalled, got, tt.want)
+		}
+	}
+}
+
+func TestParseDockerContext(t *testing.T) {
+	tests := []struct {
+		output       string
+		wantName     string
+		wantEndpoint string
+	}{
+		{output: "default unix:///var/run/docker.sock\n", wantName: "default", wantEndpoint: "unix:///var/run/docker.sock"},
+		{output: "prod ssh://deploy@prod.example.com\n", wantName: "prod", wantEndpoint: "ssh://deploy@prod.example.com"},
+		{output: "colima\n", wantName: "colima", wantEndpoint: ""},
+		{output: "", wantName: "", wantEndpoint: ""},
+	}
+
+	for _, tt := range tests {
+		name, endpoint := parseDockerContext(tt.output)
+		if name != tt.wantName || endpoint != tt.wantEndpoint {
+			t.Errorf("parseDockerContext(%q) = %q, %q, want %q, %q", tt.output, name, endpoint, tt.wantName, tt.wantEndpoint)
+		}
+	}
+}
+
+func TestParseResolvConf(t *testing.T) {
+	content := "# This is /run/systemd/resolve/stub-resolv.conf managed by man:systemd-resolved(8).\n" +
+		"; old style comment\n" +
+		"nameserver 127.0.0.53\n" +
+		"  nameserver   1.1.1.1  \n" +
+		"nameserver 2606:4700:4700::1111\n" +
+		"#nameserver 8.8.8.8\n" +
+		"options edns0 trust-ad\n" +
+		"search example.com\n" +
+		"nameserver\n"
+
+	want := []string{"127.0.0.53", "1.1.1.1", "2606:4700:4700::1111"}
+	if got := parseResolvConf(content); !slices.Equal(got, want) {
+		t.Errorf("parseResolvConf() = %q, want %q", got, want)
+	}
+
+	if got := parseResolvConf(""); len(got) != 0 {
+		t.Errorf("parseResolvConf(\"\") = %q, want no nameservers", got)
+	}
+}
+
+func TestIsWorldWritable(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permissions are not supported on Windows")
+	}
+
+	dir := t.TempDir()
+
+	tests := []struct {
+		perm os.FileMode
+		want bool
+	}{
+		{perm: 0o755, want: false},
+		{perm: 0o775, want: false},
+		{perm: 0o777, want: true},
+		{perm: 0o777 | os.ModeSticky, want: true},
+	}
+
+	for _, tt := range tests {
+		// Chmod is not affected by the umask
+		if err := os.Chmod(dir, tt.perm); err != nil {
+			t.Fatal(err)
+		}
+
+		info, err := os.Stat(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := isWorldWritable(info.Mode()); got != tt.want {
+			t.Errorf("isWorldWritable(%s) = %v, want %v", info.Mode(), got, tt.want)
+		}
+	}
+}
+
+func TestGetComposeConfigError(t *testing.T) {
+	errExit := errors.New("exit status 15")
+
+	tests := []struct {
+		name   string
+		output string
+		want   string
+	}{
+		{
+			name:   "line number",
+			output: "time=\"2026-01-02T03:04:05Z\" level=warning msg=\"version is obsolete\"\nyaml: line 3: mapping values are not allowed in this context\n",
+			want:   "yaml: line 3: mapping values are not allowed in this context",
+		},
+		{
+			name:   "without line number",
+			output: "\nservices.web Additional property imagee is not allowed\n",
+			want:   "services.web Additional property imagee is not allowed",
+		},
+		{
+			name:   "no output",
+			output: "",
+			want:   "exit status 15",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getComposeConfigError(tt.output, errExit); got != tt.want {
+				t.Errorf("getComposeConfigError() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsDockerHubLoggedIn(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		want    bool
+		wantErr bool
+	}{
+		{
+			name:   "auth",
+			config: `{"auths": {"https://index.docker.io/v1/": {"auth": "dXNlcjpwYXNz"}}}`,
+			want:   true,
+		},
+		{
+			name:   "credential store",
+			config: `{"auths": {"https://index.docker.io/v1/": {}}, "credsStore": "desktop"}`,
+			want:   true,
+		},
+		{
+			name:   "credential helper",
+			config: `{"credHelpers": {"https://index.docker.io/v1/": "pass"}}`,
+			want:   true,
+		},
+		{
+			name:   "other registry",
+			config: `{"auths": {"ghcr.io": {"auth": "dXNlcjpwYXNz"}}}`,
+			want:   false,
+		},
+		{
+			name:   "empty",
+			config: `{}`,
+			want:   false,
+		},
+		{
+			name:    "invalid",
+			config:  `{"auths":`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := isDockerHubLoggedIn([]byte(tt.config))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("isDockerHubLoggedIn() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("isDockerHubLoggedIn() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseAaStatus(t *testing.T) {
+	output := "apparmor module is loaded.\n" +
+		"5 profiles are loaded.\n" +
+		"3 profiles are in enforce mode.\n" +
+		"   /usr/bin/man\n" +
+		"   docker-default\n" +
+		"   lsb_release\n" +
+		"2 profiles are in complain mode.\n" +
+		"   /usr/sbin/sssd\n" +
+		"   snap.lxd.daemon\n" +
+		"0 profiles are in kill mode.\n" +
+		"2 processes have profiles defined.\n" +
+		"2 processes are in enforce mode.\n" +
+		"   /usr/sbin/cupsd (1234) \n" +
+		"0 processes are unconfined but have a profile defined.\n"
+
+	want := map[string]string{
+		"/usr/bin/man":    "enforce",
+		"docker-default":  "enforce",
+		"lsb_release":     "enforce",
+		"/usr/sbin/sssd":  "complain",
+		"snap.lxd.daemon": "complain",
+	}
+	if got := parseAaStatus(output); !maps.Equal(got, want) {
+		t.Errorf("parseAaStatus() = %v, want %v", got, want)
+	}
+
+	if got := parseAaStatus(""); len(got) != 0 {
+		t.Errorf("parseAaStatus(\"\") = %v, want no profiles", got)
+	}
+}
+
+func TestGetTransactionalUpdateCommand(t *testing.T) {
+	got := getTransactionalUpdateCommand([]string{"docker", "git"}, []string{"--auto-agree-with-licenses"})
+
+	want := []string{"transactional-update", "--non-interactive", "--continue", "pkg", "install", "--no-recommends", "docker", "git", "--auto-agree-with-licenses"}
+	if !slices.Equal(got, want) {
+		t.Errorf("getTransactionalUpdateCommand() = %q, want %q", got, want)
+	}
+}
+
+func TestGetRPMOSTreeInstallCommand(t *testing.T) {
+	got := getRPMOSTreeInstallCommand([]string{"moby-engine", "git"}, []string{"--apply-live"})
+
+	want := []string{"rpm-ostree", "install", "--idempotent", "--allow-inactive", "moby-engine", "git", "--apply-live"}
+	if !slices.Equal(got, want) {
+		t.Errorf("getRPMOSTreeInstallCommand() = %q, want %q", got, want)
+	}
+}
+
+func TestVerifyRegistryIdentity(t *testing.T) {
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/" {
+			t.Errorf("request path = %q, want /v2/", r.URL.Path)
+		}
+		w.Header().Set(registryAPIVersionHeader, "registry/2.0")
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer registry.Close()
+
+	if err := verifyRegistryIdentity(registry.Client(), registry.URL+"/"); err != nil {
+		t.Errorf("verifyRegistryIdentity() error = %v, want nil", err)
+	}
+
+	airPlay := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Server", "AirTunes/595.13.1")
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer airPlay.Close()
+
+	err := verifyRegistryIdentity(airPlay.Client(), airPlay.URL)
+	if err == nil || !strings.Contains(err.Error(), "AirTunes/595.13.1") {
+		t.Errorf("verifyRegistryIdentity() error = %v, want an error, which names the server", err)
+	}
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	err = verifyRegistryIdentity(http.DefaultClient, closed.URL)
+	if err == nil || !strings.Contains(err.Error(), "registry does not answer") {
+		t.Errorf("verifyRegistryIdentity() error = %v, want an error for an unreachable registry", err)
+	}
+}
+
+func TestCheckRegistryPortIdentity(t *testing.T) {
+	registry := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(registryAPIVersionHeader, "registry/2.0")
+	}))
+	defer registry.Close()
+
+	result := checkRegistryPortIdentity(&DoctorResult{Installed: true}, registry.Client(), registry.URL, 5000)
+	if !result.Installed || result.Error != nil || result.Version != "port 5000" {
+		t.Errorf("checkRegistryPortIdentity() = %+v, want an installed result for port 5000", result)
+	}
+
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer other.Close()
+
+	result = checkRegistryPortIdentity(&DoctorResult{Installed: true}, other.Client(), other.URL, 5000)
+	if result.Installed || result.Error == nil || !strings.Contains(result.Error.Error(), "port 5000") {
+		t.Errorf("checkRegistryPortIdentity() = %+v, want a failed result for port 5000", result)
+	}
+}
+
+func TestCheckHostnameResolution(t *testing.T) {
+	result := checkHostnameResolution("builder", func(host string) ([]string, error) {
+		if host != "builder" {
+			t.Errorf("lookupHost(%q), want %q", host, "builder")
+		}
+		return []string{"127.0.1.1", "::1"}, nil
+	})
+	if !result.Installed || result.Version != "builder -> 127.0.1.1, ::1" {
+		t.Errorf("checkHostnameResolution() = %+v, want a resolved hostname", result)
+	}
+
+	lookups := map[string]func(host string) ([]string, error){
+		"error": func(host string) ([]string, error) {
+			return nil, errors.New("no such host")
+		},
+		"no addresses": func(host string) ([]string, error) {
+			return nil, nil
+		},
+	}
+	for name, lookupHost := range lookups {
+		result := checkHostnameResolution("builder", lookupHost)
+		if result.Installed || result.Error == nil || !strings.Contains(result.Error.Error(), utils.HostsFilePath()) {
+			t.Errorf("%s: checkHostnameResolution() = %+v, want an error, which refers to the hosts file", name, result)
+		}
+	}
+}
+
+func TestCheckTimeZoneData(t *testing.T) {
+	dir := t.TempDir()
+	zoneinfo := filepath.Join(dir, "zoneinfo")
+	if err := os.Mkdir(zoneinfo, 0755); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(dir, "localtime")
+	if err := os.WriteFile(file, []byte("TZif"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: zoneinfo, want: true},
+		{path: file, want: false},
+		{path: filepath.Join(dir, "missing"), want: false},
+	}
+
+	for _, tt := range tests {
+		if got := isZoneinfoDir(tt.path); got != tt.want {
+			t.Errorf("isZoneinfoDir(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+
+		result := checkTimeZoneData(tt.path)
+		if result.Installed != tt.want || !result.Optional {
+			t.Errorf("checkTimeZoneData(%q) = %+v, want Installed = %v", tt.path, result, tt.want)
+		}
+		if !tt.want && (result.Error == nil || !strings.Contains(result.Error.Error(), tt.path)) {
+			t.Errorf("checkTimeZoneData(%q) error = %v, want an error, which names the path", tt.path, result.Error)
+		}
+	}
+}
+
+func TestRunDoctorChecksSkip(t *testing.T) {
+	ran := make([]string, 0)
+	check := func(name string, installed bool, optional bool) doctorCheck {
+		return doctorCheck{name: name, run: func(r *doctorRun) *DoctorResult {
+			ran = append(ran, name)
+			return &DoctorResult{Name: name, Installed: installed, Optional: optional}
+		}}
+	}
+
+	run := &doctorRun{
+		a:    newTestAppContext(t),
+		opts: &DoctorOptions{Skip: []string{"Docker Hub Login", "docker"}},
+		checks: []doctorCheck{
+			check("git", false, false),
+			check("docker", false, false),
+			{name: "buildkit", run: func(r *doctorRun) *DoctorResult {
+				ran = append(ran, "buildkit")
+				return &DoctorResult{Name: "buildkit", Installed: r.result("docker").Installed, Optional: true}
+			}},
+			check("docker hub login", false, true),
+			{name: "not applicable", run: func(r *doctorRun) *DoctorResult {
+				return nil
+			}},
+		},
+		results: map[string]*DoctorResult{},
+	}
+
+	results := runDoctorChecks(run)
+
+	names := make([]string, 0, len(results))
+	for _, r := range results {
+		names = append(names, r.Name)
+	}
+	if want := []string{"git", "buildkit"}; !slices.Equal(names, want) {
+		t.Errorf("runDoctorChecks() = %q, want %q", names, want)
+	}
+
+	// docker is run for buildkit, but the skipped network check is not run at all
+	if want := []string{"git", "buildkit", "docker"}; !slices.Equal(ran, want) {
+		t.Errorf("checks, which have run = %q, want %q", ran, want)
+	}
+
+	if issues, warnings := countDoctorIssues(results); issues != 1 || warnings != 1 {
+		t.Errorf("countDoctorIssues() = %d, %d, want 1, 1", issues, warnings)
+	}
+
+	if run.needsRepair("docker") {
+		t.Error("needsRepair(\"docker\") = true, want false for a skipped check")
+	}
+	if !run.needsRepair("git") {
+		t.Error("needsRepair(\"git\") = false, want true for a failed check")
+	}
+	if run.results["docker"].Installed {
+		t.Error("the result of the skipped docker check has been changed")
+	}
+}
+
+func TestValidateDoctorSkip(t *testing.T) {
+	names := getDoctorCheckNames()
+	if !slices.Contains(names, "docker hub login") || !slices.Contains(names, "WSL 2 / Hyper-V") {
+		t.Errorf("getDoctorCheckNames() = %q, want all checks", names)
+	}
+	if len(slices.Compact(slices.Sorted(slices.Values(names)))) != len(names) {
+		t.Errorf("getDoctorCheckNames() = %q, want unique names", names)
+	}
+
+	if err := validateDoctorSkip([]string{"Docker Hub Login", "swap"}); err != nil {
+		t.Errorf("validateDoctorSkip() error = %v, want nil", err)
+	}
+	if err := validateDoctorSkip([]string{"swapp"}); err == nil {
+		t.Error("validateDoctorSkip(\"swapp\") error = nil, want an error")
+	}
+}
+
+func TestParseDockerDaemonConfig(t *testing.T) {
+	config, err := parseDockerDaemonConfig([]byte(`{
+	"registry-mirrors": ["https://mirror.gcr.io"],
+	"insecure-registries": ["192.168.1.20:5000", "10.0.0.0/8"],
+	"log-driver": "json-file"
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"https://mirror.gcr.io"}; !slices.Equal(config.RegistryMirrors, want) {
+		t.Errorf("RegistryMirrors = %q, want %q", config.RegistryMirrors, want)
+	}
+	if want := []string{"192.168.1.20:5000", "10.0.0.0/8"}; !slices.Equal(config.InsecureRegistries, want) {
+		t.Errorf("InsecureRegistries = %q, want %q", config.InsecureRegistries, want)
+	}
+
+	for _, data := range []string{"", " \n"} {
+		config, err := parseDockerDaemonConfig([]byte(data))
+		if err != nil || config == nil || len(config.InsecureRegistries) != 0 {
+			t.Errorf("parseDockerDaemonConfig(%q) = %+v, %v, want an empty config", data, config, err)
+		}
+	}
+
+	if _, err := parseDockerDaemonConfig([]byte(`{"insecure-registries": `)); err == nil {
+		t.Error("parseDockerDaemonConfig() error = nil, want an error for invalid JSON")
+	}
+}
+
+func TestParseCPUInfoVirtualizationFlag(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "intel",
+			content: "processor\t: 0\nvendor_id\t: GenuineIntel\nflags\t\t: fpu vme de pse vmx smx est\n\nprocessor\t: 1\nflags\t\t: fpu vme de pse vmx smx est\n",
+			want:    "vmx",
+		},
+		{
+			name:    "amd",
+			content: "processor\t: 0\nvendor_id\t: AuthenticAMD\nflags\t\t: fpu vme svm extapic\n",
+			want:    "svm",
+		},
+		{
+			name:    "vm without nested virtualization",
+			content: "processor\t: 0\nflags\t\t: fpu vme de pse hypervisor\n",
+			want:    "",
+		},
+		{
+			name:    "arm",
+			content: "processor\t: 0\nFeatures\t: fp asimd evtstrm\n",
+			want:    "",
+		},
+		{
+			name:    "flag name in another field",
+			content: "model name\t: svm vmx\nflags\t\t: fpu\n",
+			want:    "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseCPUInfoVirtualizationFlag(tt.content); got != tt.want {
+				t.Errorf("parseCPUInfoVirtualizationFlag() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// newTestAppContextWithOutput creates an app for tests, which writes
+// its standard output to a file, whose content is returned by output
+func newTestAppContextWithOutput(t *testing.T) (*app.AppContext, func() string) {
+	t.Helper()
+
+	file, err := os.Create(filepath.Join(t.TempDir(), "stdout"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { file.Close() })
+
+	stdout := os.Stdout
+	os.Stdout = file
+	defer func() { os.Stdout = stdout }()
+
+	a := newTestAppContext(t)
+
+	return a, func() string {
+		data, err := os.ReadFile(file.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+}
+
+func TestReportDoctorResultsSummaryOnly(t *testing.T) {
+	results := []*DoctorResult{
+		{Name: "git", Installed: true, Version: "git version 2.43.0"},
+		{Name: "docker", Installed: false},
+		{Name: "swap", Installed: false, Optional: true},
+	}
+
+	for _, summaryOnly := range []bool{false, true} {
+		a, output := newTestAppContextWithOutput(t)
+
+		repair, err := reportDoctorResults(a, &DoctorOptions{SummaryOnly: summaryOnly}, results)
+		if repair || err == nil {
+			t.Errorf("SummaryOnly = %v: reportDoctorResults() = %v, %v, want false and an error for the exit code", summaryOnly, repair, err)
+		}
+
+		got := output()
+		if !strings.Contains(got, "Found 1 issue(s).") {
+			t.Errorf("SummaryOnly = %v: output = %q, want the verdict", summaryOnly, got)
+		}
+		if hasCheckLines := strings.Contains(got, "git version 2.43.0"); hasCheckLines == summaryOnly {
+			t.Errorf("SummaryOnly = %v: output = %q, want check lines = %v", summaryOnly, got, !summaryOnly)
+		}
+	}
+}
+
+func TestReportDoctorResultsWarningsOnly(t *testing.T) {
+	a, output := newTestAppContextWithOutput(t)
+
+	results := []*DoctorResult{{Name: "swap", Installed: false, Optional: true}}
+
+	repair, err := reportDoctorResults(a, &DoctorOptions{SummaryOnly: true}, results)
+	if repair || err != nil {
+		t.Errorf("reportDoctorResults() = %v, %v, want false, nil", repair, err)
+	}
+	if got, want := output(), "All requirements satisfied, but found 1 warning(s).\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+
+	repair, err = reportDoctorResults(a, &DoctorOptions{SummaryOnly: true, Repair: true}, results)
+	if !repair || err != nil {
+		t.Errorf("reportDoctorResults() with --repair = %v, %v, want true, nil", repair, err)
+	}
+}
+
+// testMounts is a mount table of an immutable system,
+// whose /usr is mounted read-only
+const testMounts = `/dev/sda2 / btrfs rw,relatime,ssd 0 0
+proc /proc proc rw,nosuid,nodev,noexec,relatime 0 0
+/dev/sda2 /usr btrfs ro,relatime,ssd 0 0
+/dev/sda3 /mnt/backup\040disk ext4 ro,relatime 0 0
+tmpfs /usr/local tmpfs rw,nosuid,nodev 0 0
+`
+
+func TestGetReadOnlyFilesystemError(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("mount tables only exist on Linux")
+	}
+
+	tests := []struct {
+		mounts  string
+		path    string
+		wantErr string
+	}{
+		{mounts: testMounts, path: "/etc/ssh/sshd_config"},
+		{mounts: testMounts, path: "/usr/share/zoneinfo", wantErr: "/usr/share/zoneinfo is on the read-only filesystem /usr and cannot be changed"},
+		{mounts: testMounts, path: "/usr/local/etc/hosts"},
+		{mounts: testMounts, path: "/usrlocal/hosts"},
+		{mounts: testMounts, path: "/mnt/backup disk/daemon.json", wantErr: "/mnt/backup disk/daemon.json is on the read-only filesystem /mnt/backup disk and cannot be changed"},
+		{mounts: "overlay / overlay ro,relatime 0 0\n", path: "/etc/hosts", wantErr: "root filesystem is read-only, /etc/hosts cannot be changed"},
+		{mounts: "", path: "/etc/hosts"},
+	}
+
+	for _, tt := range tests {
+		err := getReadOnlyFilesystemError(tt.mounts, tt.path)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("getReadOnlyFilesystemError(%q) error = %v, want nil", tt.path, err)
+			}
+			continue
+		}
+		if err == nil || err.Error() != tt.wantErr {
+			t.Errorf("getReadOnlyFilesystemError(%q) error = %v, want %q", tt.path, err, tt.wantErr)
+		}
+	}
+}
+
+func TestCheckWritableFilesystemIn(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("mount tables only exist on Linux")
+	}
+
+	mountsFile := filepath.Join(t.TempDir(), "mounts")
+	if err := os.WriteFile(mountsFile, []byte(testMounts), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := checkWritableFilesystemIn(mountsFile, "/usr/share/zoneinfo"); err == nil {
+		t.Error("checkWritableFilesystemIn() error = nil, want an error for a read-only filesystem")
+	}
+	if err := checkWritableFilesystemIn(mountsFile, "/etc/hosts"); err != nil {
+		t.Errorf("checkWritableFilesystemIn() error = %v, want nil for a writable filesystem", err)
+	}
+
+	// without a mount table, like on macOS, nothing is checked
+	if err := checkWritableFilesystemIn(filepath.Join(t.TempDir(), "missing"), "/usr/share/zoneinfo"); err != nil {
+		t.Errorf("checkWritableFilesystemIn() error = %v, want nil without a mount table", err)
+	}
+}
+
+func TestInitDoctorFlagsRegistryName(t *testing.T) {
+	opts := &DoctorOptions{}
+
+	flags := pflag.NewFlagSet("doctor", pflag.ContinueOnError)
+	initDoctorFlags(flags, opts)
+	if opts.RegistryName != registryContainerName {
+		t.Errorf("default RegistryName = %q, want %q", opts.RegistryName, registryContainerName)
+	}
+
+	if err := flags.Parse([]string{"--registry-name", "my-registry"}); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if opts.RegistryName != "my-registry" {
+		t.Errorf("RegistryName = %q, want %q", opts.RegistryName, "my-registry")
+	}
+}
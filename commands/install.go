@@ -0,0 +1,163 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package commands
+
+import (
+	"fmt"
+
+	"github.com/mkloubert/autark/app"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// InstallOptions contains options for the install command
+type InstallOptions struct {
+	Compose ComposeOptions
+	Doctor  DoctorOptions
+	Setup   SetupOptions
+}
+
+// installPhase is a single step of the install command
+type installPhase struct {
+	Name string
+	Run  func() error
+}
+
+func initInstallCommand(a *app.AppContext) {
+	rootCmd := a.RootCommand()
+
+	opts := &InstallOptions{}
+
+	installCmd := &cobra.Command{
+		Use:     "install",
+		Aliases: []string{"i"},
+		Short:   "Check dependencies, setup registry and deploy stack",
+		Long:    `Runs 'doctor --repair', 'setup' and 'compose up' one after another and stops on the first failure.`,
+		Example: `  sudo autark install
+  sudo autark install --registry-port 5001 --no-ssh --file ./compose.yaml`,
+		Run: func(cmd *cobra.Command, args []string) {
+			exitOnError(a, runWithLock(a, func() error {
+				return runInstall(a, opts)
+			}))
+		},
+	}
+
+	flags := installCmd.Flags()
+	initComposeFlags(flags, &opts.Compose)
+	initSetupFlags(flags, &opts.Setup)
+	initInstallDoctorFlags(flags, &opts.Doctor)
+
+	rootCmd.AddCommand(installCmd)
+}
+
+// initInstallDoctorFlags registers the flags of the doctor command,
+// which are not already defined by compose and setup, like --file,
+// so that install supports every new flag of the doctor, too
+func initInstallDoctorFlags(flags *pflag.FlagSet, opts *DoctorOptions) {
+	doctorFlags := pflag.NewFlagSet("doctor", pflag.ContinueOnError)
+	initDoctorFlags(doctorFlags, opts)
+
+	doctorFlags.VisitAll(func(flag *pflag.Flag) {
+		// install always repairs
+		if flag.Name == "repair" || flags.Lookup(flag.Name) != nil {
+			return
+		}
+
+		flags.AddFlag(flag)
+	})
+}
+
+func runInstall(a *app.AppContext, opts *InstallOptions) error {
+	// only repairs if something is missing
+	opts.Doctor.Repair = true
+	// --file is the one of compose and --registry-name the one of setup
+	opts.Doctor.ComposeFile = opts.Compose.File
+	opts.Doctor.RegistryName = opts.Setup.RegistryName
+
+	phases := []installPhase{
+		{
+			Name: "doctor",
+			Run: func() error {
+				return runDoctor(a, &opts.Doctor)
+			},
+		},
+		{
+			Name: "setup",
+			Run: func() error {
+				return runSetup(a, &opts.Setup)
+			},
+		},
+		{
+			Name: "compose up",
+			Run: func() error {
+				return runComposeUp(a, &opts.Compose)
+			},
+		},
+	}
+
+	return runInstallPhases(a, phases)
+}
+
+// runInstallPhases runs phases in order, stops on the first failure
+// and prints a summary of all phases at the end
+func runInstallPhases(a *app.AppContext, phases []installPhase) error {
+	var failedPhase *installPhase
+	var failedErr error
+	completed := 0
+
+	for i := range phases {
+		phase := &phases[i]
+
+		a.WriteF("==> [%d/%d] %s", i+1, len(phases), phase.Name)
+		a.WriteLn("")
+		a.WriteLn("")
+
+		if err := phase.Run(); err != nil {
+			failedPhase = phase
+			failedErr = err
+			break
+		}
+
+		completed++
+		a.WriteLn("")
+	}
+
+	a.WriteLn("")
+	a.WriteLn("Summary:")
+	for i, phase := range phases {
+		switch {
+		case i < completed:
+			a.WriteF("%s %s", a.Status(app.StatusOK), phase.Name)
+		case i == completed && failedPhase != nil:
+			a.WriteF("%s %s: %s", a.Status(app.StatusError), phase.Name, failedErr.Error())
+		default:
+			a.WriteF("%s %s", a.Status(app.StatusSkipped), phase.Name)
+		}
+		a.WriteLn("")
+	}
+
+	if failedPhase != nil {
+		return fmt.Errorf("install stopped, because %s failed", failedPhase.Name)
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package commands
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func TestRunInstallPhases(t *testing.T) {
+	a := newTestAppContext(t)
+
+	var called []string
+	newPhase := func(name string, err error) installPhase {
+		return installPhase{
+			Name: name,
+			Run: func() error {
+				called = append(called, name)
+				return err
+			},
+		}
+	}
+
+	err := runInstallPhases(a, []installPhase{
+		newPhase("doctor", nil),
+		newPhase("setup", nil),
+		newPhase("compose up", nil),
+	})
+	if err != nil {
+		t.Fatalf("runInstallPhases() error = %v", err)
+	}
+	if want := []string{"doctor", "setup", "compose up"}; !slices.Equal(called, want) {
+		t.Errorf("called phases = %v, want %v", called, want)
+	}
+
+	called = nil
+	err = runInstallPhases(a, []installPhase{
+		newPhase("doctor", nil),
+		newPhase("setup", errors.New("port in use")),
+		newPhase("compose up", nil),
+	})
+	if err == nil || !strings.Contains(err.Error(), "setup") {
+		t.Fatalf("runInstallPhases() error = %v, want an error of the setup phase", err)
+	}
+	if want := []string{"doctor", "setup"}; !slices.Equal(called, want) {
+		t.Errorf("called phases = %v, want %v", called, want)
+	}
+}
+
+func TestInitInstallDoctorFlags(t *testing.T) {
+	opts := &InstallOptions{}
+
+	flags := pflag.NewFlagSet("install", pflag.ContinueOnError)
+	initComposeFlags(flags, &opts.Compose)
+	initSetupFlags(flags, &opts.Setup)
+	initInstallDoctorFlags(flags, &opts.Doctor)
+
+	err := flags.Parse([]string{
+		"--file", "./compose.yaml",
+		"--docker-version", "5:27.3.1-1~ubuntu.24.04~noble",
+		"--pkg-arg=--no-install-recommends",
+		"--skip-daemon-start",
+		"--skip", "docker hub login",
+		"--registry-name", "my-registry",
+	})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if opts.Compose.File != "./compose.yaml" || opts.Doctor.ComposeFile != "" {
+		t.Errorf("--file = %q (doctor: %q), want it only for compose", opts.Compose.File, opts.Doctor.ComposeFile)
+	}
+	if opts.Doctor.DockerVersion != "5:27.3.1-1~ubuntu.24.04~noble" {
+		t.Errorf("DockerVersion = %q", opts.Doctor.DockerVersion)
+	}
+	if want := []string{"--no-install-recommends"}; !slices.Equal(opts.Doctor.PkgArgs, want) {
+		t.Errorf("PkgArgs = %v, want %v", opts.Doctor.PkgArgs, want)
+	}
+	if !opts.Doctor.SkipDaemonStart {
+		t.Error("SkipDaemonStart = false, want true")
+	}
+	if want := []string{"docker hub login"}; !slices.Equal(opts.Doctor.Skip, want) {
+		t.Errorf("Skip = %v, want %v", opts.Doctor.Skip, want)
+	}
+	// runInstall passes --registry-name of setup to the doctor
+	if opts.Setup.RegistryName != "my-registry" || opts.Doctor.RegistryName == "my-registry" {
+		t.Errorf("--registry-name = %q (doctor: %q), want it only for setup", opts.Setup.RegistryName, opts.Doctor.RegistryName)
+	}
+	if flags.Lookup("repair") != nil {
+		t.Error("install has a --repair flag, but always repairs")
+	}
+}
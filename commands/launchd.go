@@ -0,0 +1,129 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package commands
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/mkloubert/autark/app"
+	"github.com/mkloubert/autark/utils"
+)
+
+// registryLaunchdLabel is the label of the launchd agent,
+// which keeps the default registry container running on macOS
+const registryLaunchdLabel = "com.autark.registry"
+
+// getRegistryLaunchdLabel returns the label of the launchd agent,
+// which keeps a registry container running, so that each container
+// of --registry-name has its own agent
+//
+// The default container keeps the label of older versions
+func getRegistryLaunchdLabel(containerName string) string {
+	if containerName == registryContainerName {
+		return registryLaunchdLabel
+	}
+
+	return registryLaunchdLabel + "." + containerName
+}
+
+// buildLaunchdPlist returns the property list of a launchd agent, which
+// keeps a container running by starting it attached with docker, so
+// launchd starts it again, whenever it stops
+func buildLaunchdPlist(label string, dockerPath string, containerName string) string {
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>%s</string>
+	<key>ProgramArguments</key>
+	<array>
+		<string>%s</string>
+		<string>start</string>
+		<string>--attach</string>
+		<string>%s</string>
+	</array>
+	<key>RunAtLoad</key>
+	<true/>
+	<key>KeepAlive</key>
+	<true/>
+	<key>ThrottleInterval</key>
+	<integer>10</integer>
+</dict>
+</plist>
+`, html.EscapeString(label), html.EscapeString(dockerPath), html.EscapeString(containerName))
+}
+
+// getLaunchAgentPath returns the path of the property list of
+// a launchd agent of the current user
+func getLaunchAgentPath(homeDir string, label string) string {
+	return filepath.Join(homeDir, "Library", "LaunchAgents", label+".plist")
+}
+
+// installRegistryLaunchdAgent writes and loads the launchd agent,
+// which keeps the registry container running on macOS
+func installRegistryLaunchdAgent(a *app.AppContext, opts *SetupOptions) error {
+	// launchd does not use the PATH of the shell
+	dockerPath, err := exec.LookPath("docker")
+	if err != nil {
+		return fmt.Errorf("could not find docker: %w", err)
+	}
+	if dockerPath, err = filepath.Abs(dockerPath); err != nil {
+		return err
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("could not determine home directory: %w", err)
+	}
+
+	label := getRegistryLaunchdLabel(opts.RegistryName)
+	plistPath := getLaunchAgentPath(homeDir, label)
+	if err := os.MkdirAll(filepath.Dir(plistPath), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(plistPath), err)
+	}
+
+	plist := buildLaunchdPlist(label, dockerPath, opts.RegistryName)
+	if _, err := utils.WriteFileIfChanged(plistPath, []byte(plist), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", plistPath, err)
+	}
+	dropFileOwnership(a, plistPath)
+
+	// reloads the agent, if it has been loaded before
+	unloadArgs := []string{"unload", plistPath}
+	err = utils.RunCommandSilent("launchctl", unloadArgs...)
+	a.RecordCommand("launchctl", unloadArgs, err)
+	if err != nil {
+		a.D("launchd agent %s was not loaded", label)
+	}
+	if err := runInstallCommandDirect(a, "launchctl", "load", "-w", plistPath); err != nil {
+		return fmt.Errorf("failed to run launchctl: %w", err)
+	}
+
+	a.WriteF("The registry is kept running by the launchd agent %s.", plistPath)
+	a.WriteLn("")
+	return nil
+}
@@ -0,0 +1,108 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package commands
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// plistStrings returns the values of the <string> elements
+// of a property list and fails, if it is not valid XML
+func plistStrings(t *testing.T, plist string) []string {
+	t.Helper()
+
+	var values []string
+	decoder := xml.NewDecoder(strings.NewReader(plist))
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return values
+		}
+		if err != nil {
+			t.Fatalf("invalid property list: %v", err)
+		}
+
+		if start, ok := token.(xml.StartElement); ok && start.Name.Local == "string" {
+			var value string
+			if err := decoder.DecodeElement(&value, &start); err != nil {
+				t.Fatalf("invalid property list: %v", err)
+			}
+			values = append(values, value)
+		}
+	}
+}
+
+func TestBuildLaunchdPlist(t *testing.T) {
+	plist := buildLaunchdPlist(registryLaunchdLabel, "/usr/local/bin/docker", "registry")
+
+	want := []string{registryLaunchdLabel, "/usr/local/bin/docker", "start", "--attach", "registry"}
+	if got := plistStrings(t, plist); !slices.Equal(got, want) {
+		t.Errorf("strings of buildLaunchdPlist() = %q, want %q", got, want)
+	}
+
+	for _, key := range []string{"<key>RunAtLoad</key>", "<key>KeepAlive</key>"} {
+		if !strings.Contains(plist, key) {
+			t.Errorf("buildLaunchdPlist() does not contain %s", key)
+		}
+	}
+}
+
+func TestBuildLaunchdPlistEscaping(t *testing.T) {
+	dockerPath := "/Users/tom & jerry/<bin>/docker"
+
+	plist := buildLaunchdPlist(registryLaunchdLabel, dockerPath, "registry")
+
+	if got := plistStrings(t, plist); !slices.Contains(got, dockerPath) {
+		t.Errorf("strings of buildLaunchdPlist() = %q, want them to contain %q", got, dockerPath)
+	}
+}
+
+func TestGetLaunchAgentPath(t *testing.T) {
+	got := getLaunchAgentPath(filepath.Join("Users", "alice"), registryLaunchdLabel)
+
+	want := filepath.Join("Users", "alice", "Library", "LaunchAgents", "com.autark.registry.plist")
+	if got != want {
+		t.Errorf("getLaunchAgentPath() = %q, want %q", got, want)
+	}
+}
+
+func TestGetRegistryLaunchdLabel(t *testing.T) {
+	tests := []struct {
+		containerName string
+		want          string
+	}{
+		{containerName: registryContainerName, want: "com.autark.registry"},
+		{containerName: "my-registry", want: "com.autark.registry.my-registry"},
+	}
+
+	for _, tt := range tests {
+		if got := getRegistryLaunchdLabel(tt.containerName); got != tt.want {
+			t.Errorf("getRegistryLaunchdLabel(%q) = %q, want %q", tt.containerName, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package commands
+
+import (
+	"regexp"
+
+	"github.com/mkloubert/autark/app"
+	"github.com/mkloubert/autark/utils"
+	"github.com/spf13/cobra"
+)
+
+// PruneOptions contains options for the prune command
+type PruneOptions struct {
+	// All indicates if all unused images should be removed,
+	// not only dangling ones
+	All bool
+	// Volumes indicates if unused volumes should be removed
+	Volumes bool
+}
+
+// reclaimedSpaceRegex matches the summary line of
+// "docker system prune", like "Total reclaimed space: 1.2GB"
+var reclaimedSpaceRegex = regexp.MustCompile(`(?m)^Total reclaimed space:\s*(\S+)\s*$`)
+
+// buildPruneArgs builds the arguments for a "docker system prune" call
+func buildPruneArgs(opts *PruneOptions) []string {
+	args := []string{"system", "prune", "--force"}
+
+	if opts.All {
+		args = append(args, "--all")
+	}
+	if opts.Volumes {
+		args = append(args, "--volumes")
+	}
+
+	return args
+}
+
+func initPruneCommand(a *app.AppContext) {
+	rootCmd := a.RootCommand()
+
+	opts := &PruneOptions{}
+
+	pruneCmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Remove unused Docker data",
+		Long:  `Removes stopped containers, unused networks, dangling images and the build cache with 'docker system prune' to free disk space.`,
+		Example: `  autark prune
+  autark prune --all --volumes --yes`,
+		Run: func(cmd *cobra.Command, args []string) {
+			exitOnError(a, runPrune(a, opts))
+		},
+	}
+
+	pruneCmd.Flags().BoolVarP(&opts.All, "all", "a", false, "Remove all unused images, not only dangling ones")
+	pruneCmd.Flags().BoolVarP(&opts.Volumes, "volumes", "", false, "Remove unused volumes as well")
+
+	rootCmd.AddCommand(pruneCmd)
+}
+
+// parseReclaimedSpace returns the reclaimed space from the output
+// of "docker system prune", like "1.2GB", or an empty string
+func parseReclaimedSpace(output string) string {
+	match := reclaimedSpaceRegex.FindStringSubmatch(output)
+	if match == nil {
+		return ""
+	}
+
+	return match[1]
+}
+
+func runPrune(a *app.AppContext, opts *PruneOptions) error {
+	if err := checkDockerAvailable(); err != nil {
+		return err
+	}
+
+	a.WriteLn("This will remove all stopped containers, unused networks, dangling images and the build cache.")
+	if opts.All {
+		a.WriteLn("All images without at least one container will be removed as well.")
+	}
+	if opts.Volumes {
+		a.WriteLn("All volumes, which are not used by at least one container, will be removed as well.")
+	}
+	a.WriteLn("")
+
+	if !a.PromptYesNo("Are you sure you want to continue?", false) {
+		a.WriteLn("Aborted.")
+		return nil
+	}
+
+	args := buildPruneArgs(opts)
+	a.D("Running: %s", utils.RedactCommandLine("docker", args...))
+
+	output, err := utils.RunCommandCombinedToWriter(a.Stdout(), "docker", args...)
+	if err != nil {
+		return newDockerError("system prune", err, output)
+	}
+
+	a.WriteLn("")
+	if reclaimed := parseReclaimedSpace(string(output)); reclaimed != "" {
+		a.WriteF("Reclaimed %s of disk space.", reclaimed)
+		a.WriteLn("")
+	} else {
+		a.WriteLn("Unused Docker data has been removed.")
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package commands
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBuildPruneArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		opts *PruneOptions
+		want []string
+	}{
+		{name: "defaults", opts: &PruneOptions{}, want: []string{"system", "prune", "--force"}},
+		{name: "all", opts: &PruneOptions{All: true}, want: []string{"system", "prune", "--force", "--all"}},
+		{name: "all and volumes", opts: &PruneOptions{All: true, Volumes: true}, want: []string{"system", "prune", "--force", "--all", "--volumes"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := buildPruneArgs(tt.opts); !slices.Equal(got, tt.want) {
+				t.Errorf("buildPruneArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseReclaimedSpace(t *testing.T) {
+	tests := []struct {
+		output string
+		want   string
+	}{
+		{output: "Deleted Containers:\n4a1b2c3d\n\nTotal reclaimed space: 1.2GB\n", want: "1.2GB"},
+		{output: "Total reclaimed space: 0B", want: "0B"},
+		{output: "Error response from daemon: a prune operation is already running\n", want: ""},
+		{output: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := parseReclaimedSpace(tt.output); got != tt.want {
+			t.Errorf("parseReclaimedSpace(%q) = %q, want %q", tt.output, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,844 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/mkloubert/autark/app"
+	"github.com/mkloubert/autark/utils"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	// registryHealthInterval is the interval, in which the health
+	// of the registry container is checked
+	registryHealthInterval = 1 * time.Second
+	// registryHealthTimeout is the maximum time, the registry container
+	// may need to become healthy
+	registryHealthTimeout = 30 * time.Second
+)
+
+const (
+	// defaultRegistryURL is the URL of the registry, which is set up
+	// by "autark setup" with the default port
+	defaultRegistryURL = "http://localhost:5000"
+	// registryCheckTimeout is the maximum time of a request of the
+	// reachability check of the registry
+	registryCheckTimeout = 10 * time.Second
+	// defaultPushConcurrency is the default number of images,
+	// which are pushed at the same time with --parallel-pull
+	defaultPushConcurrency = 4
+	// defaultWatchInterval is the default interval of
+	// the checks of "registry watch"
+	defaultWatchInterval = 5 * time.Second
+	// registryCatalogPageSize is the number of entries, which are
+	// requested per page of the catalog and the tag lists
+	registryCatalogPageSize = 100
+)
+
+// statuses of the reachability check of the registry
+const (
+	registryStatusAuthFailed    = "auth-failed"
+	registryStatusAuthRequired  = "auth-required"
+	registryStatusMisconfigured = "misconfigured"
+	registryStatusOK            = "ok"
+	registryStatusUnreachable   = "unreachable"
+)
+
+// RegistryOptions contains options for the registry commands
+type RegistryOptions struct {
+	Name string
+	// HealthTimeout is the maximum time, the registry may need
+	// to become healthy or to answer a request
+	HealthTimeout time.Duration
+}
+
+// RegistryCatalogOptions contains options for the registry catalog command
+type RegistryCatalogOptions struct {
+	// JSON indicates if the result should be written as JSON
+	JSON bool
+	// Password is the password for basic auth
+	Password string
+	// Tags indicates if the tags of each repository should be listed
+	Tags bool
+	// URL is the base URL of the registry
+	URL string
+	// User is the user for basic auth
+	User string
+}
+
+// RegistryCheckOptions contains options for the registry check command
+type RegistryCheckOptions struct {
+	// JSON indicates if the result should be written as JSON
+	JSON bool
+	// Password is the password for basic auth
+	Password string
+	// URL is the base URL of the registry
+	URL string
+	// User is the user for basic auth
+	User string
+}
+
+// RegistryPushOptions contains options for the registry push command
+type RegistryPushOptions struct {
+	// Concurrency is the maximum number of images, which
+	// are pushed at the same time with ParallelPull
+	Concurrency int
+	// ParallelPull indicates if the images should be
+	// pulled, tagged and pushed concurrently
+	ParallelPull bool
+	// RegistryPort is the port of the local registry
+	RegistryPort int
+}
+
+// RegistryWatchOptions contains options for the registry watch command
+type RegistryWatchOptions struct {
+	// Interval is the time between two checks
+	Interval time.Duration
+	// Password is the password for basic auth
+	Password string
+	// URL is the base URL of the registry
+	URL string
+	// User is the user for basic auth
+	User string
+}
+
+// registryWatcher detects the transitions of the status
+// of the registry between the checks of "registry watch"
+type registryWatcher struct {
+	// status is the last status, which is empty before the first check
+	status string
+	// since is the time of the last transition
+	since time.Time
+}
+
+// registryRepository contains a repository of the
+// catalog of the registry
+type registryRepository struct {
+	// Name is the name of the repository, like "library/nginx"
+	Name string `json:"name"`
+	// Tags contains the tags, if they have been requested
+	Tags []string `json:"tags,omitempty"`
+}
+
+// registryCheckResult contains the result of the reachability
+// check of the registry
+type registryCheckResult struct {
+	// URL is the checked URL of the API
+	URL string `json:"url"`
+	// Status is one of the registryStatus* constants
+	Status string `json:"status"`
+	// StatusCode is the HTTP status code or 0 if not reachable
+	StatusCode int `json:"status_code"`
+	// Message describes the result
+	Message string `json:"message"`
+}
+
+// containerRuntime contains the container operations,
+// which are used by the registry commands
+type containerRuntime interface {
+	// ContainerExists checks if a container exists
+	ContainerExists(name string) (bool, error)
+	// ImageExists checks if an image exists locally
+	ImageExists(image string) (bool, error)
+	// IsContainerHealthy checks if a container is running
+	// and healthy, if it has a health check
+	IsContainerHealthy(name string) (bool, error)
+	// PullImage pulls an image
+	PullImage(image string) error
+	// PushImage pushes an image
+	PushImage(image string) error
+	// RestartContainer restarts a container
+	RestartContainer(name string) error
+	// SetRestartPolicy changes the restart policy of a container, like "no"
+	SetRestartPolicy(name string, policy string) error
+	// TagImage creates the tag target for the image source
+	TagImage(source string, target string) error
+}
+
+// dockerRuntime is the containerRuntime, which uses the docker CLI
+type dockerRuntime struct{}
+
+func (r *dockerRuntime) ContainerExists(name string) (bool, error) {
+	output, err := utils.RunCommand("docker", "inspect", "--type", "container", "--format", "{{.Name}}", name)
+	if err != nil {
+		if strings.Contains(string(output), "No such") {
+			return false, nil
+		}
+
+		return false, newDockerError("inspect", err, output)
+	}
+
+	return true, nil
+}
+
+func (r *dockerRuntime) ImageExists(image string) (bool, error) {
+	output, err := utils.RunCommand("docker", "image", "inspect", "--format", "{{.Id}}", image)
+	if err != nil {
+		if strings.Contains(string(output), "No such") {
+			return false, nil
+		}
+
+		return false, newDockerError("image inspect", err, output)
+	}
+
+	return true, nil
+}
+
+func (r *dockerRuntime) IsContainerHealthy(name string) (bool, error) {
+	output, err := utils.RunCommand("docker", "inspect", "--type", "container", "--format", "{{.State.Running}} {{if .State.Health}}{{.State.Health.Status}}{{end}}", name)
+	if err != nil {
+		return false, newDockerError("inspect", err, output)
+	}
+
+	running, health, _ := strings.Cut(strings.TrimSpace(string(output)), " ")
+	return running == "true" && (health == "" || health == "healthy"), nil
+}
+
+func (r *dockerRuntime) PullImage(image string) error {
+	output, err := utils.RunCommand("docker", "pull", "--quiet", image)
+	if err != nil {
+		return newDockerError("pull", err, output)
+	}
+
+	return nil
+}
+
+func (r *dockerRuntime) PushImage(image string) error {
+	output, err := utils.RunCommand("docker", "push", "--quiet", image)
+	if err != nil {
+		return newDockerError("push", err, output)
+	}
+
+	return nil
+}
+
+func (r *dockerRuntime) RestartContainer(name string) error {
+	output, err := utils.RunCommand("docker", "restart", name)
+	if err != nil {
+		return newDockerError("restart", err, output)
+	}
+
+	return nil
+}
+
+func (r *dockerRuntime) SetRestartPolicy(name string, policy string) error {
+	output, err := utils.RunCommand("docker", "update", "--restart="+policy, name)
+	if err != nil {
+		return newDockerError("update", err, output)
+	}
+
+	return nil
+}
+
+func (r *dockerRuntime) TagImage(source string, target string) error {
+	output, err := utils.RunCommand("docker", "tag", source, target)
+	if err != nil {
+		return newDockerError("tag", err, output)
+	}
+
+	return nil
+}
+
+// update sets the status of a new check and returns the previous
+// status with its duration, if it has changed, which is also the
+// case for the first check
+func (w *registryWatcher) update(status string, now time.Time) (string, time.Duration, bool) {
+	if status == w.status {
+		return "", 0, false
+	}
+
+	previous := w.status
+	duration := now.Sub(w.since)
+
+	w.status = status
+	w.since = now
+
+	if previous == "" {
+		return "", 0, true
+	}
+	return previous, duration, true
+}
+
+// checkRegistryReachability sends a GET request to the /v2/ endpoint
+// of a registry, which authenticates with basic auth, if user is set,
+// and tells "up, but needs auth" and misconfigurations apart
+func checkRegistryReachability(client *http.Client, baseURL string, user string, password string) *registryCheckResult {
+	apiURL := strings.TrimRight(baseURL, "/") + "/v2/"
+
+	result := &registryCheckResult{
+		URL: utils.RedactValue(apiURL),
+	}
+
+	req, err := http.NewRequest(http.MethodGet, apiURL, nil)
+	if err != nil {
+		result.Status = registryStatusMisconfigured
+		result.Message = fmt.Sprintf("invalid URL: %s", err.Error())
+		return result
+	}
+	if user != "" {
+		req.SetBasicAuth(user, password)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		result.Status = registryStatusUnreachable
+		result.Message = err.Error()
+		return result
+	}
+	defer resp.Body.Close()
+
+	result.StatusCode = resp.StatusCode
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		result.Status = registryStatusOK
+		result.Message = "registry is up"
+	case resp.StatusCode == http.StatusUnauthorized && user == "":
+		result.Status = registryStatusAuthRequired
+		result.Message = "registry is up, but requires authentication (use --registry-user and --registry-password)"
+	case resp.StatusCode == http.StatusUnauthorized:
+		result.Status = registryStatusAuthFailed
+		result.Message = fmt.Sprintf("registry is up, but rejected the credentials of %s", user)
+	default:
+		result.Status = registryStatusMisconfigured
+		result.Message = fmt.Sprintf("unexpected response %s, is this a Docker registry?", resp.Status)
+	}
+
+	return result
+}
+
+// fetchRegistryPage sends a GET request to a paginated endpoint of
+// the API of a registry, decodes the JSON response into v and returns
+// the URL of the next page from the "Link" header, which is empty on
+// the last page
+func fetchRegistryPage(client *http.Client, pageURL string, user string, password string, v any) (string, error) {
+	req, err := http.NewRequest(http.MethodGet, pageURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL: %w", err)
+	}
+	if user != "" {
+		req.SetBasicAuth(user, password)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized && user == "":
+		return "", fmt.Errorf("registry requires authentication (use --registry-user and --registry-password)")
+	case resp.StatusCode == http.StatusUnauthorized:
+		return "", fmt.Errorf("registry rejected the credentials of %s", user)
+	case resp.StatusCode != http.StatusOK:
+		return "", fmt.Errorf("unexpected response %s from %s", resp.Status, utils.RedactValue(pageURL))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return "", fmt.Errorf("invalid response from %s: %w", utils.RedactValue(pageURL), err)
+	}
+
+	next := parseNextLink(resp.Header.Get("Link"))
+	if next == "" {
+		return "", nil
+	}
+
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return "", err
+	}
+	nextURL, err := base.Parse(next)
+	if err != nil {
+		return "", fmt.Errorf("invalid Link header %q: %w", resp.Header.Get("Link"), err)
+	}
+
+	return nextURL.String(), nil
+}
+
+// getRegistryImageName returns the name of an image in the registry
+// of address, where the registry of the image, like "ghcr.io", is
+// replaced, like "localhost:5000/nginx:1.27" for "nginx:1.27"
+func getRegistryImageName(image string, address string) string {
+	if first, rest, ok := strings.Cut(image, "/"); ok {
+		// the first component is a registry, if it looks like a host
+		if strings.ContainsAny(first, ".:") || first == "localhost" {
+			image = rest
+		}
+	}
+
+	return address + "/" + image
+}
+
+func initRegistryCommand(a *app.AppContext) {
+	rootCmd := a.RootCommand()
+
+	opts := &RegistryOptions{}
+
+	registryCmd := &cobra.Command{
+		Use:     "registry",
+		Aliases: []string{"reg"},
+		Short:   "Manage the local Docker registry",
+		Long:    `Manages the local Docker registry, which has been set up by 'autark setup'.`,
+		Example: `  autark registry check
+  autark registry push nginx:1.27 postgres:17
+  autark registry restart`,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+
+	initRegistryFlags(registryCmd.PersistentFlags(), opts)
+
+	restartCmd := &cobra.Command{
+		Use:   "restart",
+		Short: "Restart the registry",
+		Long:  `Restarts the registry container, for example to apply configuration changes, and waits until it is healthy.`,
+		Example: `  autark registry restart
+  autark registry restart --registry-name my-registry --registry-health-timeout 1m`,
+		Run: func(cmd *cobra.Command, args []string) {
+			exitOnError(a, checkDockerAvailable())
+			exitOnError(a, runRegistryRestart(a, opts, &dockerRuntime{}))
+		},
+	}
+
+	checkOpts := &RegistryCheckOptions{}
+
+	checkCmd := &cobra.Command{
+		Use:   "check",
+		Short: "Check if the registry is reachable",
+		Long:  `Checks if the API of the registry is reachable and, with --registry-user and --registry-password, if the credentials are accepted.`,
+		Example: `  autark registry check
+  autark registry check --registry-url https://registry.example.com --registry-user admin --registry-password secret --json`,
+		Run: func(cmd *cobra.Command, args []string) {
+			exitOnError(a, runRegistryCheck(a, checkOpts, &http.Client{Timeout: registryCheckTimeout}))
+		},
+	}
+
+	checkFlags := checkCmd.Flags()
+	checkFlags.StringVarP(&checkOpts.URL, "registry-url", "", defaultRegistryURL, "Base URL of the registry")
+	checkFlags.StringVarP(&checkOpts.User, "registry-user", "", "", "User for basic auth")
+	checkFlags.StringVarP(&checkOpts.Password, "registry-password", "", "", "Password for basic auth")
+	checkFlags.BoolVarP(&checkOpts.JSON, "json", "", false, "Output as JSON")
+
+	installServiceCmd := &cobra.Command{
+		Use:   "install-service",
+		Short: "Keep the registry running with a systemd unit",
+		Long:  `Writes and enables a systemd unit, which keeps the registry container running instead of the restart policy of docker, so it is started in order with other units and logs to the journal.`,
+		Example: `  sudo autark registry install-service
+  sudo autark registry install-service --registry-name my-registry`,
+		Run: func(cmd *cobra.Command, args []string) {
+			exitOnError(a, checkDockerAvailable())
+			exitOnError(a, runWithLock(a, func() error {
+				return installRegistrySystemdService(a, opts, &dockerRuntime{})
+			}))
+		},
+	}
+
+	catalogOpts := &RegistryCatalogOptions{}
+
+	catalogCmd := &cobra.Command{
+		Use:   "catalog",
+		Short: "List the repositories of the registry",
+		Long:  `Lists the repositories, which are stored in the registry, and, with --tags, their tags.`,
+		Example: `  autark registry catalog
+  autark registry catalog --tags --json
+  autark registry catalog --registry-url https://registry.example.com --registry-user admin --registry-password secret`,
+		Run: func(cmd *cobra.Command, args []string) {
+			exitOnError(a, runRegistryCatalog(a, catalogOpts, &http.Client{Timeout: registryCheckTimeout}))
+		},
+	}
+
+	catalogFlags := catalogCmd.Flags()
+	catalogFlags.StringVarP(&catalogOpts.URL, "registry-url", "", defaultRegistryURL, "Base URL of the registry")
+	catalogFlags.StringVarP(&catalogOpts.User, "registry-user", "", "", "User for basic auth")
+	catalogFlags.StringVarP(&catalogOpts.Password, "registry-password", "", "", "Password for basic auth")
+	catalogFlags.BoolVarP(&catalogOpts.Tags, "tags", "", false, "List the tags of each repository")
+	catalogFlags.BoolVarP(&catalogOpts.JSON, "json", "", false, "Output as JSON")
+
+	pushOpts := &RegistryPushOptions{}
+
+	pushCmd := &cobra.Command{
+		Use:   "push <image> [<image>...]",
+		Short: "Push images to the registry",
+		Long:  `Pulls images, like "nginx:1.27", tags them for the local registry and pushes them, for example to warm the registry for offline deployments.`,
+		Example: `  autark registry push nginx:1.27
+  autark registry push --parallel-pull --concurrency 2 nginx:1.27 postgres:17 ghcr.io/example/app:1.0`,
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			exitOnError(a, checkDockerAvailable())
+			exitOnError(a, runRegistryPush(a, pushOpts, &dockerRuntime{}, args))
+		},
+	}
+
+	pushFlags := pushCmd.Flags()
+	pushFlags.IntVarP(&pushOpts.RegistryPort, "registry-port", "", 5000, "Port of the local Docker registry")
+	pushFlags.BoolVarP(&pushOpts.ParallelPull, "parallel-pull", "", false, "Pull, tag and push the images concurrently")
+	pushFlags.IntVarP(&pushOpts.Concurrency, "concurrency", "", defaultPushConcurrency, "Maximum number of images, which are pushed at the same time with --parallel-pull")
+
+	watchOpts := &RegistryWatchOptions{}
+
+	watchCmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Watch the health of the registry",
+		Long:  `Checks the API of the registry in an interval and logs every change of its status, like from ok to unreachable and back, until Ctrl-C is pressed.`,
+		Example: `  autark registry watch
+  autark registry watch --interval 10s`,
+		Run: func(cmd *cobra.Command, args []string) {
+			exitOnError(a, runRegistryWatch(a, watchOpts, &http.Client{Timeout: opts.HealthTimeout}))
+		},
+	}
+
+	watchFlags := watchCmd.Flags()
+	watchFlags.StringVarP(&watchOpts.URL, "registry-url", "", defaultRegistryURL, "Base URL of the registry")
+	watchFlags.StringVarP(&watchOpts.User, "registry-user", "", "", "User for basic auth")
+	watchFlags.StringVarP(&watchOpts.Password, "registry-password", "", "", "Password for basic auth")
+	watchFlags.DurationVarP(&watchOpts.Interval, "interval", "", defaultWatchInterval, "Time between two checks")
+
+	registryCmd.AddCommand(catalogCmd)
+	registryCmd.AddCommand(checkCmd)
+	registryCmd.AddCommand(installServiceCmd)
+	registryCmd.AddCommand(pushCmd)
+	registryCmd.AddCommand(restartCmd)
+	registryCmd.AddCommand(watchCmd)
+
+	rootCmd.AddCommand(registryCmd)
+}
+
+// initRegistryFlags registers the flags for RegistryOptions
+func initRegistryFlags(flags *pflag.FlagSet, opts *RegistryOptions) {
+	flags.StringVarP(&opts.Name, "registry-name", "", registryContainerName, "Name of the registry container")
+	flags.DurationVarP(&opts.HealthTimeout, "registry-health-timeout", "", registryHealthTimeout, "Maximum time, the registry may need to become healthy after a restart or to answer a check of watch")
+}
+
+// listRegistryRepositories returns the names of all repositories
+// of the catalog of a registry, which are requested page by page
+func listRegistryRepositories(client *http.Client, baseURL string, user string, password string) ([]string, error) {
+	repositories := make([]string, 0)
+
+	pageURL := fmt.Sprintf("%s/v2/_catalog?n=%d", strings.TrimRight(baseURL, "/"), registryCatalogPageSize)
+	for pageURL != "" {
+		var page struct {
+			Repositories []string `json:"repositories"`
+		}
+
+		next, err := fetchRegistryPage(client, pageURL, user, password, &page)
+		if err != nil {
+			return nil, err
+		}
+		if next == pageURL {
+			return nil, fmt.Errorf("registry returned the same page of the catalog again")
+		}
+
+		repositories = append(repositories, page.Repositories...)
+		pageURL = next
+	}
+
+	return repositories, nil
+}
+
+// listRegistryTags returns all tags of a repository of
+// a registry, which are requested page by page
+func listRegistryTags(client *http.Client, baseURL string, repository string, user string, password string) ([]string, error) {
+	tags := make([]string, 0)
+
+	pageURL := fmt.Sprintf("%s/v2/%s/tags/list?n=%d", strings.TrimRight(baseURL, "/"), repository, registryCatalogPageSize)
+	for pageURL != "" {
+		var page struct {
+			Tags []string `json:"tags"`
+		}
+
+		next, err := fetchRegistryPage(client, pageURL, user, password, &page)
+		if err != nil {
+			return nil, err
+		}
+		if next == pageURL {
+			return nil, fmt.Errorf("registry returned the same page of the tags of %s again", repository)
+		}
+
+		tags = append(tags, page.Tags...)
+		pageURL = next
+	}
+
+	return tags, nil
+}
+
+// newDockerError creates an error for a failed docker command,
+// which contains its output, if available
+func newDockerError(command string, err error, output []byte) error {
+	if outputStr := strings.TrimSpace(string(output)); outputStr != "" {
+		return fmt.Errorf("docker %s failed: %s", command, outputStr)
+	}
+
+	return fmt.Errorf("docker %s failed: %w", command, err)
+}
+
+// parseNextLink returns the URL of the "next" relation of a
+// "Link" header, like `</v2/_catalog?last=b&n=100>; rel="next"`
+func parseNextLink(header string) string {
+	for _, link := range strings.Split(header, ",") {
+		target, params, ok := strings.Cut(link, ";")
+		if !ok {
+			continue
+		}
+
+		for _, param := range strings.Split(params, ";") {
+			key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.EqualFold(key, "rel") && strings.Trim(value, `"`) == "next" {
+				return strings.Trim(strings.TrimSpace(target), "<>")
+			}
+		}
+	}
+
+	return ""
+}
+
+func runRegistryCatalog(a *app.AppContext, opts *RegistryCatalogOptions, client *http.Client) error {
+	if opts.Password != "" && opts.User == "" {
+		return fmt.Errorf("--registry-password requires --registry-user")
+	}
+
+	names, err := listRegistryRepositories(client, opts.URL, opts.User, opts.Password)
+	if err != nil {
+		return fmt.Errorf("failed to list repositories: %w", err)
+	}
+
+	repositories := make([]*registryRepository, 0, len(names))
+	for _, name := range names {
+		repository := &registryRepository{
+			Name: name,
+		}
+
+		if opts.Tags {
+			if repository.Tags, err = listRegistryTags(client, opts.URL, name, opts.User, opts.Password); err != nil {
+				return fmt.Errorf("failed to list tags of %s: %w", name, err)
+			}
+		}
+
+		repositories = append(repositories, repository)
+	}
+
+	if opts.JSON {
+		data, err := json.MarshalIndent(repositories, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		a.WriteLn(string(data))
+		return nil
+	}
+
+	if len(repositories) == 0 {
+		a.WriteLn("The registry contains no repositories.")
+		return nil
+	}
+
+	table := app.NewTable("REPOSITORY")
+	if opts.Tags {
+		table = app.NewTable("REPOSITORY", "TAGS")
+	}
+	for _, repository := range repositories {
+		if opts.Tags {
+			table.AddRow(repository.Name, strings.Join(repository.Tags, ", "))
+		} else {
+			table.AddRow(repository.Name)
+		}
+	}
+	a.WriteTable(table)
+
+	return nil
+}
+
+func runRegistryCheck(a *app.AppContext, opts *RegistryCheckOptions, client *http.Client) error {
+	if opts.Password != "" && opts.User == "" {
+		return fmt.Errorf("--registry-password requires --registry-user")
+	}
+
+	result := checkRegistryReachability(client, opts.URL, opts.User, opts.Password)
+
+	if opts.JSON {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		a.WriteLn(string(data))
+	} else {
+		status := a.Status(app.StatusOK)
+		if result.Status != registryStatusOK {
+			status = a.Status(app.StatusError)
+		}
+
+		a.WriteF("%s %s: %s", status, result.URL, result.Message)
+		a.WriteLn("")
+	}
+
+	if result.Status != registryStatusOK {
+		return fmt.Errorf("registry check failed: %s", result.Status)
+	}
+	return nil
+}
+
+// pushImageToRegistry pulls an image, tags it as target and pushes it
+func pushImageToRegistry(runtime containerRuntime, image string, target string) error {
+	if err := runtime.PullImage(image); err != nil {
+		return err
+	}
+	if err := runtime.TagImage(image, target); err != nil {
+		return err
+	}
+
+	return runtime.PushImage(target)
+}
+
+func runRegistryPush(a *app.AppContext, opts *RegistryPushOptions, runtime containerRuntime, images []string) error {
+	if opts.Concurrency < 1 {
+		return fmt.Errorf("invalid --concurrency value %d: expected a positive number", opts.Concurrency)
+	}
+
+	concurrency := 1
+	if opts.ParallelPull {
+		concurrency = opts.Concurrency
+	}
+
+	address := fmt.Sprintf("localhost:%d", opts.RegistryPort)
+
+	targets := make([]string, 0, len(images))
+	for _, image := range images {
+		targets = append(targets, getRegistryImageName(image, address))
+	}
+
+	a.WriteF("Pushing %d image(s) to %s ...", len(images), address)
+	a.WriteLn("")
+
+	errs := utils.RunParallel(len(images), concurrency, func(i int) error {
+		a.D("Pushing %s as %s", images[i], targets[i])
+		return pushImageToRegistry(runtime, images[i], targets[i])
+	})
+
+	a.WriteLn("")
+
+	table := app.NewTable("IMAGE", "TARGET", "STATUS", "ERROR")
+	table.Colorize = a.StatusColors(2)
+
+	failed := 0
+	for i, err := range errs {
+		if err != nil {
+			failed++
+			table.AddRow(images[i], targets[i], a.Status(app.StatusError), err.Error())
+		} else {
+			table.AddRow(images[i], targets[i], a.Status(app.StatusOK), "")
+		}
+	}
+
+	a.WriteTable(table)
+
+	if failed > 0 {
+		return fmt.Errorf("Failed to push %d of %d image(s)", failed, len(images))
+	}
+	return nil
+}
+
+func runRegistryRestart(a *app.AppContext, opts *RegistryOptions, runtime containerRuntime) error {
+	exists, err := runtime.ContainerExists(opts.Name)
+	if err != nil {
+		return fmt.Errorf("Error checking registry container %s: %w", opts.Name, err)
+	}
+	if !exists {
+		return fmt.Errorf("Registry container %s does not exist. Please run 'autark setup' first", opts.Name)
+	}
+
+	a.WriteF("Restarting registry container %s ...", opts.Name)
+	a.WriteLn("")
+
+	if err := runtime.RestartContainer(opts.Name); err != nil {
+		return fmt.Errorf("Failed to restart registry container %s: %w", opts.Name, err)
+	}
+
+	err = utils.PollUntil(opts.HealthTimeout, registryHealthInterval, func() (bool, error) {
+		return runtime.IsContainerHealthy(opts.Name)
+	})
+	if err != nil {
+		return fmt.Errorf("Registry container %s is not healthy after restart: %w", opts.Name, err)
+	}
+
+	a.WriteF("Registry container %s restarted successfully.", opts.Name)
+	a.WriteLn("")
+
+	return nil
+}
+
+func runRegistryWatch(a *app.AppContext, opts *RegistryWatchOptions, client *http.Client) error {
+	if opts.Password != "" && opts.User == "" {
+		return fmt.Errorf("--registry-password requires --registry-user")
+	}
+	if opts.Interval <= 0 {
+		return fmt.Errorf("invalid --interval value %s: expected a positive duration, like 5s", opts.Interval)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	a.WriteF("Watching %s every %s, press Ctrl-C to stop ...", utils.RedactValue(opts.URL), opts.Interval)
+	a.WriteLn("")
+
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+
+	watcher := &registryWatcher{}
+	for {
+		result := checkRegistryReachability(client, opts.URL, opts.User, opts.Password)
+
+		now := time.Now()
+		if previous, duration, changed := watcher.update(result.Status, now); changed {
+			status := a.Status(app.StatusOK)
+			if result.Status != registryStatusOK {
+				status = a.Status(app.StatusError)
+			}
+
+			a.WriteF("%s %s %s: %s", now.Format(time.DateTime), status, result.Status, result.Message)
+			if previous != "" {
+				a.WriteF(" (was %s for %s)", previous, duration.Round(time.Second))
+			}
+			a.WriteLn("")
+		}
+
+		select {
+		case <-ctx.Done():
+			a.WriteLn("Stopped watching.")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
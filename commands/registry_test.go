@@ -0,0 +1,282 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeRuntime is a containerRuntime for tests, which
+// records the called operations
+type fakeRuntime struct {
+	calls      []string
+	exists     bool
+	existsErr  error
+	healthy    bool
+	restartErr error
+}
+
+func (r *fakeRuntime) ContainerExists(name string) (bool, error) {
+	r.calls = append(r.calls, "exists "+name)
+	return r.exists, r.existsErr
+}
+
+func (r *fakeRuntime) ImageExists(image string) (bool, error) {
+	r.calls = append(r.calls, "image-exists "+image)
+	return false, nil
+}
+
+func (r *fakeRuntime) IsContainerHealthy(name string) (bool, error) {
+	r.calls = append(r.calls, "healthy "+name)
+	return r.healthy, nil
+}
+
+func (r *fakeRuntime) PullImage(image string) error {
+	r.calls = append(r.calls, "pull "+image)
+	return nil
+}
+
+func (r *fakeRuntime) PushImage(image string) error {
+	r.calls = append(r.calls, "push "+image)
+	return nil
+}
+
+func (r *fakeRuntime) RestartContainer(name string) error {
+	r.calls = append(r.calls, "restart "+name)
+	return r.restartErr
+}
+
+func (r *fakeRuntime) SetRestartPolicy(name string, policy string) error {
+	r.calls = append(r.calls, "restart-policy "+name+" "+policy)
+	return nil
+}
+
+func (r *fakeRuntime) TagImage(source string, target string) error {
+	r.calls = append(r.calls, "tag "+source+" "+target)
+	return nil
+}
+
+func TestRunRegistryRestart(t *testing.T) {
+	tests := []struct {
+		name      string
+		runtime   *fakeRuntime
+		wantCalls []string
+		wantErr   string
+	}{
+		{
+			name:      "healthy",
+			runtime:   &fakeRuntime{exists: true, healthy: true},
+			wantCalls: []string{"exists registry", "restart registry", "healthy registry"},
+		},
+		{
+			name:      "missing container",
+			runtime:   &fakeRuntime{exists: false},
+			wantCalls: []string{"exists registry"},
+			wantErr:   "does not exist",
+		},
+		{
+			name:      "inspect fails",
+			runtime:   &fakeRuntime{existsErr: errors.New("daemon not running")},
+			wantCalls: []string{"exists registry"},
+			wantErr:   "daemon not running",
+		},
+		{
+			name:      "restart fails",
+			runtime:   &fakeRuntime{exists: true, restartErr: errors.New("permission denied")},
+			wantCalls: []string{"exists registry", "restart registry"},
+			wantErr:   "Failed to restart",
+		},
+		{
+			name:      "unhealthy",
+			runtime:   &fakeRuntime{exists: true, healthy: false},
+			wantCalls: []string{"exists registry", "restart registry", "healthy registry"},
+			wantErr:   "not healthy",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &RegistryOptions{Name: "registry", HealthTimeout: 10 * time.Millisecond}
+
+			err := runRegistryRestart(newTestAppContext(t), opts, tt.runtime)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("runRegistryRestart() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("runRegistryRestart() error = %v, want %q", err, tt.wantErr)
+			}
+			if !slices.Equal(tt.runtime.calls, tt.wantCalls) {
+				t.Errorf("calls = %q, want %q", tt.runtime.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestCheckRegistryReachability(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/teapot/v2/":
+			w.WriteHeader(http.StatusTeapot)
+		case r.URL.Path != "/v2/":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			user, password, ok := r.BasicAuth()
+			if !ok || user != "admin" || password != "s3cr3t" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name           string
+		baseURL        string
+		user           string
+		password       string
+		wantStatus     string
+		wantStatusCode int
+	}{
+		{name: "ok", baseURL: server.URL + "/", user: "admin", password: "s3cr3t", wantStatus: registryStatusOK, wantStatusCode: http.StatusOK},
+		{name: "auth required", baseURL: server.URL, wantStatus: registryStatusAuthRequired, wantStatusCode: http.StatusUnauthorized},
+		{name: "auth failed", baseURL: server.URL, user: "admin", password: "wrong", wantStatus: registryStatusAuthFailed, wantStatusCode: http.StatusUnauthorized},
+		{name: "misconfigured", baseURL: server.URL + "/teapot", wantStatus: registryStatusMisconfigured, wantStatusCode: http.StatusTeapot},
+		{name: "unreachable", baseURL: "http://127.0.0.1:1", wantStatus: registryStatusUnreachable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := checkRegistryReachability(server.Client(), tt.baseURL, tt.user, tt.password)
+			if result.Status != tt.wantStatus || result.StatusCode != tt.wantStatusCode {
+				t.Errorf("checkRegistryReachability() = %s (%d), want %s (%d)", result.Status, result.StatusCode, tt.wantStatus, tt.wantStatusCode)
+			}
+		})
+	}
+}
+
+func TestRegistryWatcherUpdate(t *testing.T) {
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	steps := []struct {
+		status       string
+		after        time.Duration
+		wantPrevious string
+		wantDuration time.Duration
+		wantChanged  bool
+	}{
+		{status: registryStatusOK, after: 0, wantChanged: true},
+		{status: registryStatusOK, after: 5 * time.Second, wantChanged: false},
+		{status: registryStatusUnreachable, after: 10 * time.Second, wantPrevious: registryStatusOK, wantDuration: 10 * time.Second, wantChanged: true},
+		{status: registryStatusUnreachable, after: 15 * time.Second, wantChanged: false},
+		{status: registryStatusOK, after: 40 * time.Second, wantPrevious: registryStatusUnreachable, wantDuration: 30 * time.Second, wantChanged: true},
+	}
+
+	watcher := &registryWatcher{}
+	for i, step := range steps {
+		previous, duration, changed := watcher.update(step.status, start.Add(step.after))
+		if previous != step.wantPrevious || duration != step.wantDuration || changed != step.wantChanged {
+			t.Errorf("step %d: update(%q) = %q, %s, %v, want %q, %s, %v", i, step.status, previous, duration, changed, step.wantPrevious, step.wantDuration, step.wantChanged)
+		}
+	}
+}
+
+func TestParseNextLink(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{header: `</v2/_catalog?last=b&n=100>; rel="next"`, want: "/v2/_catalog?last=b&n=100"},
+		{header: `</v2/_catalog?last=b&n=100>; rel=next`, want: "/v2/_catalog?last=b&n=100"},
+		{header: `<https://example.com/docs>; rel="help", </v2/app/tags/list?last=v2&n=100>; title="x"; rel="next"`, want: "/v2/app/tags/list?last=v2&n=100"},
+		{header: `</v2/_catalog?last=a>; rel="prev"`, want: ""},
+		{header: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := parseNextLink(tt.header); got != tt.want {
+			t.Errorf("parseNextLink(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestListRegistryRepositoriesPaging(t *testing.T) {
+	pages := map[string][]string{
+		"":      {"alpha", "beta"},
+		"beta":  {"gamma", "delta"},
+		"delta": {"epsilon"},
+	}
+	next := map[string]string{"": "beta", "beta": "delta"}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/_catalog" {
+			http.NotFound(w, r)
+			return
+		}
+		if user, password, ok := r.BasicAuth(); !ok || user != "admin" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		last := r.URL.Query().Get("last")
+		if n := r.URL.Query().Get("n"); n == "" {
+			t.Errorf("request %s without page size", r.URL)
+		}
+		if after, ok := next[last]; ok {
+			w.Header().Set("Link", `</v2/_catalog?last=`+after+`&n=2>; rel="next"`)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"repositories": ["%s"]}`, strings.Join(pages[last], `", "`))
+	}))
+	defer server.Close()
+
+	got, err := listRegistryRepositories(server.Client(), server.URL+"/", "admin", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"alpha", "beta", "gamma", "delta", "epsilon"}; !slices.Equal(got, want) {
+		t.Errorf("listRegistryRepositories() = %q, want %q", got, want)
+	}
+
+	if _, err := listRegistryRepositories(server.Client(), server.URL, "", ""); err == nil || !strings.Contains(err.Error(), "requires authentication") {
+		t.Errorf("listRegistryRepositories() without credentials error = %v, want an authentication error", err)
+	}
+}
+
+func TestListRegistryRepositoriesSamePage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", `<`+r.URL.String()+`>; rel="next"`)
+		fmt.Fprint(w, `{"repositories": ["alpha"]}`)
+	}))
+	defer server.Close()
+
+	if _, err := listRegistryRepositories(server.Client(), server.URL, "", ""); err == nil {
+		t.Error("listRegistryRepositories() error = nil, want an error for a repeated page")
+	}
+}
@@ -0,0 +1,242 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mkloubert/autark/app"
+	"github.com/mkloubert/autark/utils"
+	"github.com/spf13/pflag"
+)
+
+// remoteBinaryName is the name of the copy of the autark binary
+// inside the home directory of the remote user
+const remoteBinaryName = ".autark-remote"
+
+// remoteLocalFlags contains the flags, which are not forwarded
+// to the remote host, because they only make sense locally
+var remoteLocalFlags = []string{"config", "remote", "remote-key", "remote-port", "work-dir"}
+
+// remoteFileFlags contains the flags, whose values can be paths of
+// local files, which do not exist on the remote host
+var remoteFileFlags = []string{"after-setup-hook", "registry-htpasswd-file"}
+
+// RemoteOptions contains the options to run a command
+// on a remote host via SSH
+type RemoteOptions struct {
+	// Target is the remote host, like "user@host"
+	Target string
+	// Port is the port of the SSH server
+	Port int
+	// IdentityFile is the optional path of the private key
+	IdentityFile string
+}
+
+// buildRemoteSCPArgs returns the arguments for scp, which copies
+// a local file to a path of the remote host
+func buildRemoteSCPArgs(opts *RemoteOptions, localPath string, remotePath string) []string {
+	args := []string{"-q", "-P", strconv.Itoa(opts.Port)}
+	args = append(args, buildRemoteSSHOptionArgs(opts)...)
+
+	return append(args, localPath, opts.Target+":"+remotePath)
+}
+
+// buildRemoteSSHArgs returns the arguments for ssh, which runs a
+// command on the remote host, where tty allocates a terminal for
+// the prompts of the remote command
+func buildRemoteSSHArgs(opts *RemoteOptions, tty bool, command string) []string {
+	args := []string{"-p", strconv.Itoa(opts.Port)}
+	if tty {
+		args = append(args, "-t")
+	}
+	args = append(args, buildRemoteSSHOptionArgs(opts)...)
+
+	return append(args, opts.Target, command)
+}
+
+// buildRemoteSSHOptionArgs returns the arguments, which are shared by
+// ssh and scp and only allow key-based authentication
+func buildRemoteSSHOptionArgs(opts *RemoteOptions) []string {
+	args := make([]string, 0)
+	if opts.IdentityFile != "" {
+		args = append(args, "-i", opts.IdentityFile)
+	}
+
+	return append(args, "-o", "PreferredAuthentications=publickey")
+}
+
+// buildRemoteCommand returns the shell command, which runs the copy of
+// the autark binary on the remote host and removes it afterwards,
+// while the exit code of autark is kept
+func buildRemoteCommand(args []string) string {
+	quotedArgs := make([]string, 0, len(args))
+	for _, arg := range args {
+		quotedArgs = append(quotedArgs, quoteShellArg(arg))
+	}
+
+	return fmt.Sprintf(
+		"chmod +x ./%[1]s && ./%[1]s %[2]s; status=$?; rm -f ./%[1]s; exit $status",
+		remoteBinaryName, strings.Join(quotedArgs, " "),
+	)
+}
+
+// collectRemoteArgs returns the flags, which have been set for the
+// current command, as arguments for the remote command
+//
+// Flags of remoteFileFlags are rejected, because their local
+// files do not exist on the remote host
+func collectRemoteArgs(flags *pflag.FlagSet) ([]string, error) {
+	args := make([]string, 0)
+
+	var err error
+	flags.Visit(func(flag *pflag.Flag) {
+		if slices.Contains(remoteLocalFlags, flag.Name) {
+			return
+		}
+		if slices.Contains(remoteFileFlags, flag.Name) {
+			err = fmt.Errorf("--%s cannot be used with --remote, because the file does not exist on the remote host", flag.Name)
+			return
+		}
+
+		if sliceValue, ok := flag.Value.(pflag.SliceValue); ok {
+			for _, value := range sliceValue.GetSlice() {
+				args = append(args, fmt.Sprintf("--%s=%s", flag.Name, value))
+			}
+			return
+		}
+
+		args = append(args, fmt.Sprintf("--%s=%s", flag.Name, flag.Value.String()))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return args, nil
+}
+
+func initRemoteFlags(flags *pflag.FlagSet, opts *RemoteOptions) {
+	flags.StringVarP(&opts.Target, "remote", "", "", "Run on a remote host via SSH instead, like user@host")
+	flags.IntVarP(&opts.Port, "remote-port", "", 22, "Port of the SSH server of the remote host")
+	flags.StringVarP(&opts.IdentityFile, "remote-key", "", "", "Private key for the SSH connection to the remote host")
+}
+
+// parseRemotePlatform parses the output of "uname -sm" into
+// the names of the OS and the architecture used by Go
+func parseRemotePlatform(output string) (string, string) {
+	fields := strings.Fields(output)
+	if len(fields) < 2 {
+		return "", ""
+	}
+
+	goos := strings.ToLower(fields[0])
+
+	goarch := fields[1]
+	switch goarch {
+	case "x86_64":
+		goarch = "amd64"
+	case "aarch64":
+		goarch = "arm64"
+	case "i386", "i686":
+		goarch = "386"
+	case "armv6l", "armv7l":
+		goarch = "arm"
+	}
+
+	return goos, goarch
+}
+
+// quoteShellArg quotes an argument for a POSIX shell
+func quoteShellArg(arg string) string {
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
+
+// runRemote copies the autark binary to a remote host and runs it
+// there with args, while its output is streamed back
+//
+// The remote host must have the same OS and architecture, because
+// the binary of this host is copied
+func runRemote(a *app.AppContext, opts *RemoteOptions, args []string) error {
+	if !utils.CommandExists("ssh") || !utils.CommandExists("scp") {
+		return fmt.Errorf("--remote requires the ssh and scp commands")
+	}
+	if opts.Port < 1 || opts.Port > 65535 {
+		return fmt.Errorf("invalid --remote-port value %d", opts.Port)
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("could not find the autark binary: %w", err)
+	}
+
+	a.WriteF("Checking remote host %s...", opts.Target)
+	a.WriteLn("")
+
+	unameArgs := buildRemoteSSHArgs(opts, false, "uname -sm")
+	a.D("Running: %s", utils.RedactCommandLine("ssh", unameArgs...))
+	output, err := utils.RunCommand("ssh", unameArgs...)
+	if err != nil {
+		return fmt.Errorf("could not connect to %s: %w", opts.Target, newInstallCommandError("ssh", err, output))
+	}
+
+	goos, goarch := parseRemotePlatform(string(output))
+	if goos != runtime.GOOS || goarch != runtime.GOARCH {
+		return fmt.Errorf("remote host %s runs %s/%s, but this binary is built for %s/%s", opts.Target, goos, goarch, runtime.GOOS, runtime.GOARCH)
+	}
+
+	a.WriteF("Copying autark to %s...", opts.Target)
+	a.WriteLn("")
+
+	scpArgs := buildRemoteSCPArgs(opts, executable, remoteBinaryName)
+	a.D("Running: %s", utils.RedactCommandLine("scp", scpArgs...))
+	if output, err := utils.RunCommand("scp", scpArgs...); err != nil {
+		return fmt.Errorf("could not copy autark to %s: %w", opts.Target, newInstallCommandError("scp", err, output))
+	}
+
+	// the remote command is a single argument of ssh, so its
+	// arguments are redacted before it is built for the logs
+	redactedArgs := utils.RedactArgs(args)
+
+	a.WriteF("Running 'autark %s' on %s...", strings.Join(redactedArgs, " "), opts.Target)
+	a.WriteLn("")
+	a.WriteLn("")
+
+	tty := utils.IsTerminal(a.Stdin())
+	sshArgs := buildRemoteSSHArgs(opts, tty, buildRemoteCommand(args))
+	a.D("Running: %s", utils.RedactCommandLine("ssh", buildRemoteSSHArgs(opts, tty, buildRemoteCommand(redactedArgs))...))
+
+	cmd := exec.Command("ssh", sshArgs...)
+	cmd.Stdin = a.Stdin()
+	cmd.Stdout = a.Stdout()
+	cmd.Stderr = a.Stderr()
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("autark failed on %s: %w", opts.Target, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,176 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package commands
+
+import (
+	"os/exec"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mkloubert/autark/utils"
+	"github.com/spf13/pflag"
+)
+
+func TestBuildRemoteSSHArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		opts *RemoteOptions
+		tty  bool
+		want []string
+	}{
+		{
+			name: "defaults",
+			opts: &RemoteOptions{Target: "admin@server", Port: 22},
+			want: []string{"-p", "22", "-o", "PreferredAuthentications=publickey", "admin@server", "uname -sm"},
+		},
+		{
+			name: "tty and key",
+			opts: &RemoteOptions{Target: "admin@server", Port: 2222, IdentityFile: "/home/me/.ssh/id_ed25519"},
+			tty:  true,
+			want: []string{"-p", "2222", "-t", "-i", "/home/me/.ssh/id_ed25519", "-o", "PreferredAuthentications=publickey", "admin@server", "uname -sm"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := buildRemoteSSHArgs(tt.opts, tt.tty, "uname -sm"); !slices.Equal(got, tt.want) {
+				t.Errorf("buildRemoteSSHArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuoteShellArg(t *testing.T) {
+	tests := []struct {
+		arg  string
+		want string
+	}{
+		{arg: "doctor", want: `'doctor'`},
+		{arg: "", want: `''`},
+		{arg: "--pkg-arg=--no-install-recommends", want: `'--pkg-arg=--no-install-recommends'`},
+		{arg: "it's", want: `'it'\''s'`},
+		{arg: "$(rm -rf /); `id`", want: "'$(rm -rf /); `id`'"},
+	}
+
+	for _, tt := range tests {
+		if got := quoteShellArg(tt.arg); got != tt.want {
+			t.Errorf("quoteShellArg(%q) = %q, want %q", tt.arg, got, tt.want)
+		}
+	}
+}
+
+func TestBuildRemoteCommand(t *testing.T) {
+	got := buildRemoteCommand([]string{"doctor", "--repair", "--pkg-arg=it's"})
+
+	want := `chmod +x ./.autark-remote && ./.autark-remote 'doctor' '--repair' '--pkg-arg=it'\''s'; status=$?; rm -f ./.autark-remote; exit $status`
+	if got != want {
+		t.Errorf("buildRemoteCommand() = %q, want %q", got, want)
+	}
+}
+
+func TestBuildRemoteCommandRedacted(t *testing.T) {
+	args := []string{"setup", "--registry-env=REGISTRY_HTTP_SECRET=s3cr3t", "--registry-env=REGISTRY_LOG_LEVEL=debug"}
+
+	got := utils.RedactCommandLine("ssh", buildRemoteSSHArgs(&RemoteOptions{Target: "root@host", Port: 22}, false, buildRemoteCommand(utils.RedactArgs(args)))...)
+	if strings.Contains(got, "s3cr3t") {
+		t.Errorf("logged remote command line %q contains the secret", got)
+	}
+	if !strings.Contains(got, "'--registry-env=REGISTRY_HTTP_SECRET=********'") || !strings.Contains(got, "'--registry-env=REGISTRY_LOG_LEVEL=debug'") {
+		t.Errorf("logged remote command line = %q, want the redacted --registry-env values", got)
+	}
+
+	// the command, which is run, keeps the secret
+	if !strings.Contains(buildRemoteCommand(args), "REGISTRY_HTTP_SECRET=s3cr3t") {
+		t.Error("buildRemoteCommand() lost the value of --registry-env")
+	}
+}
+
+func TestQuoteShellArgWithShell(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sh is not available")
+	}
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+
+	args := []string{"it's", "$HOME", "a b", "`id`", ""}
+	for _, arg := range args {
+		output, err := exec.Command("sh", "-c", "printf %s "+quoteShellArg(arg)).Output()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(output) != arg {
+			t.Errorf("sh received %q, want %q", output, arg)
+		}
+	}
+}
+
+func TestCollectRemoteArgs(t *testing.T) {
+	flags := pflag.NewFlagSet("setup", pflag.ContinueOnError)
+	flags.String("remote", "", "")
+	flags.String("registry-name", "registry", "")
+	flags.Int("registry-port", 5000, "")
+	flags.StringSlice("registry-env", nil, "")
+	flags.String("registry-htpasswd-file", "", "")
+
+	if err := flags.Parse([]string{"--remote=root@server", "--registry-port=5001", "--registry-env=A=1", "--registry-env=B=2"}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := collectRemoteArgs(flags)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"--registry-env=A=1", "--registry-env=B=2", "--registry-port=5001"}
+	if !slices.Equal(got, want) {
+		t.Errorf("collectRemoteArgs() = %q, want %q", got, want)
+	}
+}
+
+func TestCollectRemoteArgsRejectsLocalFiles(t *testing.T) {
+	tests := []struct {
+		flag  string
+		value string
+	}{
+		{flag: "registry-htpasswd-file", value: "./htpasswd"},
+		{flag: "after-setup-hook", value: "./seed-images.sh"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.flag, func(t *testing.T) {
+			flags := pflag.NewFlagSet("setup", pflag.ContinueOnError)
+			flags.String("remote", "", "")
+			flags.String(tt.flag, "", "")
+
+			if err := flags.Parse([]string{"--remote=root@server", "--" + tt.flag + "=" + tt.value}); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := collectRemoteArgs(flags)
+			if err == nil || !strings.Contains(err.Error(), "--"+tt.flag) {
+				t.Errorf("collectRemoteArgs() error = %v, want an error for --%s", err, tt.flag)
+			}
+		})
+	}
+}
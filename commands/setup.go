@@ -22,29 +22,170 @@
 package commands
 
 import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
 	"fmt"
+	"io"
+	"maps"
 	"math/rand"
 	"net"
+	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"regexp"
 	"runtime"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/mkloubert/autark/app"
 	"github.com/mkloubert/autark/utils"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 const (
+	registryCertFileName  = "registry.crt"
+	registryCertValidity  = 10 * 365 * 24 * time.Hour
 	registryContainerName = "autark-registry"
+	registryContainerPort = 5000
+	registryHtpasswdPath  = "/auth/htpasswd"
 	registryImage         = "registry:2"
+	registryKeyFileName   = "registry.key"
 )
 
+// dockerDaemonRestartTimeout is the maximum time to wait
+// for the docker daemon after restarting it
+const dockerDaemonRestartTimeout = time.Minute
+
+// registryLogLevels contains the log levels, which are
+// accepted by the registry
+var registryLogLevels = []string{"error", "warn", "info", "debug"}
+
+const (
+	// registryPullAlways pulls the registry image on every installation
+	registryPullAlways = "always"
+	// registryPullMissing only pulls the registry image, if it does not exist
+	registryPullMissing = "missing"
+	// registryPullNever never pulls the registry image
+	registryPullNever = "never"
+)
+
+const (
+	// defaultUploadPurgingAge is the default minimum age of orphaned
+	// uploads, which are deleted by the registry
+	defaultUploadPurgingAge = "168h"
+	// defaultUploadPurgingInterval is the default interval, in which
+	// the registry deletes orphaned uploads
+	defaultUploadPurgingInterval = "24h"
+)
+
+// registryPullPolicies contains the values of --pull
+var registryPullPolicies = []string{registryPullAlways, registryPullMissing, registryPullNever}
+
+// registryMemoryRegex matches memory limits, which are supported
+// by "docker run --memory", like "512m" or "2g"
+var registryMemoryRegex = regexp.MustCompile(`^(?i)\d+(\.\d+)?[bkmg]?$`)
+
+// linuxFirewallInstallers contains the functions, which install a firewall
+// on specific Linux distributions
+var linuxFirewallInstallers = map[utils.LinuxDistro]func(a *app.AppContext) error{
+	utils.DistroDebian:   installFirewallDebian,
+	utils.DistroUbuntu:   installFirewallDebian,
+	utils.DistroFedora:   installFirewallFedora,
+	utils.DistroRHEL:     installFirewallFedora,
+	utils.DistroCentOS:   installFirewallFedora,
+	utils.DistroArch:     installFirewallArch,
+	utils.DistroAlpine:   installFirewallAlpine,
+	utils.DistroOpenSUSE: installFirewallOpenSUSE,
+	utils.DistroGentoo:   installFirewallGentoo,
+	utils.DistroVoid:     installFirewallVoid,
+}
+
+// linuxSSHInstallers contains the functions, which install an SSH server
+// on specific Linux distributions
+var linuxSSHInstallers = map[utils.LinuxDistro]func(a *app.AppContext, port int) error{
+	utils.DistroDebian:   installSSHDebian,
+	utils.DistroUbuntu:   installSSHDebian,
+	utils.DistroFedora:   installSSHFedora,
+	utils.DistroRHEL:     installSSHFedora,
+	utils.DistroCentOS:   installSSHFedora,
+	utils.DistroArch:     installSSHArch,
+	utils.DistroAlpine:   installSSHAlpine,
+	utils.DistroOpenSUSE: installSSHOpenSUSE,
+	utils.DistroGentoo:   installSSHGentoo,
+	utils.DistroVoid:     installSSHVoid,
+}
+
 // SetupOptions contains options for the setup command
 type SetupOptions struct {
 	RegistryPort int
 	NoFirewall   bool
 	NoSSH        bool
+	Open         bool
+	SelfSigned   bool
+	Memory       string
+	CPUs         string
+	// RegistryName is the name of the registry container
+	RegistryName string
+	// RegistryLogLevel is the log level of the registry
+	RegistryLogLevel string
+	// Pull is the pull policy of the registry image,
+	// like "always", "missing" or "never"
+	Pull string
+	// RegistryEnv contains additional environment variables of the
+	// registry container as KEY=VALUE pairs
+	RegistryEnv []string
+	// RegistryHtpasswdFile is the path of an existing htpasswd file,
+	// which is used for the basic authentication of the registry
+	RegistryHtpasswdFile string
+	// RegistryHostname is the externally reachable hostname of the
+	// registry, which is detected, if empty
+	RegistryHostname string
+	// RegistryInterface is the name of the network interface, whose
+	// IPv4 address is used instead of the detected primary IP
+	RegistryInterface string
+	// TrustCert indicates if the self-signed certificate should
+	// be trusted by the docker daemon of this host
+	TrustCert bool
+	// AfterSetupHook is the path of a script or a command, which
+	// is run after the registry has been set up successfully
+	AfterSetupHook string
+	// Launchd indicates if the registry should be kept running by
+	// a launchd agent instead of the restart policy of docker
+	Launchd bool
+	// SystemdUnit indicates if the registry is kept running by the
+	// systemd unit of "registry install-service" instead of the
+	// restart policy of docker, which is detected by setup
+	SystemdUnit bool
+	// OpenFirewall indicates if the port of the registry should
+	// be opened in the detected firewall
+	OpenFirewall bool
+	// PersistFirewall indicates if the rule of OpenFirewall should
+	// survive a reload of the firewall and a reboot
+	PersistFirewall bool
+	// UploadPurging indicates if the registry should delete
+	// orphaned uploads regularly
+	UploadPurging bool
+	// UploadPurgingAge is the minimum age of the uploads,
+	// which are deleted, like "168h"
+	UploadPurgingAge string
+	// UploadPurgingInterval is the interval of the
+	// deletion of uploads, like "24h"
+	UploadPurgingInterval string
+	// ConfigureInsecure indicates if the registry should be added
+	// to the insecure registries of the docker daemon of this host
+	ConfigureInsecure bool
+	// IPv6 indicates if the port of the registry should be published
+	// explicitly on all IPv4 and all IPv6 addresses of the host
+	IPv6 bool
 }
 
 // FirewallInfo contains information about the detected firewall
@@ -61,6 +202,166 @@ type SSHInfo struct {
 	Running   bool
 }
 
+// registryContainerConfig contains the options of a registry
+// container, which are compared to detect changes
+type registryContainerConfig struct {
+	// Image is the image, like "registry:2"
+	Image string
+	// Port is the port on the host, which is 0 if unknown
+	Port int
+	// Env contains the environment variables, which are set by autark
+	Env map[string]string
+	// Memory is the memory limit in bytes, which is 0 if not limited
+	Memory int64
+	// NanoCPUs is the CPU limit in billionths of CPUs,
+	// which is 0 if not limited
+	NanoCPUs int64
+	// IPv6 indicates if the port is published on an IPv6 address
+	IPv6 bool
+}
+
+// buildAfterSetupHookEnv returns the environment variables,
+// which describe the registry for the after-setup hook
+func buildAfterSetupHookEnv(opts *SetupOptions, hostname string, port int, certsDir string) []string {
+	scheme := "http"
+	certFile := ""
+	if certsDir != "" {
+		scheme = "https"
+		certFile = filepath.Join(certsDir, registryCertFileName)
+	}
+
+	return []string{
+		"AUTARK_REGISTRY_ADDRESS=" + net.JoinHostPort(hostname, strconv.Itoa(port)),
+		"AUTARK_REGISTRY_CERT=" + certFile,
+		"AUTARK_REGISTRY_HOST=" + hostname,
+		"AUTARK_REGISTRY_NAME=" + opts.RegistryName,
+		"AUTARK_REGISTRY_PORT=" + strconv.Itoa(port),
+		"AUTARK_REGISTRY_SCHEME=" + scheme,
+	}
+}
+
+// buildPortMapping builds the value of the "-p" argument of "docker run",
+// like "5000:5000" or "127.0.0.1:5000:5000", which publishes a TCP port
+// of a container on a port of the host
+//
+// bind is an optional IP address of the host, hostPort and
+// containerPort have to be between 1 and 65535
+func buildPortMapping(bind string, hostPort int, containerPort int) (string, error) {
+	if hostPort < 1 || hostPort > 65535 {
+		return "", fmt.Errorf("invalid host port %d: expected a port between 1 and 65535", hostPort)
+	}
+	if containerPort < 1 || containerPort > 65535 {
+		return "", fmt.Errorf("invalid container port %d: expected a port between 1 and 65535", containerPort)
+	}
+
+	mapping := fmt.Sprintf("%d:%d", hostPort, containerPort)
+	if bind == "" {
+		return mapping, nil
+	}
+
+	ip := net.ParseIP(strings.Trim(bind, "[]"))
+	if ip == nil {
+		return "", fmt.Errorf("invalid bind address %q: expected an IP address", bind)
+	}
+	if ip.To4() == nil {
+		return fmt.Sprintf("[%s]:%s", ip, mapping), nil
+	}
+	return fmt.Sprintf("%s:%s", ip, mapping), nil
+}
+
+// buildRegistryEnvArgs builds the "-e KEY=VALUE" arguments for
+// "docker run", which are sorted by key
+func buildRegistryEnvArgs(env map[string]string) []string {
+	args := make([]string, 0, len(env)*2)
+
+	for _, key := range slices.Sorted(maps.Keys(env)) {
+		args = append(args, "-e", key+"="+env[key])
+	}
+
+	return args
+}
+
+// buildRegistryPortMappings builds the values of the "-p" arguments
+// of "docker run", which publish the port of the registry
+//
+// With ipv6, the port is published on "0.0.0.0" and "[::]"
+// explicitly, otherwise docker decides, which addresses are used
+func buildRegistryPortMappings(hostPort int, ipv6 bool) ([]string, error) {
+	binds := []string{""}
+	if ipv6 {
+		binds = []string{"0.0.0.0", "::"}
+	}
+
+	mappings := make([]string, 0, len(binds))
+	for _, bind := range binds {
+		mapping, err := buildPortMapping(bind, hostPort, registryContainerPort)
+		if err != nil {
+			return nil, err
+		}
+
+		mappings = append(mappings, mapping)
+	}
+
+	return mappings, nil
+}
+
+// buildRegistryRunArgs builds the arguments for "docker run", which
+// starts the registry container
+//
+// If certsDir is not empty, the registry is served via TLS with
+// the certificate and key from this directory
+func buildRegistryRunArgs(opts *SetupOptions, certsDir string) ([]string, error) {
+	portMappings, err := buildRegistryPortMappings(opts.RegistryPort, opts.IPv6)
+	if err != nil {
+		return nil, err
+	}
+
+	// the launchd agent or the systemd unit restarts the container itself
+	restartPolicy := "--restart=always"
+	if opts.Launchd || opts.SystemdUnit {
+		restartPolicy = "--restart=no"
+	}
+
+	args := []string{
+		"run",
+		"-d",
+		"--name", opts.RegistryName,
+		restartPolicy,
+	}
+
+	for _, portMapping := range portMappings {
+		args = append(args, "-p", portMapping)
+	}
+
+	if certsDir != "" {
+		args = append(args, "-v", fmt.Sprintf("%s:/certs:ro", certsDir))
+	}
+	if opts.RegistryHtpasswdFile != "" {
+		args = append(args, "-v", fmt.Sprintf("%s:%s:ro", opts.RegistryHtpasswdFile, registryHtpasswdPath))
+	}
+
+	args = append(args, buildRegistryEnvArgs(getRegistryEnv(opts, certsDir != ""))...)
+
+	if opts.Memory != "" {
+		args = append(args, "--memory", opts.Memory)
+	}
+	if opts.CPUs != "" {
+		args = append(args, "--cpus", opts.CPUs)
+	}
+
+	return append(args, registryImage), nil
+}
+
+// checkDockerAvailable checks if docker is installed
+// and its daemon is running
+func checkDockerAvailable() error {
+	if !utils.CommandExists("docker") {
+		return fmt.Errorf("Docker is not installed. Please run 'autark doctor --repair' first")
+	}
+
+	return checkDockerDaemonRunning()
+}
+
 func checkDockerDaemonRunning() error {
 	output, err := utils.RunCommand("docker", "info")
 	if err != nil {
@@ -77,7 +378,58 @@ func checkDockerDaemonRunning() error {
 	return nil
 }
 
-func checkRegistryRunning() (bool, error) {
+// compareRegistryContainerConfig compares the config of a running
+// registry container with the expected one and returns the
+// differences, like "port: 5001 (expected: 5000)"
+//
+// The port is only compared, if it is known in both configs
+func compareRegistryContainerConfig(actual *registryContainerConfig, expected *registryContainerConfig) []string {
+	differences := make([]string, 0)
+
+	if actual.Image != expected.Image {
+		differences = append(differences, fmt.Sprintf("image: %s (expected: %s)", actual.Image, expected.Image))
+	}
+	if actual.Port != 0 && expected.Port != 0 && actual.Port != expected.Port {
+		differences = append(differences, fmt.Sprintf("port: %d (expected: %d)", actual.Port, expected.Port))
+	}
+	if actual.IPv6 != expected.IPv6 {
+		differences = append(differences, fmt.Sprintf("ipv6: %t (expected: %t)", actual.IPv6, expected.IPv6))
+	}
+
+	keys := slices.Collect(maps.Keys(expected.Env))
+	for key := range actual.Env {
+		// variables of the image itself do not start with REGISTRY_
+		if _, ok := expected.Env[key]; !ok && strings.HasPrefix(key, "REGISTRY_") {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		actualValue, actualOK := actual.Env[key]
+		expectedValue, expectedOK := expected.Env[key]
+
+		switch {
+		case !actualOK:
+			differences = append(differences, fmt.Sprintf("%s: not set (expected: %s)", key, redactEnvValue(key, expectedValue)))
+		case !expectedOK:
+			differences = append(differences, fmt.Sprintf("%s: %s (expected: not set)", key, redactEnvValue(key, actualValue)))
+		case actualValue != expectedValue:
+			differences = append(differences, fmt.Sprintf("%s: %s (expected: %s)", key, redactEnvValue(key, actualValue), redactEnvValue(key, expectedValue)))
+		}
+	}
+
+	if actual.Memory != expected.Memory {
+		differences = append(differences, fmt.Sprintf("memory: %s (expected: %s)", formatResourceLimit(actual.Memory, 1), formatResourceLimit(expected.Memory, 1)))
+	}
+	if actual.NanoCPUs != expected.NanoCPUs {
+		differences = append(differences, fmt.Sprintf("cpus: %s (expected: %s)", formatResourceLimit(actual.NanoCPUs, 1e9), formatResourceLimit(expected.NanoCPUs, 1e9)))
+	}
+
+	return differences
+}
+
+func checkRegistryRunning(name string) (bool, error) {
 	if !utils.CommandExists("docker") {
 		return false, fmt.Errorf("docker is not installed")
 	}
@@ -88,7 +440,7 @@ func checkRegistryRunning() (bool, error) {
 	}
 
 	// Check if container exists and is running
-	output, err := utils.RunCommand("docker", "ps", "--filter", fmt.Sprintf("name=%s", registryContainerName), "--format", "{{.Status}}")
+	output, err := utils.RunCommand("docker", "ps", "--filter", fmt.Sprintf("name=^/%s$", name), "--format", "{{.Status}}")
 	if err != nil {
 		return false, fmt.Errorf("failed to check docker containers: %w", err)
 	}
@@ -222,6 +574,7 @@ func checkSSHDarwin() *SSHInfo {
 
 	// macOS has SSH built-in, check if Remote Login is enabled
 	cmd := exec.Command("systemsetup", "-getremotelogin")
+	cmd.Env = utils.ParsedCommandEnv()
 	output, err := cmd.Output()
 	if err == nil && strings.Contains(strings.ToLower(string(output)), "on") {
 		info.Running = true
@@ -259,52 +612,429 @@ func configureSSHPort(port int) error {
 		return nil // Default port, no configuration needed
 	}
 
-	// Read current sshd_config
 	configPath := "/etc/ssh/sshd_config"
-	content, err := os.ReadFile(configPath)
-	if err != nil {
+	if _, err := os.Stat(configPath); err != nil {
 		return fmt.Errorf("failed to read sshd_config: %w", err)
 	}
+	if err := checkWritableFilesystem(configPath); err != nil {
+		return err
+	}
+
+	// replaces the Port lines or the commented default, and a new
+	// line has to be placed before any Match block to apply globally
+	if _, err := utils.EnsureLine(configPath, "Port ", fmt.Sprintf("Port %d", port), "Match "); err != nil {
+		return fmt.Errorf("failed to write sshd_config: %w", err)
+	}
+
+	return nil
+}
+
+// configureInsecureRegistry adds the address of the registry to the
+// insecure registries in the daemon.json file of the docker daemon
+// and restarts the daemon, if the file has been changed
+//
+// The previous version of the file is kept as daemon.json.bak
+func configureInsecureRegistry(a *app.AppContext, registryAddress string) error {
+	host, _, _ := net.SplitHostPort(registryAddress)
+	if isLoopbackHost(host) {
+		a.D("Docker trusts %s without configuration", registryAddress)
+		return nil
+	}
 
-	// Check if Port line exists and modify it
-	lines := strings.Split(string(content), "\n")
-	portConfigured := false
-	for i, line := range lines {
-		trimmed := strings.TrimSpace(line)
-		if strings.HasPrefix(trimmed, "Port ") || strings.HasPrefix(trimmed, "#Port ") {
-			lines[i] = fmt.Sprintf("Port %d", port)
-			portConfigured = true
-			break
+	var perm os.FileMode = 0644
+
+	data, err := os.ReadFile(dockerDaemonConfigPath)
+	exists := err == nil
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", dockerDaemonConfigPath, err)
+	}
+
+	newData, changed, err := mergeInsecureRegistry(data, registryAddress)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", dockerDaemonConfigPath, err)
+	}
+	if !changed {
+		a.WriteF("%s is already an insecure registry in %s.", registryAddress, dockerDaemonConfigPath)
+		a.WriteLn("")
+		return nil
+	}
+
+	if err := checkWritableFilesystem(dockerDaemonConfigPath); err != nil {
+		return err
+	}
+
+	if exists {
+		if info, err := os.Stat(dockerDaemonConfigPath); err == nil {
+			perm = info.Mode().Perm()
+		}
+
+		backupPath := dockerDaemonConfigPath + ".bak"
+		if err := utils.AtomicWriteFile(backupPath, data, perm); err != nil {
+			return fmt.Errorf("failed to write %s: %w", backupPath, err)
+		}
+		a.D("Saved a backup of %s as %s", dockerDaemonConfigPath, backupPath)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dockerDaemonConfigPath), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(dockerDaemonConfigPath), err)
+	}
+	if err := utils.AtomicWriteFile(dockerDaemonConfigPath, newData, perm); err != nil {
+		return fmt.Errorf("failed to write %s: %w", dockerDaemonConfigPath, err)
+	}
+
+	a.WriteF("Added %s to the insecure registries in %s.", registryAddress, dockerDaemonConfigPath)
+	a.WriteLn("")
+
+	return restartDockerDaemonLinux(a)
+}
+
+// ensureRegistryCertificate makes sure, that a self-signed certificate
+// for the registry and its hostname exists in the state directory and
+// returns the directory containing the certificate and its key
+func ensureRegistryCertificate(a *app.AppContext, hostname string) (string, error) {
+	certsDir, err := getRegistryCertsDir()
+	if err != nil {
+		return "", err
+	}
+
+	certFile := filepath.Join(certsDir, registryCertFileName)
+	keyFile := filepath.Join(certsDir, registryKeyFileName)
+
+	_, keyErr := os.Stat(keyFile)
+	if existingCert, err := os.ReadFile(certFile); err == nil && keyErr == nil {
+		if isCertificateValidFor(existingCert, hostname) {
+			a.D("Using existing registry certificate: %s", certFile)
+			return certsDir, nil
 		}
+
+		a.D("Existing registry certificate %s is not valid for %s", certFile, hostname)
 	}
 
-	if !portConfigured {
-		// Add Port configuration at the beginning
-		lines = append([]string{fmt.Sprintf("Port %d", port)}, lines...)
+	hosts := []string{"localhost", "127.0.0.1", "::1"}
+	if !slices.Contains(hosts, hostname) {
+		hosts = append(hosts, hostname)
 	}
 
-	// Write back
-	err = os.WriteFile(configPath, []byte(strings.Join(lines, "\n")), 0644)
+	a.WriteF("Generating self-signed certificate for %s ...", strings.Join(hosts, ", "))
+	a.WriteLn("")
+
+	certPEM, keyPEM, err := utils.GenerateSelfSignedCert(hosts, registryCertValidity)
 	if err != nil {
-		return fmt.Errorf("failed to write sshd_config: %w", err)
+		return "", fmt.Errorf("failed to generate certificate: %w", err)
+	}
+
+	if err := os.MkdirAll(certsDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", certsDir, err)
+	}
+	if err := utils.AtomicWriteFile(keyFile, keyPEM, 0600); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", keyFile, err)
+	}
+	if err := utils.AtomicWriteFile(certFile, certPEM, 0644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", certFile, err)
+	}
+	dropFileOwnership(a, certsDir, keyFile, certFile)
+
+	return certsDir, nil
+}
+
+// ensureRegistryImage pulls the registry image depending on
+// the pull policy of --pull
+func ensureRegistryImage(a *app.AppContext, runtime containerRuntime, policy string) error {
+	exists := false
+	if policy != registryPullAlways {
+		var err error
+		exists, err = runtime.ImageExists(registryImage)
+		if err != nil {
+			return err
+		}
+	}
+
+	pull, err := shouldPullRegistryImage(policy, exists)
+	if err != nil {
+		return err
+	}
+	if !pull {
+		a.D("Using existing image %s (--pull %s)", registryImage, policy)
+		return nil
+	}
+
+	a.WriteF("Pulling %s...", registryImage)
+	a.WriteLn("")
+
+	return runtime.PullImage(registryImage)
+}
+
+// ensureRegistryHostnameResolves checks if a custom hostname of the
+// registry can be resolved and offers to add an entry, which points
+// to the primary IP of this host, to the hosts file otherwise
+func ensureRegistryHostnameResolves(a *app.AppContext, hostname string, lookupHost func(host string) ([]string, error), detectHostIP func() (net.IP, error)) error {
+	if net.ParseIP(hostname) != nil {
+		return nil
+	}
+
+	if addrs, err := lookupHost(hostname); err == nil && len(addrs) > 0 {
+		a.D("Registry hostname %s resolves to %s", hostname, strings.Join(addrs, ", "))
+		return nil
+	}
+
+	hostsFile := utils.HostsFilePath()
+
+	a.WriteF("%s Registry hostname %s cannot be resolved.", a.Status(app.StatusWarn), hostname)
+	a.WriteLn("")
+	a.WriteLn("")
+
+	if !a.PromptYesNo(fmt.Sprintf("Would you like to add %s to %s?", hostname, hostsFile), false) {
+		a.WriteF("Skipping. Please make sure, that clients can resolve %s.", hostname)
+		a.WriteLn("")
+		a.WriteLn("")
+		return nil
+	}
+
+	if !utils.IsRoot() {
+		a.WriteLn("")
+		return newRootPrivilegesError(a, "Editing "+hostsFile)
+	}
+	if err := checkWritableFilesystem(hostsFile); err != nil {
+		return err
+	}
+
+	hostIP, err := detectHostIP()
+	if err != nil {
+		return fmt.Errorf("could not detect the IP of this host: %w", err)
 	}
 
+	content, err := os.ReadFile(hostsFile)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", hostsFile, err)
+	}
+
+	newContent, changed := utils.SetHostsEntry(string(content), hostname, hostIP.String())
+	if changed {
+		if _, err := utils.WriteFileIfChanged(hostsFile, []byte(newContent), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", hostsFile, err)
+		}
+	}
+
+	a.WriteF("%s now points to %s. To undo this, remove the line marked with '%s' from %s.", hostname, hostIP, utils.HostsEntryMarker, hostsFile)
+	a.WriteLn("")
+	a.WriteLn("")
+
 	return nil
 }
 
-// generateRandomPort generates a random available port > 1024
-func generateRandomPort() int {
+// formatResourceLimit formats a memory or CPU limit,
+// which is divided by unit, or "none" if it is 0
+func formatResourceLimit(value int64, unit float64) string {
+	if value == 0 {
+		return "none"
+	}
+
+	return strconv.FormatFloat(float64(value)/unit, 'f', -1, 64)
+}
+
+// getAfterSetupHookCommand returns the command, which runs a hook:
+// existing files are executed directly, everything else is run
+// by the shell of the platform
+func getAfterSetupHookCommand(goos utils.OSType, hook string, isFile bool) (string, []string) {
+	if isFile {
+		return hook, nil
+	}
+
+	if goos == utils.OSWindows {
+		return "cmd", []string{"/C", hook}
+	}
+	return "sh", []string{"-c", hook}
+}
+
+// getDarwinRemoteLoginError classifies the output and error of
+// "systemsetup -setremotelogin on" and returns an error with specific
+// guidance, or nil if Remote Login has been enabled
+func getDarwinRemoteLoginError(output string, err error) error {
+	const alternatives = "enable it in System Settings > General > Sharing > Remote Login or run 'sudo launchctl load -w /System/Library/LaunchDaemons/ssh.plist'"
+
+	lowerOutput := strings.ToLower(output)
+
+	switch {
+	case strings.Contains(lowerOutput, "full disk access"):
+		return fmt.Errorf("systemsetup requires Full Disk Access for your terminal (System Settings > Privacy & Security > Full Disk Access), or %s", alternatives)
+	case strings.Contains(lowerOutput, "administrator access"), strings.Contains(lowerOutput, "must be run as root"):
+		return fmt.Errorf("systemsetup requires administrator privileges, run autark with sudo, or %s", alternatives)
+	case err != nil:
+		return fmt.Errorf("%w, %s", newInstallCommandError("systemsetup", err, []byte(output)), alternatives)
+	default:
+		return nil
+	}
+}
+
+// getFirewallOpenPortCommands returns the commands, which allow incoming
+// TCP connections to a port in a firewall, and if the rule is persistent,
+// which may differ from persist, if the firewall does not support it
+func getFirewallOpenPortCommands(firewall string, port int, persist bool) ([][]string, bool, error) {
+	portArg := fmt.Sprintf("%d/tcp", port)
+
+	switch firewall {
+	case "firewalld":
+		if persist {
+			// permanent rules are only active after a reload
+			return [][]string{
+				{"firewall-cmd", "--permanent", "--add-port=" + portArg},
+				{"firewall-cmd", "--reload"},
+			}, true, nil
+		}
+		return [][]string{{"firewall-cmd", "--add-port=" + portArg}}, false, nil
+	case "ufw":
+		// rules of ufw are always persistent
+		return [][]string{{"ufw", "allow", portArg}}, true, nil
+	case "iptables":
+		// rules of iptables are always runtime-only
+		return [][]string{{"iptables", "-I", "INPUT", "-p", "tcp", "--dport", strconv.Itoa(port), "-j", "ACCEPT"}}, false, nil
+	default:
+		return nil, false, fmt.Errorf("opening ports is not supported for %s", firewall)
+	}
+}
+
+// getDefaultRegistryHostname returns the primary IP of this host,
+// which is detected by detectHostIP, or "localhost" as fallback
+func getDefaultRegistryHostname(detectHostIP func() (net.IP, error)) string {
+	hostIP, err := detectHostIP()
+	if err != nil || hostIP == nil {
+		return "localhost"
+	}
+
+	return hostIP.String()
+}
+
+// getExpectedRegistryContainerConfig returns the config of a
+// registry container, which is started with specific options
+func getExpectedRegistryContainerConfig(opts *SetupOptions) *registryContainerConfig {
+	config := &registryContainerConfig{
+		Image: registryImage,
+		Port:  opts.RegistryPort,
+		Env:   getRegistryEnv(opts, opts.SelfSigned),
+		IPv6:  opts.IPv6,
+	}
+
+	// validated by validateRegistryResources before
+	config.Memory, _ = parseMemoryLimit(opts.Memory)
+	if opts.CPUs != "" {
+		cpus, _ := strconv.ParseFloat(opts.CPUs, 64)
+		config.NanoCPUs = int64(cpus * 1e9)
+	}
+
+	return config
+}
+
+// getDockerCertPath returns the path, where docker on a specific OS
+// expects the CA certificate of a registry, or an empty string, if
+// the certificate has to be imported into the certificate store
+func getDockerCertPath(goos utils.OSType, homeDir string, registryAddress string) string {
+	switch goos {
+	case utils.OSWindows:
+		return ""
+	case utils.OSDarwin:
+		return filepath.Join(homeDir, ".docker", "certs.d", registryAddress, "ca.crt")
+	default:
+		return filepath.Join("/etc/docker/certs.d", registryAddress, "ca.crt")
+	}
+}
+
+// getRegistryEnv returns the environment variables of the registry
+// container, where tls indicates if the registry is served via TLS
+func getRegistryEnv(opts *SetupOptions, tls bool) map[string]string {
+	env := map[string]string{}
+
+	if tls {
+		env["REGISTRY_HTTP_TLS_CERTIFICATE"] = "/certs/" + registryCertFileName
+		env["REGISTRY_HTTP_TLS_KEY"] = "/certs/" + registryKeyFileName
+	}
+	if opts.RegistryHtpasswdFile != "" {
+		env["REGISTRY_AUTH"] = "htpasswd"
+		env["REGISTRY_AUTH_HTPASSWD_PATH"] = registryHtpasswdPath
+		env["REGISTRY_AUTH_HTPASSWD_REALM"] = "Registry Realm"
+	}
+	if opts.RegistryLogLevel != "" {
+		env["REGISTRY_LOG_LEVEL"] = opts.RegistryLogLevel
+	}
+
+	// the defaults of the flags are the ones of the registry, so the
+	// variables are only set if they change them, which keeps the
+	// configuration of existing containers unchanged
+	if !opts.UploadPurging {
+		env["REGISTRY_STORAGE_MAINTENANCE_UPLOADPURGING_ENABLED"] = "false"
+	} else if opts.UploadPurgingAge != defaultUploadPurgingAge || opts.UploadPurgingInterval != defaultUploadPurgingInterval {
+		// the registry replaces its whole default
+		// settings of upload purging with these
+		env["REGISTRY_STORAGE_MAINTENANCE_UPLOADPURGING_ENABLED"] = "true"
+		env["REGISTRY_STORAGE_MAINTENANCE_UPLOADPURGING_AGE"] = opts.UploadPurgingAge
+		env["REGISTRY_STORAGE_MAINTENANCE_UPLOADPURGING_INTERVAL"] = opts.UploadPurgingInterval
+	}
+
+	// validated by validateRegistryEnv before
+	customEnv, _ := utils.ParseKeyValuePairs(opts.RegistryEnv)
+	maps.Copy(env, customEnv)
+
+	return env
+}
+
+// getRegistryCertsDir returns the directory inside the state directory,
+// which contains the self-signed certificate of the registry and its key
+func getRegistryCertsDir() (string, error) {
+	stateDir, err := utils.StateDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	return filepath.Join(stateDir, "certs"), nil
+}
+
+// getRegistryCertInstructions returns copy-pasteable commands, which
+// make docker on a specific OS trust the certificate of a registry
+func getRegistryCertInstructions(goos utils.OSType, certFile string, registryAddress string) []string {
+	switch goos {
+	case utils.OSWindows:
+		return []string{
+			fmt.Sprintf(`Import-Certificate -FilePath "%s" -CertStoreLocation Cert:\LocalMachine\Root`, certFile),
+			"Restart Docker Desktop",
+		}
+	case utils.OSDarwin:
+		certPath := getDockerCertPath(goos, "~", registryAddress)
+		return []string{
+			fmt.Sprintf("mkdir -p %s", filepath.Dir(certPath)),
+			fmt.Sprintf("cp %s %s", certFile, certPath),
+			"Restart Docker Desktop",
+		}
+	default:
+		certPath := getDockerCertPath(goos, "", registryAddress)
+		return []string{
+			fmt.Sprintf("sudo mkdir -p %s", filepath.Dir(certPath)),
+			fmt.Sprintf("sudo cp %s %s", certFile, certPath),
+		}
+	}
+}
+
+// findRandomAvailablePort returns a random available TCP port
+// above 1024 or false, if none has been found
+func findRandomAvailablePort(isAvailable func(port int) bool) (int, bool) {
 	const minPort = 1025
 	const maxPort = 65535
 	const maxAttempts = 100
 
 	for i := 0; i < maxAttempts; i++ {
 		port := minPort + rand.Intn(maxPort-minPort)
-		if isTCPPortAvailable(port) {
-			return port
+		if isAvailable(port) {
+			return port, true
 		}
 	}
 
+	return 0, false
+}
+
+// generateRandomPort generates a random available port > 1024
+func generateRandomPort() int {
+	if port, ok := findRandomAvailablePort(isTCPPortAvailable); ok {
+		return port
+	}
+
 	// Fallback to a commonly used alternative SSH port
 	return 2222
 }
@@ -313,24 +1043,65 @@ func initSetupCommand(a *app.AppContext) {
 	rootCmd := a.RootCommand()
 
 	opts := &SetupOptions{}
+	remoteOpts := &RemoteOptions{}
 
 	setupCmd := &cobra.Command{
 		Use:     "setup",
 		Aliases: []string{"s"},
 		Short:   "Setup local Docker registry",
-		Long:    `Sets up a local Docker registry as a background service. If not already running, it will be installed and configured to start automatically on system boot.`,
+		Long:    `Sets up a local Docker registry as a background service. If not already running, it will be installed and configured to start automatically on system boot. With --remote, autark is copied to a remote host and the setup runs there via SSH.`,
+		Example: `  autark setup
+  autark setup --registry-port 5001 --no-ssh
+  sudo autark setup --self-signed --registry-hostname registry.example.lan --trust-cert
+  autark setup --remote root@192.168.1.20 --remote-key ~/.ssh/id_ed25519`,
 		Run: func(cmd *cobra.Command, args []string) {
-			runSetup(a, opts)
+			if remoteOpts.Target != "" {
+				exitOnError(a, runRemoteSetup(a, remoteOpts, cmd.Flags()))
+				return
+			}
+
+			exitOnError(a, runWithLock(a, func() error {
+				return runSetup(a, opts)
+			}))
 		},
 	}
 
-	setupCmd.Flags().IntVarP(&opts.RegistryPort, "registry-port", "", 5000, "Port for the local Docker registry")
-	setupCmd.Flags().BoolVarP(&opts.NoFirewall, "no-firewall", "", false, "Skip firewall check and installation")
-	setupCmd.Flags().BoolVarP(&opts.NoSSH, "no-ssh", "", false, "Skip SSH server check and installation")
+	flags := setupCmd.Flags()
+	initSetupFlags(flags, opts)
+	initRemoteFlags(flags, remoteOpts)
 
 	rootCmd.AddCommand(setupCmd)
 }
 
+// initSetupFlags registers the flags for SetupOptions, which are
+// shared by all commands running the setup
+func initSetupFlags(flags *pflag.FlagSet, opts *SetupOptions) {
+	flags.IntVarP(&opts.RegistryPort, "registry-port", "", 5000, "Port for the local Docker registry (0 selects a free port)")
+	flags.StringVarP(&opts.RegistryName, "registry-name", "", registryContainerName, "Name of the registry container")
+	flags.StringVarP(&opts.RegistryLogLevel, "registry-log-level", "", "info", fmt.Sprintf("Log level of the registry (%s)", strings.Join(registryLogLevels, ", ")))
+	flags.StringVarP(&opts.Pull, "pull", "", registryPullMissing, fmt.Sprintf("Pull policy of the registry image (%s)", strings.Join(registryPullPolicies, ", ")))
+	flags.StringArrayVarP(&opts.RegistryEnv, "registry-env", "", nil, "Environment variable of the registry container, like REGISTRY_STORAGE_DELETE_ENABLED=true (repeatable)")
+	flags.BoolVarP(&opts.UploadPurging, "upload-purging", "", true, "Let the registry delete orphaned uploads of interrupted pushes regularly")
+	flags.StringVarP(&opts.UploadPurgingAge, "upload-purging-age", "", defaultUploadPurgingAge, "Minimum age of the orphaned uploads, which are deleted, like 72h")
+	flags.StringVarP(&opts.UploadPurgingInterval, "upload-purging-interval", "", defaultUploadPurgingInterval, "Interval, in which orphaned uploads are deleted, like 12h")
+	flags.StringVarP(&opts.RegistryHtpasswdFile, "registry-htpasswd-file", "", "", "Existing htpasswd file with bcrypt hashes, which is mounted into the registry for basic authentication")
+	flags.StringVarP(&opts.RegistryHostname, "registry-hostname", "", "", "Externally reachable hostname of the registry (default: primary IP of this host)")
+	flags.StringVarP(&opts.RegistryInterface, "registry-interface", "", "", "Network interface, whose IPv4 address is used instead of the detected primary IP, like eth0")
+	flags.StringVarP(&opts.AfterSetupHook, "after-setup-hook", "", "", "Script or command, which is run after a successful setup with the values of the registry as AUTARK_REGISTRY_* environment variables")
+	flags.BoolVarP(&opts.NoFirewall, "no-firewall", "", false, "Skip firewall check and installation")
+	flags.BoolVarP(&opts.Launchd, "launchd", "", false, "Keep the registry running with a launchd agent instead of the restart policy of docker (macOS only)")
+	flags.BoolVarP(&opts.OpenFirewall, "open-firewall", "", false, "Allow incoming connections to the port of the registry in the detected firewall (requires root)")
+	flags.BoolVarP(&opts.IPv6, "ipv6", "", false, "Publish the port of the registry explicitly on all IPv4 and all IPv6 addresses")
+	flags.BoolVarP(&opts.ConfigureInsecure, "configure-insecure", "", false, "Add the registry to \"insecure-registries\" in /etc/docker/daemon.json and restart docker (Linux only, requires root)")
+	flags.BoolVarP(&opts.PersistFirewall, "persist-firewall", "", true, "Add the rule of --open-firewall permanently, which also reloads firewalld, instead of runtime-only")
+	flags.BoolVarP(&opts.NoSSH, "no-ssh", "", false, "Skip SSH server check and installation")
+	flags.BoolVarP(&opts.Open, "open", "", false, "Open the catalog page of the registry in the browser")
+	flags.BoolVarP(&opts.SelfSigned, "self-signed", "", false, "Serve the registry via TLS with a generated self-signed certificate")
+	flags.BoolVarP(&opts.TrustCert, "trust-cert", "", false, "Trust the self-signed certificate in docker on this host (requires --self-signed and root)")
+	flags.StringVarP(&opts.Memory, "memory", "", "", "Memory limit of the registry container, like 512m or 2g")
+	flags.StringVarP(&opts.CPUs, "cpus", "", "", "Number of CPUs the registry container may use, like 0.5 or 2")
+}
+
 func installFirewall(a *app.AppContext) error {
 	platform := a.Platform()
 
@@ -351,7 +1122,7 @@ func installFirewall(a *app.AppContext) error {
 func installFirewallArch(a *app.AppContext) error {
 	a.D("Installing ufw on Arch Linux...")
 
-	if err := runInstallCommandDirect("pacman", "-Sy", "--noconfirm", "ufw"); err != nil {
+	if err := runInstallCommandDirect(a, "pacman", "-Sy", "--noconfirm", "ufw"); err != nil {
 		return fmt.Errorf("failed to install ufw: %w", err)
 	}
 
@@ -361,7 +1132,7 @@ func installFirewallArch(a *app.AppContext) error {
 func installFirewallAlpine(a *app.AppContext) error {
 	a.D("Installing iptables on Alpine Linux...")
 
-	if err := runInstallCommandDirect("apk", "add", "iptables"); err != nil {
+	if err := runInstallCommandDirect(a, "apk", "add", "iptables"); err != nil {
 		return fmt.Errorf("failed to install iptables: %w", err)
 	}
 
@@ -373,15 +1144,15 @@ func installFirewallByPackageManager(a *app.AppContext) error {
 
 	switch platform.PackageManager {
 	case utils.PkgMgrApt:
-		return runInstallCommandDirect("apt-get", "install", "-y", "-qq", "ufw")
+		return runInstallCommandDirect(a, "apt-get", "install", "-y", "-qq", "ufw")
 	case utils.PkgMgrDnf:
-		return runInstallCommandDirect("dnf", "install", "-y", "-q", "firewalld")
+		return runInstallCommandDirect(a, "dnf", "install", "-y", "-q", "firewalld")
 	case utils.PkgMgrPacman:
-		return runInstallCommandDirect("pacman", "-Sy", "--noconfirm", "ufw")
+		return runInstallCommandDirect(a, "pacman", "-Sy", "--noconfirm", "ufw")
 	case utils.PkgMgrApk:
-		return runInstallCommandDirect("apk", "add", "iptables")
+		return runInstallCommandDirect(a, "apk", "add", "iptables")
 	case utils.PkgMgrZypper:
-		return runInstallCommandDirect("zypper", "install", "-y", "firewalld")
+		return runInstallCommandDirect(a, "zypper", "install", "-y", "firewalld")
 	default:
 		return fmt.Errorf("firewall installation not supported for package manager: %s", platform.PackageManager)
 	}
@@ -390,11 +1161,11 @@ func installFirewallByPackageManager(a *app.AppContext) error {
 func installFirewallDebian(a *app.AppContext) error {
 	a.D("Installing ufw on Debian/Ubuntu...")
 
-	if err := runInstallCommandDirect("apt-get", "update", "-qq"); err != nil {
+	if err := runInstallCommandDirect(a, "apt-get", "update", "-qq"); err != nil {
 		return fmt.Errorf("failed to update package list: %w", err)
 	}
 
-	if err := runInstallCommandDirect("apt-get", "install", "-y", "-qq", "ufw"); err != nil {
+	if err := runInstallCommandDirect(a, "apt-get", "install", "-y", "-qq", "ufw"); err != nil {
 		return fmt.Errorf("failed to install ufw: %w", err)
 	}
 
@@ -404,11 +1175,11 @@ func installFirewallDebian(a *app.AppContext) error {
 func installFirewallFedora(a *app.AppContext) error {
 	a.D("Installing firewalld on Fedora/RHEL...")
 
-	if err := runInstallCommandDirect("dnf", "install", "-y", "-q", "firewalld"); err != nil {
+	if err := runInstallCommandDirect(a, "dnf", "install", "-y", "-q", "firewalld"); err != nil {
 		return fmt.Errorf("failed to install firewalld: %w", err)
 	}
 
-	if err := runInstallCommandDirect("systemctl", "enable", "--now", "firewalld"); err != nil {
+	if err := runInstallCommandDirect(a, "systemctl", "enable", "--now", "firewalld"); err != nil {
 		return fmt.Errorf("failed to enable firewalld: %w", err)
 	}
 
@@ -416,34 +1187,19 @@ func installFirewallFedora(a *app.AppContext) error {
 }
 
 func installFirewallLinux(a *app.AppContext) error {
-	platform := a.Platform()
-
 	a.WriteLn("Installing firewall...")
 
-	switch platform.LinuxDistro {
-	case utils.DistroDebian, utils.DistroUbuntu:
-		return installFirewallDebian(a)
-	case utils.DistroFedora, utils.DistroRHEL, utils.DistroCentOS:
-		return installFirewallFedora(a)
-	case utils.DistroArch:
-		return installFirewallArch(a)
-	case utils.DistroAlpine:
-		return installFirewallAlpine(a)
-	case utils.DistroOpenSUSE:
-		return installFirewallOpenSUSE(a)
-	case utils.DistroGentoo:
-		return installFirewallGentoo(a)
-	case utils.DistroVoid:
-		return installFirewallVoid(a)
-	default:
-		return installFirewallByPackageManager(a)
+	if install, ok := linuxFirewallInstallers[a.Platform().LinuxDistro]; ok {
+		return install(a)
 	}
+
+	return installFirewallByPackageManager(a)
 }
 
 func installFirewallGentoo(a *app.AppContext) error {
 	a.D("Installing iptables on Gentoo...")
 
-	if err := runInstallCommandDirect("emerge", "--quiet", "net-firewall/iptables"); err != nil {
+	if err := runInstallCommandDirect(a, "emerge", "--quiet", "net-firewall/iptables"); err != nil {
 		return fmt.Errorf("failed to install iptables: %w", err)
 	}
 
@@ -453,11 +1209,11 @@ func installFirewallGentoo(a *app.AppContext) error {
 func installFirewallOpenSUSE(a *app.AppContext) error {
 	a.D("Installing firewalld on openSUSE...")
 
-	if err := runInstallCommandDirect("zypper", "install", "-y", "firewalld"); err != nil {
+	if err := runInstallCommandDirect(a, "zypper", "install", "-y", "firewalld"); err != nil {
 		return fmt.Errorf("failed to install firewalld: %w", err)
 	}
 
-	if err := runInstallCommandDirect("systemctl", "enable", "--now", "firewalld"); err != nil {
+	if err := runInstallCommandDirect(a, "systemctl", "enable", "--now", "firewalld"); err != nil {
 		return fmt.Errorf("failed to enable firewalld: %w", err)
 	}
 
@@ -467,7 +1223,7 @@ func installFirewallOpenSUSE(a *app.AppContext) error {
 func installFirewallVoid(a *app.AppContext) error {
 	a.D("Installing iptables on Void Linux...")
 
-	if err := runInstallCommandDirect("xbps-install", "-y", "iptables"); err != nil {
+	if err := runInstallCommandDirect(a, "xbps-install", "-y", "iptables"); err != nil {
 		return fmt.Errorf("failed to install iptables: %w", err)
 	}
 
@@ -490,58 +1246,79 @@ func installSSH(a *app.AppContext, port int) error {
 }
 
 func installSSHLinux(a *app.AppContext, port int) error {
-	platform := a.Platform()
-
 	a.WriteLn("Installing OpenSSH server...")
 
-	switch platform.LinuxDistro {
-	case utils.DistroDebian, utils.DistroUbuntu:
-		return installSSHDebian(a, port)
-	case utils.DistroFedora, utils.DistroRHEL, utils.DistroCentOS:
-		return installSSHFedora(a, port)
-	case utils.DistroArch:
-		return installSSHArch(a, port)
-	case utils.DistroAlpine:
-		return installSSHAlpine(a, port)
-	case utils.DistroOpenSUSE:
-		return installSSHOpenSUSE(a, port)
-	case utils.DistroGentoo:
-		return installSSHGentoo(a, port)
-	case utils.DistroVoid:
-		return installSSHVoid(a, port)
-	default:
-		return installSSHByPackageManager(a, port)
+	if install, ok := linuxSSHInstallers[a.Platform().LinuxDistro]; ok {
+		return install(a, port)
 	}
+
+	return installSSHByPackageManager(a, port)
 }
 
-func installRegistry(a *app.AppContext, port int) error {
+func installRegistry(a *app.AppContext, opts *SetupOptions, certsDir string) error {
 	a.WriteLn("Installing Docker registry...")
 
+	// before the existing container is removed, because
+	// this fails with --pull never, if the image is missing
+	if err := ensureRegistryImage(a, &dockerRuntime{}, opts.Pull); err != nil {
+		return err
+	}
+
 	// First, remove any existing container with the same name (stopped or otherwise)
-	_ = exec.Command("docker", "rm", "-f", registryContainerName).Run()
+	removeRegistryContainer(a, opts.RegistryName)
+
+	// the unit of "registry install-service" starts the new container
+	if _, err := os.Stat(getSystemdUnitPath(opts.RegistryName)); err == nil {
+		a.D("The registry is kept running by the systemd unit %s", getSystemdUnitPath(opts.RegistryName))
+		opts.SystemdUnit = true
+	}
 
 	// Run the registry container with restart policy
-	cmd := exec.Command("docker", "run",
-		"-d",
-		"--name", registryContainerName,
-		"--restart=always",
-		"-p", fmt.Sprintf("%d:5000", port),
-		registryImage,
-	)
-	cmd.Stdout = a.Stdout()
-	cmd.Stderr = a.Stderr()
+	args, err := buildRegistryRunArgs(opts, certsDir)
+	if err != nil {
+		return err
+	}
 
-	if err := cmd.Run(); err != nil {
+	stderr, err := runRegistryContainer(a, args)
+	if err != nil && isContainerNameConflict(stderr) {
+		// the removal has raced with another one or
+		// the container has been paused
+		a.W("The name %s is still in use by another container. Removing it again...", opts.RegistryName)
+		removeRegistryContainer(a, opts.RegistryName)
+
+		stderr, err = runRegistryContainer(a, args)
+		if err != nil && isContainerNameConflict(stderr) {
+			return fmt.Errorf("failed to start registry container, because the name %s is still in use. Please remove that container with 'docker rm -f %s' or use --registry-name", opts.RegistryName, opts.RegistryName)
+		}
+	}
+	if err != nil {
 		return fmt.Errorf("failed to start registry container: %w", err)
 	}
 
 	return nil
 }
 
+// isContainerNameConflict checks if the standard error output of
+// "docker run" reports, that the name is used by another container
+func isContainerNameConflict(stderr string) bool {
+	return strings.Contains(stderr, "is already in use by container")
+}
+
+// isLoopbackHost checks if a hostname or IP is a loopback
+// address, which docker uses via HTTP without configuration
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
 func installSSHAlpine(a *app.AppContext, port int) error {
 	a.D("Installing OpenSSH server on Alpine Linux...")
 
-	if err := runInstallCommandDirect("apk", "add", "openssh"); err != nil {
+	if err := runInstallCommandDirect(a, "apk", "add", "openssh"); err != nil {
 		return fmt.Errorf("failed to install openssh: %w", err)
 	}
 
@@ -549,11 +1326,11 @@ func installSSHAlpine(a *app.AppContext, port int) error {
 		a.W("Failed to configure SSH port: %s", err.Error())
 	}
 
-	if err := runInstallCommandDirect("rc-update", "add", "sshd"); err != nil {
+	if err := runInstallCommandDirect(a, "rc-update", "add", "sshd"); err != nil {
 		return fmt.Errorf("failed to enable sshd service: %w", err)
 	}
 
-	if err := runInstallCommandDirect("service", "sshd", "start"); err != nil {
+	if err := runInstallCommandDirect(a, "service", "sshd", "start"); err != nil {
 		return fmt.Errorf("failed to start sshd service: %w", err)
 	}
 
@@ -563,7 +1340,7 @@ func installSSHAlpine(a *app.AppContext, port int) error {
 func installSSHArch(a *app.AppContext, port int) error {
 	a.D("Installing OpenSSH server on Arch Linux...")
 
-	if err := runInstallCommandDirect("pacman", "-Sy", "--noconfirm", "openssh"); err != nil {
+	if err := runInstallCommandDirect(a, "pacman", "-Sy", "--noconfirm", "openssh"); err != nil {
 		return fmt.Errorf("failed to install openssh: %w", err)
 	}
 
@@ -571,7 +1348,7 @@ func installSSHArch(a *app.AppContext, port int) error {
 		a.W("Failed to configure SSH port: %s", err.Error())
 	}
 
-	if err := runInstallCommandDirect("systemctl", "enable", "--now", "sshd"); err != nil {
+	if err := runInstallCommandDirect(a, "systemctl", "enable", "--now", "sshd"); err != nil {
 		return fmt.Errorf("failed to enable sshd service: %w", err)
 	}
 
@@ -583,37 +1360,37 @@ func installSSHByPackageManager(a *app.AppContext, port int) error {
 
 	switch platform.PackageManager {
 	case utils.PkgMgrApt:
-		if err := runInstallCommandDirect("apt-get", "install", "-y", "-qq", "openssh-server"); err != nil {
+		if err := runInstallCommandDirect(a, "apt-get", "install", "-y", "-qq", "openssh-server"); err != nil {
 			return err
 		}
 		if err := configureSSHPort(port); err != nil {
 			a.W("Failed to configure SSH port: %s", err.Error())
 		}
-		return runInstallCommandDirect("systemctl", "enable", "--now", "ssh")
+		return runInstallCommandDirect(a, "systemctl", "enable", "--now", "ssh")
 	case utils.PkgMgrDnf:
-		if err := runInstallCommandDirect("dnf", "install", "-y", "-q", "openssh-server"); err != nil {
+		if err := runInstallCommandDirect(a, "dnf", "install", "-y", "-q", "openssh-server"); err != nil {
 			return err
 		}
 		if err := configureSSHPort(port); err != nil {
 			a.W("Failed to configure SSH port: %s", err.Error())
 		}
-		return runInstallCommandDirect("systemctl", "enable", "--now", "sshd")
+		return runInstallCommandDirect(a, "systemctl", "enable", "--now", "sshd")
 	case utils.PkgMgrPacman:
-		if err := runInstallCommandDirect("pacman", "-Sy", "--noconfirm", "openssh"); err != nil {
+		if err := runInstallCommandDirect(a, "pacman", "-Sy", "--noconfirm", "openssh"); err != nil {
 			return err
 		}
 		if err := configureSSHPort(port); err != nil {
 			a.W("Failed to configure SSH port: %s", err.Error())
 		}
-		return runInstallCommandDirect("systemctl", "enable", "--now", "sshd")
+		return runInstallCommandDirect(a, "systemctl", "enable", "--now", "sshd")
 	case utils.PkgMgrApk:
-		if err := runInstallCommandDirect("apk", "add", "openssh"); err != nil {
+		if err := runInstallCommandDirect(a, "apk", "add", "openssh"); err != nil {
 			return err
 		}
 		if err := configureSSHPort(port); err != nil {
 			a.W("Failed to configure SSH port: %s", err.Error())
 		}
-		return runInstallCommandDirect("rc-update", "add", "sshd")
+		return runInstallCommandDirect(a, "rc-update", "add", "sshd")
 	default:
 		return fmt.Errorf("SSH installation not supported for package manager: %s", platform.PackageManager)
 	}
@@ -622,8 +1399,10 @@ func installSSHByPackageManager(a *app.AppContext, port int) error {
 func installSSHDarwin(a *app.AppContext, port int) error {
 	a.WriteLn("Enabling Remote Login (SSH) on macOS...")
 
-	// Enable Remote Login via systemsetup (requires admin privileges)
-	if err := runInstallCommandDirect("systemsetup", "-setremotelogin", "on"); err != nil {
+	// Enable Remote Login via systemsetup (requires admin privileges),
+	// which exits with 0 on some errors, so its output is checked
+	output, err := runInstallCommandCaptured(a, "systemsetup", "-setremotelogin", "on")
+	if err := getDarwinRemoteLoginError(string(output), err); err != nil {
 		return fmt.Errorf("failed to enable Remote Login: %w", err)
 	}
 
@@ -637,11 +1416,11 @@ func installSSHDarwin(a *app.AppContext, port int) error {
 func installSSHDebian(a *app.AppContext, port int) error {
 	a.D("Installing OpenSSH server on Debian/Ubuntu...")
 
-	if err := runInstallCommandDirect("apt-get", "update", "-qq"); err != nil {
+	if err := runInstallCommandDirect(a, "apt-get", "update", "-qq"); err != nil {
 		return fmt.Errorf("failed to update package list: %w", err)
 	}
 
-	if err := runInstallCommandDirect("apt-get", "install", "-y", "-qq", "openssh-server"); err != nil {
+	if err := runInstallCommandDirect(a, "apt-get", "install", "-y", "-qq", "openssh-server"); err != nil {
 		return fmt.Errorf("failed to install openssh-server: %w", err)
 	}
 
@@ -649,7 +1428,7 @@ func installSSHDebian(a *app.AppContext, port int) error {
 		a.W("Failed to configure SSH port: %s", err.Error())
 	}
 
-	if err := runInstallCommandDirect("systemctl", "enable", "--now", "ssh"); err != nil {
+	if err := runInstallCommandDirect(a, "systemctl", "enable", "--now", "ssh"); err != nil {
 		return fmt.Errorf("failed to enable ssh service: %w", err)
 	}
 
@@ -659,7 +1438,7 @@ func installSSHDebian(a *app.AppContext, port int) error {
 func installSSHFedora(a *app.AppContext, port int) error {
 	a.D("Installing OpenSSH server on Fedora/RHEL...")
 
-	if err := runInstallCommandDirect("dnf", "install", "-y", "-q", "openssh-server"); err != nil {
+	if err := runInstallCommandDirect(a, "dnf", "install", "-y", "-q", "openssh-server"); err != nil {
 		return fmt.Errorf("failed to install openssh-server: %w", err)
 	}
 
@@ -667,7 +1446,7 @@ func installSSHFedora(a *app.AppContext, port int) error {
 		a.W("Failed to configure SSH port: %s", err.Error())
 	}
 
-	if err := runInstallCommandDirect("systemctl", "enable", "--now", "sshd"); err != nil {
+	if err := runInstallCommandDirect(a, "systemctl", "enable", "--now", "sshd"); err != nil {
 		return fmt.Errorf("failed to enable sshd service: %w", err)
 	}
 
@@ -677,7 +1456,7 @@ func installSSHFedora(a *app.AppContext, port int) error {
 func installSSHGentoo(a *app.AppContext, port int) error {
 	a.D("Installing OpenSSH server on Gentoo...")
 
-	if err := runInstallCommandDirect("emerge", "--quiet", "net-misc/openssh"); err != nil {
+	if err := runInstallCommandDirect(a, "emerge", "--quiet", "net-misc/openssh"); err != nil {
 		return fmt.Errorf("failed to install openssh: %w", err)
 	}
 
@@ -685,11 +1464,11 @@ func installSSHGentoo(a *app.AppContext, port int) error {
 		a.W("Failed to configure SSH port: %s", err.Error())
 	}
 
-	if err := runInstallCommandDirect("rc-update", "add", "sshd", "default"); err != nil {
+	if err := runInstallCommandDirect(a, "rc-update", "add", "sshd", "default"); err != nil {
 		return fmt.Errorf("failed to enable sshd service: %w", err)
 	}
 
-	if err := runInstallCommandDirect("service", "sshd", "start"); err != nil {
+	if err := runInstallCommandDirect(a, "service", "sshd", "start"); err != nil {
 		return fmt.Errorf("failed to start sshd service: %w", err)
 	}
 
@@ -699,7 +1478,7 @@ func installSSHGentoo(a *app.AppContext, port int) error {
 func installSSHOpenSUSE(a *app.AppContext, port int) error {
 	a.D("Installing OpenSSH server on openSUSE...")
 
-	if err := runInstallCommandDirect("zypper", "install", "-y", "openssh"); err != nil {
+	if err := runInstallCommandDirect(a, "zypper", "install", "-y", "openssh"); err != nil {
 		return fmt.Errorf("failed to install openssh: %w", err)
 	}
 
@@ -707,7 +1486,7 @@ func installSSHOpenSUSE(a *app.AppContext, port int) error {
 		a.W("Failed to configure SSH port: %s", err.Error())
 	}
 
-	if err := runInstallCommandDirect("systemctl", "enable", "--now", "sshd"); err != nil {
+	if err := runInstallCommandDirect(a, "systemctl", "enable", "--now", "sshd"); err != nil {
 		return fmt.Errorf("failed to enable sshd service: %w", err)
 	}
 
@@ -717,7 +1496,7 @@ func installSSHOpenSUSE(a *app.AppContext, port int) error {
 func installSSHVoid(a *app.AppContext, port int) error {
 	a.D("Installing OpenSSH server on Void Linux...")
 
-	if err := runInstallCommandDirect("xbps-install", "-y", "openssh"); err != nil {
+	if err := runInstallCommandDirect(a, "xbps-install", "-y", "openssh"); err != nil {
 		return fmt.Errorf("failed to install openssh: %w", err)
 	}
 
@@ -725,7 +1504,7 @@ func installSSHVoid(a *app.AppContext, port int) error {
 		a.W("Failed to configure SSH port: %s", err.Error())
 	}
 
-	if err := runInstallCommandDirect("ln", "-s", "/etc/sv/sshd", "/var/service/"); err != nil {
+	if err := runInstallCommandDirect(a, "ln", "-s", "/etc/sv/sshd", "/var/service/"); err != nil {
 		// Link might already exist, just warn
 		a.W("Failed to enable sshd service: %s", err.Error())
 	}
@@ -772,6 +1551,17 @@ func installSSHWindows(a *app.AppContext, port int) error {
 	return nil
 }
 
+// inspectRegistryContainer returns the config
+// of an existing registry container
+func inspectRegistryContainer(name string) (*registryContainerConfig, error) {
+	output, err := utils.RunCommand("docker", "inspect", "--type", "container", name)
+	if err != nil {
+		return nil, newDockerError("inspect", err, output)
+	}
+
+	return parseRegistryContainerConfig(output)
+}
+
 // isPortAvailable checks if a TCP port is available (not in use)
 func isTCPPortAvailable(port int) bool {
 	addr := fmt.Sprintf(":%d", port)
@@ -783,7 +1573,397 @@ func isTCPPortAvailable(port int) bool {
 	return true
 }
 
-func runSetup(a *app.AppContext, opts *SetupOptions) {
+// isCertificateValidFor checks if a PEM encoded certificate
+// is valid for a specific hostname or IP
+func isCertificateValidFor(certPEM []byte, hostname string) bool {
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return false
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return false
+	}
+
+	return cert.VerifyHostname(hostname) == nil
+}
+
+// mergeInsecureRegistry adds a registry address to the
+// "insecure-registries" of the content of a daemon.json file and
+// returns the new content and if it has been changed
+//
+// All other keys and their values are kept
+func mergeInsecureRegistry(data []byte, registryAddress string) ([]byte, bool, error) {
+	config := map[string]json.RawMessage{}
+	if len(bytes.TrimSpace(data)) > 0 {
+		if err := json.Unmarshal(data, &config); err != nil {
+			return nil, false, err
+		}
+	}
+
+	insecureRegistries := make([]string, 0)
+	if raw, ok := config["insecure-registries"]; ok {
+		if err := json.Unmarshal(raw, &insecureRegistries); err != nil {
+			return nil, false, fmt.Errorf("insecure-registries: %w", err)
+		}
+	}
+
+	if isInsecureRegistryListed(registryAddress, insecureRegistries) {
+		return data, false, nil
+	}
+
+	raw, err := json.Marshal(append(insecureRegistries, registryAddress))
+	if err != nil {
+		return nil, false, err
+	}
+	config["insecure-registries"] = raw
+
+	newData, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return nil, false, err
+	}
+
+	return append(newData, '\n'), true, nil
+}
+
+// openFirewallPort allows incoming TCP connections to the port of
+// the registry in the detected firewall
+func openFirewallPort(a *app.AppContext, port int, persist bool) error {
+	firewallInfo := checkFirewall()
+	if !firewallInfo.Installed {
+		a.W("No firewall detected, port %d does not need to be opened.", port)
+		return nil
+	}
+
+	commands, persistent, err := getFirewallOpenPortCommands(firewallInfo.Name, port, persist)
+	if err != nil {
+		a.W("%s. Please allow incoming connections to port %d/tcp manually.", err.Error(), port)
+		return nil
+	}
+
+	if firewallInfo.Name == "iptables" {
+		// "-I" would add the rule again on every run
+		checkArgs := append([]string{"-C"}, commands[0][2:]...)
+		if utils.RunCommandSilent("iptables", checkArgs...) == nil {
+			a.WriteF("Port %d/tcp is already open in iptables.", port)
+			a.WriteLn("")
+			return nil
+		}
+	}
+
+	a.WriteF("Opening port %d/tcp in %s...", port, firewallInfo.Name)
+	a.WriteLn("")
+
+	for _, cmd := range commands {
+		if err := runInstallCommandDirect(a, cmd[0], cmd[1:]...); err != nil {
+			return fmt.Errorf("failed to run %s: %w", strings.Join(cmd, " "), err)
+		}
+	}
+
+	switch {
+	case persist && !persistent:
+		a.W("The rule of %s is not persistent and gets lost on the next reboot.", firewallInfo.Name)
+	case !persist && persistent:
+		a.W("The rule of %s is persistent, because %s does not support runtime-only rules.", firewallInfo.Name, firewallInfo.Name)
+	}
+
+	return nil
+}
+
+func openRegistryCatalog(a *app.AppContext, opts *SetupOptions) {
+	scheme := "http"
+	if opts.SelfSigned {
+		scheme = "https"
+	}
+
+	catalogURL := fmt.Sprintf("%s://localhost:%d/v2/_catalog", scheme, opts.RegistryPort)
+
+	a.WriteF("Opening %s ...", catalogURL)
+	a.WriteLn("")
+
+	if err := utils.OpenURL(catalogURL); err != nil {
+		a.W("Failed to open %s: %s", catalogURL, err.Error())
+	}
+}
+
+// parseMemoryLimit parses a memory limit, like "512m" or "2g", into
+// bytes as "docker run --memory" does, where the units are powers
+// of 1024, or returns 0 for an empty string
+func parseMemoryLimit(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	if !registryMemoryRegex.MatchString(value) {
+		return 0, fmt.Errorf("invalid memory limit %q", value)
+	}
+
+	multiplier := 1.0
+	switch unit := strings.ToLower(value[len(value)-1:]); unit {
+	case "k":
+		multiplier = 1 << 10
+	case "m":
+		multiplier = 1 << 20
+	case "g":
+		multiplier = 1 << 30
+	}
+	number := strings.TrimRight(strings.ToLower(value), "bkmg")
+
+	bytes, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid memory limit %q: %w", value, err)
+	}
+
+	return int64(bytes * multiplier), nil
+}
+
+// parseRegistryContainerConfig parses the output of "docker inspect"
+// for a registry container
+func parseRegistryContainerConfig(data []byte) (*registryContainerConfig, error) {
+	var containers []struct {
+		Config struct {
+			Image string
+			Env   []string
+		}
+		HostConfig struct {
+			PortBindings map[string][]struct {
+				HostIp   string
+				HostPort string
+			}
+			Memory   int64
+			NanoCpus int64
+		}
+	}
+	if err := json.Unmarshal(data, &containers); err != nil {
+		return nil, fmt.Errorf("invalid output of docker inspect: %w", err)
+	}
+	if len(containers) == 0 {
+		return nil, fmt.Errorf("container not found")
+	}
+
+	container := containers[0]
+
+	config := &registryContainerConfig{
+		Image:    container.Config.Image,
+		Env:      map[string]string{},
+		Memory:   container.HostConfig.Memory,
+		NanoCPUs: container.HostConfig.NanoCpus,
+	}
+
+	bindings := container.HostConfig.PortBindings[fmt.Sprintf("%d/tcp", registryContainerPort)]
+	if len(bindings) > 0 {
+		config.Port, _ = strconv.Atoi(bindings[0].HostPort)
+	}
+	for _, binding := range bindings {
+		if ip := net.ParseIP(binding.HostIp); ip != nil && ip.To4() == nil {
+			config.IPv6 = true
+		}
+	}
+
+	for _, entry := range container.Config.Env {
+		if key, value, ok := strings.Cut(entry, "="); ok {
+			config.Env[key] = value
+		}
+	}
+
+	return config, nil
+}
+
+// restartDockerDaemonLinux restarts the docker daemon, so it reads
+// its config file again, and waits until it is running
+//
+// Containers with a restart policy, like the registry, are started
+// by the daemon again
+func restartDockerDaemonLinux(a *app.AppContext) error {
+	a.WriteLn("Restarting docker daemon...")
+
+	var err error
+	switch {
+	case a.Platform().Capabilities().HasSystemd:
+		err = runInstallCommandDirect(a, "systemctl", "restart", "docker")
+	case utils.CommandExists("rc-service"):
+		err = runInstallCommandDirect(a, "rc-service", "docker", "restart")
+	case utils.CommandExists("service"):
+		err = runInstallCommandDirect(a, "service", "docker", "restart")
+	default:
+		return fmt.Errorf("no service manager found, please restart docker manually")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to restart docker daemon: %w", err)
+	}
+
+	if err := waitForDockerDaemon(a, dockerDaemonRestartTimeout, dockerDesktopStartInterval, isDockerDaemonRunning); err != nil {
+		a.WriteLn("")
+		return fmt.Errorf("docker daemon is not running after restart: %w", err)
+	}
+
+	a.WriteLn("Docker daemon restarted successfully.")
+	return nil
+}
+
+// redactEnvValue masks the value of an environment variable,
+// if its name denotes a secret, like REGISTRY_HTTP_SECRET, or
+// secrets inside of it, like passwords of URLs
+func redactEnvValue(key string, value string) string {
+	if utils.IsSecretName(key) {
+		return utils.RedactedValue
+	}
+
+	return utils.RedactValue(value)
+}
+
+// removeRegistryContainer removes the container of the registry,
+// even if it is running or paused
+func removeRegistryContainer(a *app.AppContext, name string) {
+	// older versions of docker cannot remove paused containers
+	if err := utils.RunCommandSilent("docker", "unpause", name); err == nil {
+		a.D("Unpaused container %s", name)
+	}
+
+	if output, err := utils.RunCommandSilentCapture("docker", "rm", "-f", name); err != nil {
+		a.D("Could not remove container %s: %s", name, output)
+	}
+}
+
+// resolveRegistryHtpasswdFile validates the file of
+// --registry-htpasswd-file and returns its absolute path,
+// which can be mounted into the registry container
+func resolveRegistryHtpasswdFile(a *app.AppContext, path string) (string, error) {
+	path, err := utils.ExpandPath(path)
+	if err != nil {
+		return "", fmt.Errorf("invalid --registry-htpasswd-file: %w", err)
+	}
+
+	path, err = filepath.Abs(a.ResolvePath(path))
+	if err != nil {
+		return "", fmt.Errorf("invalid --registry-htpasswd-file: %w", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("invalid --registry-htpasswd-file: %w", err)
+	}
+
+	if err := validateHtpasswd(data); err != nil {
+		return "", fmt.Errorf("invalid --registry-htpasswd-file %s: %w", path, err)
+	}
+
+	return path, nil
+}
+
+// runAfterSetupHook runs the hook of --after-setup-hook with the
+// values of the registry as environment variables and streams its output
+func runAfterSetupHook(a *app.AppContext, opts *SetupOptions, hostname string, port int, certsDir string) error {
+	hook := opts.AfterSetupHook
+
+	isFile := false
+	if path, err := utils.ExpandPath(hook); err == nil {
+		path = a.ResolvePath(path)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			hook = path
+			isFile = true
+		}
+	}
+
+	name, args := getAfterSetupHookCommand(a.Platform().OS, hook, isFile)
+
+	a.WriteLn("")
+	a.WriteF("Running after-setup hook %s...", opts.AfterSetupHook)
+	a.WriteLn("")
+	a.D("Running: %s", utils.RedactCommandLine(name, args...))
+
+	cmd := exec.Command(name, args...)
+	cmd.Dir = a.Config().WorkDir
+	cmd.Env = append(os.Environ(), buildAfterSetupHookEnv(opts, hostname, port, certsDir)...)
+	cmd.Stdin = a.Stdin()
+	cmd.Stdout = a.Stdout()
+	cmd.Stderr = a.Stderr()
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("after-setup hook failed: %w", err)
+	}
+
+	return nil
+}
+
+// runRegistryContainer runs "docker run" for the registry container,
+// whose output is streamed, and returns its standard error output
+func runRegistryContainer(a *app.AppContext, args []string) (string, error) {
+	a.D("Running: %s", utils.RedactCommandLine("docker", args...))
+
+	var stderr bytes.Buffer
+
+	cmd := exec.Command("docker", args...)
+	cmd.Stdout = a.Stdout()
+	cmd.Stderr = io.MultiWriter(a.Stderr(), &stderr)
+
+	err := cmd.Run()
+	return stderr.String(), err
+}
+
+// runRemoteSetup runs the setup with the same flags on a remote host
+func runRemoteSetup(a *app.AppContext, opts *RemoteOptions, flags *pflag.FlagSet) error {
+	remoteArgs, err := collectRemoteArgs(flags)
+	if err != nil {
+		return err
+	}
+
+	args := append([]string{"setup"}, remoteArgs...)
+	if a.Config().Yes && !flags.Changed("yes") {
+		args = append(args, "--yes")
+	}
+
+	return runRemote(a, opts, args)
+}
+
+func runSetup(a *app.AppContext, opts *SetupOptions) error {
+	if opts.RegistryPort < 0 || opts.RegistryPort > 65535 {
+		return fmt.Errorf("invalid --registry-port value %d: expected 0 (free port) or a port between 1 and 65535", opts.RegistryPort)
+	}
+	if err := validateRegistryResources(opts); err != nil {
+		return err
+	}
+	if err := validateRegistryLogLevel(opts.RegistryLogLevel); err != nil {
+		return err
+	}
+	if err := validateRegistryPullPolicy(opts.Pull); err != nil {
+		return err
+	}
+	if err := validateRegistryUploadPurging(opts); err != nil {
+		return err
+	}
+	if opts.RegistryHtpasswdFile != "" {
+		htpasswdFile, err := resolveRegistryHtpasswdFile(a, opts.RegistryHtpasswdFile)
+		if err != nil {
+			return err
+		}
+		opts.RegistryHtpasswdFile = htpasswdFile
+	}
+	if err := validateRegistryEnv(a, opts.RegistryEnv); err != nil {
+		return err
+	}
+	if opts.TrustCert && !opts.SelfSigned {
+		return fmt.Errorf("--trust-cert requires --self-signed")
+	}
+	if opts.TrustCert && !utils.IsRoot() {
+		return newRootPrivilegesError(a, "--trust-cert")
+	}
+	if opts.Launchd && a.Platform().OS != utils.OSDarwin {
+		return fmt.Errorf("--launchd is only supported on macOS")
+	}
+	if opts.OpenFirewall && !utils.IsRoot() {
+		return newRootPrivilegesError(a, "--open-firewall")
+	}
+	if opts.ConfigureInsecure && a.Platform().OS != utils.OSLinux {
+		return fmt.Errorf("--configure-insecure is only supported on Linux, please add the registry to the insecure registries in the settings of Docker Desktop")
+	}
+	if opts.ConfigureInsecure && opts.SelfSigned {
+		return fmt.Errorf("--configure-insecure cannot be used with --self-signed, use --trust-cert instead")
+	}
+	if opts.ConfigureInsecure && !utils.IsRoot() {
+		return newRootPrivilegesError(a, "--configure-insecure")
+	}
+
 	// Check firewall status unless --no-firewall is set
 	if !opts.NoFirewall {
 		a.WriteLn("Checking firewall status...")
@@ -791,10 +1971,10 @@ func runSetup(a *app.AppContext, opts *SetupOptions) {
 		firewallInfo := checkFirewall()
 
 		if firewallInfo.Installed {
-			a.WriteF("[OK] Firewall detected: %s", firewallInfo.Name)
+			a.WriteF("%s Firewall detected: %s", a.Status(app.StatusOK), firewallInfo.Name)
 			a.WriteLn("")
 		} else {
-			a.WriteF("[WARN] No firewall detected.")
+			a.WriteF("%s No firewall detected.", a.Status(app.StatusWarn))
 			a.WriteLn("")
 			a.WriteLn("")
 
@@ -802,21 +1982,11 @@ func runSetup(a *app.AppContext, opts *SetupOptions) {
 				// Check for root privileges
 				if !utils.IsRoot() {
 					a.WriteLn("")
-					if runtime.GOOS == "windows" {
-						a.WriteErrLn("Error: Firewall installation requires administrator privileges.")
-						a.WriteErrLn("Please run this command as Administrator.")
-					} else {
-						a.WriteErrLn("Error: Firewall installation requires root privileges.")
-						a.WriteErrLn("Please run this command with sudo.")
-					}
-					os.Exit(1)
-					return
+					return newRootPrivilegesError(a, "Firewall installation")
 				}
 
 				if err := installFirewall(a); err != nil {
-					a.WriteErrLn(fmt.Sprintf("Failed to install firewall: %s", err.Error()))
-					os.Exit(1)
-					return
+					return fmt.Errorf("Failed to install firewall: %w", err)
 				}
 
 				a.WriteLn("Firewall installed successfully.")
@@ -835,13 +2005,13 @@ func runSetup(a *app.AppContext, opts *SetupOptions) {
 		sshInfo := checkSSH()
 
 		if sshInfo.Installed && sshInfo.Running {
-			a.WriteF("[OK] SSH server detected: %s (running)", sshInfo.Name)
+			a.WriteF("%s SSH server detected: %s (running)", a.Status(app.StatusOK), sshInfo.Name)
 			a.WriteLn("")
 		} else if sshInfo.Installed {
-			a.WriteF("[WARN] SSH server installed but not running: %s", sshInfo.Name)
+			a.WriteF("%s SSH server installed but not running: %s", a.Status(app.StatusWarn), sshInfo.Name)
 			a.WriteLn("")
 		} else {
-			a.WriteF("[WARN] No SSH server detected.")
+			a.WriteF("%s No SSH server detected.", a.Status(app.StatusWarn))
 			a.WriteLn("")
 			a.WriteLn("")
 
@@ -849,15 +2019,7 @@ func runSetup(a *app.AppContext, opts *SetupOptions) {
 				// Check for root privileges
 				if !utils.IsRoot() {
 					a.WriteLn("")
-					if runtime.GOOS == "windows" {
-						a.WriteErrLn("Error: SSH installation requires administrator privileges.")
-						a.WriteErrLn("Please run this command as Administrator.")
-					} else {
-						a.WriteErrLn("Error: SSH installation requires root privileges.")
-						a.WriteErrLn("Please run this command with sudo.")
-					}
-					os.Exit(1)
-					return
+					return newRootPrivilegesError(a, "SSH installation")
 				}
 
 				// Generate a random available port as suggestion
@@ -871,9 +2033,7 @@ func runSetup(a *app.AppContext, opts *SetupOptions) {
 
 				// Verify the port is available
 				if !isTCPPortAvailable(sshPort) {
-					a.WriteErrLn(fmt.Sprintf("Port %d is already in use. Please choose a different port.", sshPort))
-					os.Exit(1)
-					return
+					return fmt.Errorf("Port %d is already in use. Please choose a different port", sshPort)
 				}
 
 				a.WriteLn("")
@@ -881,9 +2041,7 @@ func runSetup(a *app.AppContext, opts *SetupOptions) {
 				a.WriteLn("")
 
 				if err := installSSH(a, sshPort); err != nil {
-					a.WriteErrLn(fmt.Sprintf("Failed to install SSH server: %s", err.Error()))
-					os.Exit(1)
-					return
+					return fmt.Errorf("Failed to install SSH server: %w", err)
 				}
 
 				a.WriteF("SSH server installed successfully on port %d.", sshPort)
@@ -899,57 +2057,443 @@ func runSetup(a *app.AppContext, opts *SetupOptions) {
 	a.WriteLn("Checking Docker registry status...")
 	a.WriteLn("")
 
-	port := opts.RegistryPort
-	a.D("Using registry port: %d", port)
+	detectHostIP := utils.DetectPrimaryIPv4
+	if opts.RegistryInterface != "" {
+		detectHostIP = func() (net.IP, error) {
+			return utils.InterfaceIPv4(opts.RegistryInterface)
+		}
+
+		if _, err := detectHostIP(); err != nil {
+			return fmt.Errorf("invalid --registry-interface: %w", err)
+		}
+	}
+
+	hostname := opts.RegistryHostname
+	if hostname == "" {
+		hostname = getDefaultRegistryHostname(detectHostIP)
+	}
+	a.D("Using registry hostname: %s", hostname)
+
+	if opts.RegistryHostname != "" {
+		if err := ensureRegistryHostnameResolves(a, hostname, net.LookupHost, detectHostIP); err != nil {
+			return err
+		}
+	}
 
 	// Check if Docker is available
 	if !utils.CommandExists("docker") {
-		a.WriteErrLn("Docker is not installed. Please run 'autark doctor --repair' first.")
-		os.Exit(1)
-		return
+		return fmt.Errorf("Docker is not installed. Please run 'autark doctor --repair' first")
 	}
 
 	// Check if registry is already running
-	running, err := checkRegistryRunning()
+	running, err := checkRegistryRunning(opts.RegistryName)
 	if err != nil {
-		a.WriteErrLn(fmt.Sprintf("Error checking registry status: %s", err.Error()))
-		os.Exit(1)
-		return
+		return fmt.Errorf("Error checking registry status: %w", err)
+	}
+
+	if running && opts.RegistryPort == 0 {
+		a.WriteLn("Docker registry is already running.")
+
+		if opts.Open {
+			a.W("Cannot open the catalog page, because the port of the running registry is unknown with --registry-port 0")
+		}
+		return nil
 	}
 
 	if running {
-		a.WriteF("Docker registry is already running on port %d.", port)
+		actual, err := inspectRegistryContainer(opts.RegistryName)
+		if err != nil {
+			return fmt.Errorf("Error inspecting registry container: %w", err)
+		}
+
+		differences := compareRegistryContainerConfig(actual, getExpectedRegistryContainerConfig(opts))
+		if len(differences) == 0 {
+			a.WriteF("Docker registry is already running on port %d with the same options.", opts.RegistryPort)
+			a.WriteLn("")
+
+			if opts.OpenFirewall {
+				if err := openFirewallPort(a, opts.RegistryPort, opts.PersistFirewall); err != nil {
+					return fmt.Errorf("Failed to open firewall: %w", err)
+				}
+			}
+
+			if opts.ConfigureInsecure {
+				if err := configureInsecureRegistry(a, net.JoinHostPort(hostname, strconv.Itoa(opts.RegistryPort))); err != nil {
+					return fmt.Errorf("Failed to configure insecure registry: %w", err)
+				}
+			}
+
+			if opts.Open {
+				openRegistryCatalog(a, opts)
+			}
+
+			if opts.AfterSetupHook != "" {
+				certsDir := ""
+				if opts.SelfSigned {
+					if certsDir, err = getRegistryCertsDir(); err != nil {
+						return err
+					}
+				}
+
+				return runAfterSetupHook(a, opts, hostname, opts.RegistryPort, certsDir)
+			}
+			return nil
+		}
+
+		a.WriteF("%s Docker registry is already running, but with different options:", a.Status(app.StatusWarn))
+		a.WriteLn("")
+		for _, difference := range differences {
+			a.WriteLn("  - " + difference)
+		}
+		a.WriteLn("")
+
+		// images are stored inside the container and get lost
+		if !a.PromptYesNo("Would you like to recreate the registry container? All pushed images will be lost", false) {
+			a.WriteLn("Keeping the running registry container.")
+			return nil
+		}
 		a.WriteLn("")
-		return
 	}
 
-	a.WriteF("Docker registry is not running on port %d.", port)
-	a.WriteLn("")
-	a.WriteLn("")
+	if opts.RegistryPort == 0 {
+		selectedPort, ok := findRandomAvailablePort(isTCPPortAvailable)
+		if !ok {
+			return fmt.Errorf("Could not find a free port for the registry. Please specify one with --registry-port")
+		}
+
+		opts.RegistryPort = selectedPort
+		a.WriteF("Selected free port %d for the registry.", selectedPort)
+		a.WriteLn("")
+	}
+
+	port := opts.RegistryPort
+	a.D("Using registry port: %d", port)
+
+	if !running {
+		a.WriteF("Docker registry is not running on port %d.", port)
+		a.WriteLn("")
+		a.WriteLn("")
+	}
+
+	certsDir := ""
+	if opts.SelfSigned {
+		certsDir, err = ensureRegistryCertificate(a, hostname)
+		if err != nil {
+			return err
+		}
+	}
 
 	// Install the registry
-	if err := installRegistry(a, port); err != nil {
-		a.WriteErrLn(fmt.Sprintf("Failed to install registry: %s", err.Error()))
-		os.Exit(1)
-		return
+	if err := installRegistry(a, opts, certsDir); err != nil {
+		return fmt.Errorf("Failed to install registry: %w", err)
 	}
 
 	// Verify the registry is running
-	running, err = checkRegistryRunning()
+	running, err = checkRegistryRunning(opts.RegistryName)
 	if err != nil {
-		a.WriteErrLn(fmt.Sprintf("Error verifying registry status: %s", err.Error()))
-		os.Exit(1)
-		return
+		return fmt.Errorf("Error verifying registry status: %w", err)
 	}
 
 	if !running {
-		a.WriteErrLn("Registry container started but is not running. Please check Docker logs.")
-		os.Exit(1)
-		return
+		return fmt.Errorf("Registry container started but is not running. Please check Docker logs")
+	}
+
+	scheme := "http"
+	if certsDir != "" {
+		scheme = "https"
+	}
+
+	client := &http.Client{
+		Timeout: registryCheckTimeout,
+		Transport: &http.Transport{
+			// only the header is checked and the self-signed
+			// certificate may not be trusted yet
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+	}
+
+	baseURL := fmt.Sprintf("%s://localhost:%d", scheme, port)
+	if err := verifyRegistryContainer(a, client, baseURL, opts.RegistryName, registryHealthTimeout, removeRegistryContainer); err != nil {
+		return err
 	}
 
 	a.WriteLn("")
 	a.WriteF("Docker registry successfully installed and running on port %d.", port)
 	a.WriteLn("")
+
+	if opts.Launchd {
+		if err := installRegistryLaunchdAgent(a, opts); err != nil {
+			return fmt.Errorf("Failed to install launchd agent: %w", err)
+		}
+	}
 	a.WriteLn("The registry will automatically restart on system boot.")
+
+	if certsDir != "" {
+		a.WriteF("The registry is served via TLS with the self-signed certificate %s.", filepath.Join(certsDir, registryCertFileName))
+		a.WriteLn("")
+
+		if opts.TrustCert {
+			registryAddress := net.JoinHostPort(hostname, strconv.Itoa(port))
+			if err := trustRegistryCert(a, filepath.Join(certsDir, registryCertFileName), registryAddress); err != nil {
+				return fmt.Errorf("Failed to trust registry certificate: %w", err)
+			}
+		}
+	}
+
+	if opts.OpenFirewall {
+		a.WriteLn("")
+		if err := openFirewallPort(a, port, opts.PersistFirewall); err != nil {
+			return fmt.Errorf("Failed to open firewall: %w", err)
+		}
+	}
+
+	if opts.ConfigureInsecure {
+		a.WriteLn("")
+		if err := configureInsecureRegistry(a, net.JoinHostPort(hostname, strconv.Itoa(port))); err != nil {
+			return fmt.Errorf("Failed to configure insecure registry: %w", err)
+		}
+	}
+
+	writeRegistryClientInstructions(a, hostname, port, certsDir)
+
+	if opts.Open {
+		openRegistryCatalog(a, opts)
+	}
+
+	if opts.AfterSetupHook != "" {
+		return runAfterSetupHook(a, opts, hostname, port, certsDir)
+	}
+
+	return nil
+}
+
+// shouldPullRegistryImage decides with the pull policy and if the
+// image exists locally, if the registry image has to be pulled
+func shouldPullRegistryImage(policy string, imageExists bool) (bool, error) {
+	switch policy {
+	case registryPullAlways:
+		return true, nil
+	case registryPullMissing:
+		return !imageExists, nil
+	case registryPullNever:
+		if !imageExists {
+			return false, fmt.Errorf("image %s does not exist and --pull is %s. Please pull it first or use --pull %s", registryImage, registryPullNever, registryPullMissing)
+		}
+		return false, nil
+	default:
+		return false, validateRegistryPullPolicy(policy)
+	}
+}
+
+// trustRegistryCert makes docker on this host trust the
+// self-signed certificate of the registry
+func trustRegistryCert(a *app.AppContext, certFile string, registryAddress string) error {
+	goos := a.Platform().OS
+
+	if goos == utils.OSWindows {
+		return runInstallCommandDirect(a, "powershell", "-Command",
+			fmt.Sprintf(`Import-Certificate -FilePath "%s" -CertStoreLocation Cert:\LocalMachine\Root`, certFile))
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("could not determine home directory: %w", err)
+	}
+
+	certPEM, err := os.ReadFile(certFile)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", certFile, err)
+	}
+
+	certPath := getDockerCertPath(goos, homeDir, registryAddress)
+	if err := checkWritableFilesystem(certPath); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(certPath), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(certPath), err)
+	}
+
+	if written, err := utils.WriteFileIfChanged(certPath, certPEM, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", certPath, err)
+	} else if !written {
+		a.D("%s is up to date", certPath)
+	}
+
+	// ~/.docker/certs.d/<host>:<port> on macOS
+	for dir := filepath.Dir(certPath); strings.HasPrefix(dir, homeDir+string(filepath.Separator)); dir = filepath.Dir(dir) {
+		dropFileOwnership(a, dir)
+	}
+	dropFileOwnership(a, certPath)
+
+	a.WriteF("Docker on this host trusts the registry certificate via %s.", certPath)
+	a.WriteLn("")
+	return nil
+}
+
+// validateRegistryResources checks the values of --memory and --cpus
+// before anything is changed on the system
+func validateRegistryResources(opts *SetupOptions) error {
+	if opts.Memory != "" && !registryMemoryRegex.MatchString(opts.Memory) {
+		return fmt.Errorf("invalid --memory value %q: expected a number with an optional unit b, k, m or g, like 512m or 2g", opts.Memory)
+	}
+
+	if opts.CPUs != "" {
+		cpus, err := strconv.ParseFloat(opts.CPUs, 64)
+		if err != nil || cpus <= 0 {
+			return fmt.Errorf("invalid --cpus value %q: expected a positive number, like 0.5 or 2", opts.CPUs)
+		}
+	}
+
+	return nil
+}
+
+// verifyRegistryContainer waits, until the started container answers
+// on baseURL, and checks if it is a Docker registry
+//
+// If not, the container is removed with remove, because otherwise
+// it would be restarted on every boot
+func verifyRegistryContainer(a *app.AppContext, client *http.Client, baseURL string, name string, timeout time.Duration, remove func(a *app.AppContext, name string)) error {
+	a.D("Verifying registry on %s ...", baseURL)
+
+	var identityErr error
+	err := utils.PollUntil(timeout, registryHealthInterval, func() (bool, error) {
+		err := verifyRegistryIdentity(client, baseURL)
+
+		var urlErr *url.Error
+		if err != nil && errors.As(err, &urlErr) {
+			return false, err // not ready yet
+		}
+
+		identityErr = err
+		return true, nil
+	})
+	if err == nil {
+		err = identityErr
+	}
+	if err == nil {
+		return nil
+	}
+
+	a.W("Removing container %s, because it does not answer like a Docker registry...", name)
+	remove(a, name)
+
+	return fmt.Errorf("Image %s does not appear to be a Docker registry: %w", registryImage, err)
+}
+
+// writeRegistryClientInstructions writes how clients can
+// configure docker to use the registry
+func writeRegistryClientInstructions(a *app.AppContext, hostname string, port int, certsDir string) {
+	registryAddress := net.JoinHostPort(hostname, strconv.Itoa(port))
+
+	a.WriteLn("")
+	a.WriteF("To use the registry at %s from other machines:", registryAddress)
+	a.WriteLn("")
+
+	if certsDir != "" {
+		certFile := filepath.Join(certsDir, registryCertFileName)
+
+		a.WriteF("  1. Copy %s to each client and trust it in docker, like on %s:", certFile, a.Platform().OS)
+		for _, instruction := range getRegistryCertInstructions(a.Platform().OS, certFile, registryAddress) {
+			a.WriteLn("")
+			a.WriteF("       %s", instruction)
+		}
+	} else {
+		a.WriteF(`  1. Add "%s" to "insecure-registries" in /etc/docker/daemon.json on each client and restart docker`, registryAddress)
+	}
+	a.WriteLn("")
+
+	a.WriteF("  2. docker tag <image> %s/<image>", registryAddress)
+	a.WriteLn("")
+	a.WriteF("  3. docker push %s/<image>", registryAddress)
+	a.WriteLn("")
+}
+
+// validateHtpasswd checks if data is a non-empty htpasswd file with
+// "user:hash" lines, where the hashes use bcrypt, because other
+// algorithms are not supported by the registry
+func validateHtpasswd(data []byte) error {
+	users := 0
+
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		user, hash, ok := strings.Cut(line, ":")
+		if !ok || user == "" || hash == "" {
+			return fmt.Errorf("line %d: expected user:hash", i+1)
+		}
+		if !strings.HasPrefix(hash, "$2") {
+			return fmt.Errorf("line %d: the hash of %s is no bcrypt hash, create it with 'htpasswd -B'", i+1, user)
+		}
+
+		users++
+	}
+
+	if users == 0 {
+		return fmt.Errorf("file contains no users")
+	}
+
+	return nil
+}
+
+// validateRegistryEnv checks the values of --registry-env and warns
+// about keys, which are not known by the registry
+func validateRegistryEnv(a *app.AppContext, registryEnv []string) error {
+	env, err := utils.ParseKeyValuePairs(registryEnv)
+	if err != nil {
+		return fmt.Errorf("invalid --registry-env: %w", err)
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(env)) {
+		if !strings.HasPrefix(key, "REGISTRY_") {
+			a.W("--registry-env %s does not start with REGISTRY_ and may be ignored by the registry", key)
+		}
+	}
+
+	return nil
+}
+
+// validateRegistryLogLevel checks the value of --registry-log-level
+func validateRegistryLogLevel(level string) error {
+	if level == "" || slices.Contains(registryLogLevels, level) {
+		return nil
+	}
+
+	return fmt.Errorf("invalid --registry-log-level value %q: expected one of %s", level, strings.Join(registryLogLevels, ", "))
+}
+
+// validateRegistryPullPolicy checks the value of --pull
+func validateRegistryPullPolicy(policy string) error {
+	if slices.Contains(registryPullPolicies, policy) {
+		return nil
+	}
+
+	return fmt.Errorf("invalid --pull value %q: expected one of %s", policy, strings.Join(registryPullPolicies, ", "))
+}
+
+// validateRegistryUploadPurging checks the durations of
+// --upload-purging-age and --upload-purging-interval
+func validateRegistryUploadPurging(opts *SetupOptions) error {
+	if !opts.UploadPurging {
+		return nil
+	}
+
+	durations := []struct {
+		flag  string
+		value string
+	}{
+		{"--upload-purging-age", opts.UploadPurgingAge},
+		{"--upload-purging-interval", opts.UploadPurgingInterval},
+	}
+
+	for _, d := range durations {
+		duration, err := time.ParseDuration(d.value)
+		if err != nil || duration <= 0 {
+			return fmt.Errorf("invalid %s value %q: expected a positive duration, like 24h or 90m", d.flag, d.value)
+		}
+	}
+
+	return nil
 }
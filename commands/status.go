@@ -0,0 +1,209 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/mkloubert/autark/app"
+	"github.com/mkloubert/autark/utils"
+	"github.com/spf13/cobra"
+)
+
+// StatusOptions contains options for the status command
+type StatusOptions struct {
+	// JSON indicates if the status should be written as JSON
+	JSON bool
+	// RegistryName is the name of the registry container
+	RegistryName string
+}
+
+// StatusSection contains the state of a part of the system
+type StatusSection struct {
+	// OK indicates if the part is installed and running
+	OK bool `json:"ok"`
+	// State is a readable description, like "running"
+	State string `json:"state"`
+	// Port is the port of the part, which is 0 if unknown
+	Port int `json:"port,omitempty"`
+}
+
+// SystemStatus contains the state of all parts of the system,
+// which are managed by autark
+type SystemStatus struct {
+	Platform *utils.PlatformInfo `json:"platform"`
+	Docker   *StatusSection      `json:"docker"`
+	Registry *StatusSection      `json:"registry"`
+	SSH      *StatusSection      `json:"ssh"`
+	Firewall *StatusSection      `json:"firewall"`
+}
+
+// statusChecks contains the functions, which detect the state
+// of the parts of the system for the status command
+type statusChecks struct {
+	dockerDaemon func() *DoctorResult
+	registry     func() (bool, int, error)
+	ssh          func() *SSHInfo
+	firewall     func() *FirewallInfo
+}
+
+// collectSystemStatus runs the checks and collects their results
+func collectSystemStatus(platform *utils.PlatformInfo, checks *statusChecks) *SystemStatus {
+	status := &SystemStatus{
+		Platform: platform,
+	}
+
+	dockerDaemonResult := checks.dockerDaemon()
+	status.Docker = &StatusSection{OK: dockerDaemonResult.Installed, State: "running"}
+	if dockerDaemonResult.Version != "" {
+		status.Docker.State = dockerDaemonResult.Version
+	}
+	if dockerDaemonResult.Error != nil {
+		status.Docker.State = dockerDaemonResult.Error.Error()
+	}
+
+	running, port, err := checks.registry()
+	switch {
+	case err != nil:
+		status.Registry = &StatusSection{State: err.Error()}
+	case running:
+		status.Registry = &StatusSection{OK: true, State: "running", Port: port}
+	default:
+		status.Registry = &StatusSection{State: "not running"}
+	}
+
+	sshInfo := checks.ssh()
+	switch {
+	case sshInfo.Installed && sshInfo.Running:
+		status.SSH = &StatusSection{OK: true, State: fmt.Sprintf("%s (running)", sshInfo.Name)}
+	case sshInfo.Installed:
+		status.SSH = &StatusSection{State: fmt.Sprintf("%s (not running)", sshInfo.Name)}
+	default:
+		status.SSH = &StatusSection{State: "not installed"}
+	}
+
+	firewallInfo := checks.firewall()
+	if firewallInfo.Installed {
+		status.Firewall = &StatusSection{OK: true, State: firewallInfo.Name}
+	} else {
+		status.Firewall = &StatusSection{State: "not installed"}
+	}
+
+	return status
+}
+
+// getStatusChecks returns the checks of the status command,
+// which reuse the ones of doctor and setup
+func getStatusChecks(a *app.AppContext, opts *StatusOptions) *statusChecks {
+	return &statusChecks{
+		dockerDaemon: func() *DoctorResult {
+			dockerProvider := ""
+			if a.Platform().OS == utils.OSDarwin {
+				dockerProvider = getDarwinDockerProvider()
+			}
+
+			return checkDockerDaemon(checkDocker(), dockerProvider)
+		},
+		registry: func() (bool, int, error) {
+			running, err := checkRegistryRunning(opts.RegistryName)
+			if err != nil || !running {
+				return running, 0, err
+			}
+
+			config, err := inspectRegistryContainer(opts.RegistryName)
+			if err != nil {
+				return true, 0, nil // port is unknown only
+			}
+			return true, config.Port, nil
+		},
+		ssh:      checkSSH,
+		firewall: checkFirewall,
+	}
+}
+
+func initStatusCommand(a *app.AppContext) {
+	rootCmd := a.RootCommand()
+
+	opts := &StatusOptions{}
+
+	statusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the status of the system",
+		Long:  `Shows the platform and the state of the docker daemon, the registry, the SSH server and the firewall without changing anything.`,
+		Example: `  autark status
+  autark status --json --registry-name my-registry`,
+		Run: func(cmd *cobra.Command, args []string) {
+			exitOnError(a, runStatus(a, opts, getStatusChecks(a, opts)))
+		},
+	}
+
+	flags := statusCmd.Flags()
+	flags.BoolVarP(&opts.JSON, "json", "", false, "Output as JSON")
+	flags.StringVarP(&opts.RegistryName, "registry-name", "", registryContainerName, "Name of the registry container")
+
+	rootCmd.AddCommand(statusCmd)
+}
+
+func runStatus(a *app.AppContext, opts *StatusOptions, checks *statusChecks) error {
+	status := collectSystemStatus(a.Platform(), checks)
+
+	if opts.JSON {
+		data, err := json.MarshalIndent(status, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		a.WriteLn(string(data))
+		return nil
+	}
+
+	registryState := status.Registry.State
+	if status.Registry.Port != 0 {
+		registryState = fmt.Sprintf("%s on port %d", registryState, status.Registry.Port)
+	}
+
+	table := app.NewTable()
+	table.Colorize = a.StatusColors(0)
+
+	table.AddRow(a.Status(app.StatusInfo), "platform", status.Platform.String())
+	for _, row := range []struct {
+		name    string
+		section *StatusSection
+		state   string
+	}{
+		{"docker daemon", status.Docker, status.Docker.State},
+		{"registry", status.Registry, registryState},
+		{"ssh server", status.SSH, status.SSH.State},
+		{"firewall", status.Firewall, status.Firewall.State},
+	} {
+		marker := a.Status(app.StatusOK)
+		if !row.section.OK {
+			marker = a.Status(app.StatusWarn)
+		}
+
+		table.AddRow(marker, row.name, row.state)
+	}
+
+	a.WriteTable(table)
+	return nil
+}
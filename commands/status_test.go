@@ -0,0 +1,109 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package commands
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCollectSystemStatus(t *testing.T) {
+	checks := &statusChecks{
+		dockerDaemon: func() *DoctorResult {
+			return &DoctorResult{Installed: true, Version: "28.0.1"}
+		},
+		registry: func() (bool, int, error) {
+			return true, 5000, nil
+		},
+		ssh: func() *SSHInfo {
+			return &SSHInfo{Name: "openssh", Installed: true, Running: true}
+		},
+		firewall: func() *FirewallInfo {
+			return &FirewallInfo{Name: "ufw", Installed: true}
+		},
+	}
+
+	status := collectSystemStatus(nil, checks)
+
+	want := map[string]StatusSection{
+		"docker":   {OK: true, State: "28.0.1"},
+		"registry": {OK: true, State: "running", Port: 5000},
+		"ssh":      {OK: true, State: "openssh (running)"},
+		"firewall": {OK: true, State: "ufw"},
+	}
+	got := map[string]StatusSection{
+		"docker":   *status.Docker,
+		"registry": *status.Registry,
+		"ssh":      *status.SSH,
+		"firewall": *status.Firewall,
+	}
+	for name, section := range want {
+		if got[name] != section {
+			t.Errorf("%s = %+v, want %+v", name, got[name], section)
+		}
+	}
+}
+
+func TestCollectSystemStatusFailures(t *testing.T) {
+	checks := &statusChecks{
+		dockerDaemon: func() *DoctorResult {
+			return &DoctorResult{Error: errors.New("daemon not running")}
+		},
+		registry: func() (bool, int, error) {
+			return false, 0, errors.New("docker not available")
+		},
+		ssh: func() *SSHInfo {
+			return &SSHInfo{Name: "openssh", Installed: true}
+		},
+		firewall: func() *FirewallInfo {
+			return &FirewallInfo{}
+		},
+	}
+
+	status := collectSystemStatus(nil, checks)
+
+	want := map[string]StatusSection{
+		"docker":   {State: "daemon not running"},
+		"registry": {State: "docker not available"},
+		"ssh":      {State: "openssh (not running)"},
+		"firewall": {State: "not installed"},
+	}
+	got := map[string]StatusSection{
+		"docker":   *status.Docker,
+		"registry": *status.Registry,
+		"ssh":      *status.SSH,
+		"firewall": *status.Firewall,
+	}
+	for name, section := range want {
+		if got[name] != section {
+			t.Errorf("%s = %+v, want %+v", name, got[name], section)
+		}
+	}
+
+	checks.registry = func() (bool, int, error) { return false, 0, nil }
+	checks.ssh = func() *SSHInfo { return &SSHInfo{} }
+
+	status = collectSystemStatus(nil, checks)
+	if status.Registry.State != "not running" || status.SSH.State != "not installed" {
+		t.Errorf("registry = %+v, ssh = %+v, want \"not running\" and \"not installed\"", status.Registry, status.SSH)
+	}
+}
@@ -0,0 +1,88 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package commands
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/mkloubert/autark/app"
+	"github.com/mkloubert/autark/utils"
+	"github.com/spf13/cobra"
+)
+
+func initSupportedCommand(a *app.AppContext) {
+	rootCmd := a.RootCommand()
+
+	supportedCmd := &cobra.Command{
+		Use:     "supported",
+		Short:   "List supported distributions and package managers",
+		Long:    `Lists the Linux distributions and package managers, on which autark knows how to install docker, git, SSH server and firewall.`,
+		Example: `  autark supported`,
+		Run: func(cmd *cobra.Command, args []string) {
+			writeSupportMatrix(a, a.Config().EOL)
+		},
+	}
+
+	rootCmd.AddCommand(supportedCmd)
+}
+
+// supportedMark returns the mark for a support matrix cell
+func supportedMark(supported bool) string {
+	if supported {
+		return "yes"
+	}
+
+	return "-"
+}
+
+// writeSupportMatrix writes the support matrix, which is derived
+// from the installers used by doctor and setup
+func writeSupportMatrix(w io.Writer, eol string) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Linux distributions:%s", eol)
+	fmt.Fprintf(tw, "DISTRIBUTION\tDOCKER\tSSH\tFIREWALL%s", eol)
+	for _, distro := range utils.KnownLinuxDistros {
+		_, hasDocker := linuxDockerInstallers[distro]
+		_, hasSSH := linuxSSHInstallers[distro]
+		_, hasFirewall := linuxFirewallInstallers[distro]
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s%s", distro, supportedMark(hasDocker), supportedMark(hasSSH), supportedMark(hasFirewall), eol)
+	}
+	tw.Flush()
+
+	fmt.Fprintf(w, "%s", eol)
+
+	fmt.Fprintf(tw, "Package managers:%s", eol)
+	fmt.Fprintf(tw, "PACKAGE MANAGER\tGIT%s", eol)
+	for _, pkgMgr := range utils.KnownPackageManagers {
+		_, err := getGitInstallCommand(pkgMgr, nil)
+
+		fmt.Fprintf(tw, "%s\t%s%s", pkgMgr, supportedMark(err == nil), eol)
+	}
+	tw.Flush()
+
+	fmt.Fprintf(w, "%s", eol)
+	fmt.Fprintf(w, "On other Linux distributions, autark tries to use the detected package manager instead.%s", eol)
+}
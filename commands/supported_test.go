@@ -0,0 +1,47 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package commands
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+)
+
+func TestWriteSupportMatrix(t *testing.T) {
+	var buffer bytes.Buffer
+	writeSupportMatrix(&buffer, "\n")
+	output := buffer.String()
+
+	rows := []string{
+		`(?m)^ubuntu\s+yes\s+yes\s+yes$`,
+		`(?m)^debian\s+yes\s+yes\s+yes$`,
+		`(?m)^fedora\s+yes\s+yes\s+yes$`,
+		`(?m)^apt\s+yes$`,
+		`(?m)^flatpak\s+-$`,
+	}
+	for _, row := range rows {
+		if !regexp.MustCompile(row).MatchString(output) {
+			t.Errorf("output does not match %s:\n%s", row, output)
+		}
+	}
+}
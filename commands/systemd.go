@@ -0,0 +1,117 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package commands
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/mkloubert/autark/app"
+	"github.com/mkloubert/autark/utils"
+)
+
+// systemdUnitDir is the directory of the systemd units,
+// which are installed by the administrator
+const systemdUnitDir = "/etc/systemd/system"
+
+// buildSystemdUnit returns the content of a systemd unit, which keeps
+// a container running by starting it attached with docker, so systemd
+// starts it again, whenever it stops, and its logs go to the journal
+func buildSystemdUnit(dockerPath string, containerName string) string {
+	return fmt.Sprintf(`[Unit]
+Description=Docker registry %[2]s of autark
+Requires=docker.service
+After=docker.service network-online.target
+Wants=network-online.target
+
+[Service]
+ExecStart=%[1]s start --attach %[2]s
+ExecStop=%[1]s stop %[2]s
+Restart=always
+RestartSec=10
+
+[Install]
+WantedBy=multi-user.target
+`, dockerPath, containerName)
+}
+
+// getSystemdUnitPath returns the path of the systemd
+// unit, which keeps a container running
+func getSystemdUnitPath(containerName string) string {
+	return filepath.Join(systemdUnitDir, containerName+".service")
+}
+
+// installRegistrySystemdService writes and enables the systemd unit,
+// which keeps the registry container running instead of the restart
+// policy of docker
+func installRegistrySystemdService(a *app.AppContext, opts *RegistryOptions, runtime containerRuntime) error {
+	if !a.Platform().Capabilities().HasSystemd {
+		return fmt.Errorf("systemd is not the running init system of this host")
+	}
+	if !utils.IsRoot() {
+		return newRootPrivilegesError(a, "registry install-service")
+	}
+
+	exists, err := runtime.ContainerExists(opts.Name)
+	if err != nil {
+		return fmt.Errorf("Error checking registry container %s: %w", opts.Name, err)
+	}
+	if !exists {
+		return fmt.Errorf("Registry container %s does not exist. Please run 'autark setup' first", opts.Name)
+	}
+
+	// systemd does not use the PATH of the shell
+	dockerPath, err := exec.LookPath("docker")
+	if err != nil {
+		return fmt.Errorf("could not find docker: %w", err)
+	}
+	if dockerPath, err = filepath.Abs(dockerPath); err != nil {
+		return err
+	}
+
+	unitPath := getSystemdUnitPath(opts.Name)
+	if err := checkWritableFilesystem(unitPath); err != nil {
+		return err
+	}
+
+	unit := buildSystemdUnit(dockerPath, opts.Name)
+	if _, err := utils.WriteFileIfChanged(unitPath, []byte(unit), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", unitPath, err)
+	}
+
+	// systemd restarts the container itself
+	if err := runtime.SetRestartPolicy(opts.Name, "no"); err != nil {
+		return fmt.Errorf("Failed to disable the restart policy of %s: %w", opts.Name, err)
+	}
+
+	if err := runInstallCommandDirect(a, "systemctl", "daemon-reload"); err != nil {
+		return fmt.Errorf("failed to run systemctl: %w", err)
+	}
+	if err := runInstallCommandDirect(a, "systemctl", "enable", "--now", filepath.Base(unitPath)); err != nil {
+		return fmt.Errorf("failed to run systemctl: %w", err)
+	}
+
+	a.WriteF("The registry is kept running by the systemd unit %s.", unitPath)
+	a.WriteLn("")
+	return nil
+}
@@ -0,0 +1,66 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package commands
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuildSystemdUnit(t *testing.T) {
+	unit := buildSystemdUnit("/usr/bin/docker", "autark-registry")
+
+	for _, line := range []string{
+		"Description=Docker registry autark-registry of autark",
+		"Requires=docker.service",
+		"After=docker.service network-online.target",
+		"ExecStart=/usr/bin/docker start --attach autark-registry",
+		"ExecStop=/usr/bin/docker stop autark-registry",
+		"Restart=always",
+		"RestartSec=10",
+		"WantedBy=multi-user.target",
+	} {
+		if !strings.Contains(unit, "\n"+line+"\n") {
+			t.Errorf("buildSystemdUnit() = %q, want it to contain the line %q", unit, line)
+		}
+	}
+
+	sections := []string{"[Unit]", "[Service]", "[Install]"}
+	last := -1
+	for _, section := range sections {
+		i := strings.Index(unit, section)
+		if i <= last {
+			t.Errorf("section %s is missing or not in order %q", section, sections)
+		}
+		last = i
+	}
+}
+
+func TestGetSystemdUnitPath(t *testing.T) {
+	got := getSystemdUnitPath("autark-registry")
+
+	want := filepath.Join(systemdUnitDir, "autark-registry.service")
+	if got != want {
+		t.Errorf("getSystemdUnitPath() = %q, want %q", got, want)
+	}
+}
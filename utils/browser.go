@@ -0,0 +1,54 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package utils
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+)
+
+// OpenURL opens a URL with the default browser of the current platform
+func OpenURL(url string) error {
+	name, args, err := getOpenURLCommand(runtime.GOOS, url)
+	if err != nil {
+		return err
+	}
+
+	return exec.Command(name, args...).Start()
+}
+
+// getOpenURLCommand returns the command and its arguments,
+// which open a URL on a specific platform
+func getOpenURLCommand(goos string, url string) (string, []string, error) {
+	switch goos {
+	case "darwin":
+		return "open", []string{url}, nil
+	case "windows":
+		// the empty argument is the title of the window
+		return "cmd", []string{"/C", "start", "", url}, nil
+	case "linux", "freebsd", "netbsd", "openbsd", "dragonfly":
+		return "xdg-open", []string{url}, nil
+	default:
+		return "", nil, fmt.Errorf("opening URLs is not supported on %s", goos)
+	}
+}
@@ -0,0 +1,58 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package utils
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestGetOpenURLCommand(t *testing.T) {
+	const url = "http://localhost:5000/v2/_catalog"
+
+	tests := []struct {
+		goos     string
+		wantName string
+		wantArgs []string
+	}{
+		{goos: "darwin", wantName: "open", wantArgs: []string{url}},
+		{goos: "windows", wantName: "cmd", wantArgs: []string{"/C", "start", "", url}},
+		{goos: "linux", wantName: "xdg-open", wantArgs: []string{url}},
+		{goos: "freebsd", wantName: "xdg-open", wantArgs: []string{url}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.goos, func(t *testing.T) {
+			name, args, err := getOpenURLCommand(tt.goos, url)
+			if err != nil {
+				t.Fatalf("getOpenURLCommand() error = %v", err)
+			}
+			if name != tt.wantName || !slices.Equal(args, tt.wantArgs) {
+				t.Errorf("getOpenURLCommand() = %s %v, want %s %v", name, args, tt.wantName, tt.wantArgs)
+			}
+		})
+	}
+
+	if _, _, err := getOpenURLCommand("plan9", url); err == nil {
+		t.Error("getOpenURLCommand(plan9) error = nil, want an error")
+	}
+}
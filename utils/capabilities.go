@@ -0,0 +1,104 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package utils
+
+import (
+	"os"
+	"strings"
+)
+
+// PlatformCapabilities contains the features of the current platform,
+// which can be used by commands to enable or skip steps
+type PlatformCapabilities struct {
+	// HasDocker indicates if the docker CLI is installed
+	HasDocker bool `json:"has_docker"`
+	// HasSystemd indicates if systemd is the running init system
+	HasSystemd bool `json:"has_systemd"`
+	// IsContainer indicates if autark runs inside a container
+	IsContainer bool `json:"is_container"`
+	// IsWSL indicates if autark runs inside the Windows Subsystem for Linux
+	IsWSL bool `json:"is_wsl"`
+}
+
+// platformState contains the functions to inspect the
+// system, which are required to detect the capabilities
+type platformState struct {
+	commandExists func(name string) bool
+	fileExists    func(path string) bool
+	readFile      func(path string) ([]byte, error)
+}
+
+// containerCgroupMarkers contains strings, which appear in the
+// cgroups of the init process inside a container
+var containerCgroupMarkers = []string{"containerd", "docker", "kubepods", "libpod", "lxc"}
+
+// Capabilities returns the capabilities of the platform,
+// which are only detected on the first call
+func (p *PlatformInfo) Capabilities() *PlatformCapabilities {
+	p.capabilitiesOnce.Do(func() {
+		p.capabilities = detectCapabilities(p.OS, &platformState{
+			commandExists: CommandExists,
+			fileExists:    fileExists,
+			readFile:      os.ReadFile,
+		})
+	})
+
+	return p.capabilities
+}
+
+// detectCapabilities detects the capabilities of an OS
+// with the help of a specific system state
+func detectCapabilities(osType OSType, state *platformState) *PlatformCapabilities {
+	capabilities := &PlatformCapabilities{
+		HasDocker: state.commandExists("docker"),
+	}
+
+	if osType != OSLinux {
+		return capabilities
+	}
+
+	// see sd_booted(3)
+	capabilities.HasSystemd = state.fileExists("/run/systemd/system")
+
+	capabilities.IsContainer = state.fileExists("/.dockerenv") || state.fileExists("/run/.containerenv")
+	if !capabilities.IsContainer {
+		if cgroup, err := state.readFile("/proc/1/cgroup"); err == nil {
+			for _, marker := range containerCgroupMarkers {
+				if strings.Contains(string(cgroup), marker) {
+					capabilities.IsContainer = true
+					break
+				}
+			}
+		}
+	}
+
+	if osRelease, err := state.readFile("/proc/sys/kernel/osrelease"); err == nil {
+		capabilities.IsWSL = strings.Contains(strings.ToLower(string(osRelease)), "microsoft")
+	}
+
+	return capabilities
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
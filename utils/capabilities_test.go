@@ -0,0 +1,108 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package utils
+
+import (
+	"os"
+	"slices"
+	"testing"
+)
+
+// newTestPlatformState creates a platformState, where only specific
+// commands and files exist
+func newTestPlatformState(commands []string, files map[string]string) *platformState {
+	return &platformState{
+		commandExists: func(name string) bool {
+			return slices.Contains(commands, name)
+		},
+		fileExists: func(path string) bool {
+			_, ok := files[path]
+			return ok
+		},
+		readFile: func(path string) ([]byte, error) {
+			content, ok := files[path]
+			if !ok {
+				return nil, os.ErrNotExist
+			}
+			return []byte(content), nil
+		},
+	}
+}
+
+func TestDetectCapabilities(t *testing.T) {
+	tests := []struct {
+		name     string
+		osType   OSType
+		commands []string
+		files    map[string]string
+		want     PlatformCapabilities
+	}{
+		{
+			name:     "linux host with systemd",
+			osType:   OSLinux,
+			commands: []string{"docker"},
+			files: map[string]string{
+				"/run/systemd/system":        "",
+				"/proc/1/cgroup":             "0::/init.scope\n",
+				"/proc/sys/kernel/osrelease": "6.8.0-45-generic\n",
+			},
+			want: PlatformCapabilities{HasDocker: true, HasSystemd: true},
+		},
+		{
+			name:   "docker container",
+			osType: OSLinux,
+			files:  map[string]string{"/.dockerenv": ""},
+			want:   PlatformCapabilities{IsContainer: true},
+		},
+		{
+			name:   "kubernetes pod",
+			osType: OSLinux,
+			files:  map[string]string{"/proc/1/cgroup": "0::/kubepods/besteffort/pod1234\n"},
+			want:   PlatformCapabilities{IsContainer: true},
+		},
+		{
+			name:   "wsl",
+			osType: OSLinux,
+			files: map[string]string{
+				"/run/systemd/system":        "",
+				"/proc/sys/kernel/osrelease": "5.15.153.1-microsoft-standard-WSL2\n",
+			},
+			want: PlatformCapabilities{HasSystemd: true, IsWSL: true},
+		},
+		{
+			name:     "macOS",
+			osType:   OSDarwin,
+			commands: []string{"docker"},
+			files:    map[string]string{"/.dockerenv": ""},
+			want:     PlatformCapabilities{HasDocker: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := detectCapabilities(tt.osType, newTestPlatformState(tt.commands, tt.files))
+			if *got != tt.want {
+				t.Errorf("detectCapabilities() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
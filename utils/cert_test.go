@@ -0,0 +1,79 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package utils
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestGenerateSelfSignedCert(t *testing.T) {
+	hosts := []string{"registry.example.lan", "localhost", "127.0.0.1", "::1"}
+
+	certPEM, keyPEM, err := GenerateSelfSignedCert(hosts, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("GenerateSelfSignedCert() error = %v", err)
+	}
+
+	block, _ := pem.Decode(certPEM)
+	if block == nil || block.Type != "CERTIFICATE" {
+		t.Fatalf("certificate is not a PEM block of type CERTIFICATE")
+	}
+	if keyBlock, _ := pem.Decode(keyPEM); keyBlock == nil || keyBlock.Type != "PRIVATE KEY" {
+		t.Fatalf("key is not a PEM block of type PRIVATE KEY")
+	}
+	if _, err := tls.X509KeyPair(certPEM, keyPEM); err != nil {
+		t.Fatalf("certificate and key do not match: %v", err)
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"registry.example.lan", "localhost"}; !slices.Equal(cert.DNSNames, want) {
+		t.Errorf("DNSNames = %v, want %v", cert.DNSNames, want)
+	}
+	if len(cert.IPAddresses) != 2 || cert.IPAddresses[0].String() != "127.0.0.1" || cert.IPAddresses[1].String() != "::1" {
+		t.Errorf("IPAddresses = %v, want [127.0.0.1 ::1]", cert.IPAddresses)
+	}
+	if cert.Subject.CommonName != "registry.example.lan" {
+		t.Errorf("CommonName = %q, want the first host", cert.Subject.CommonName)
+	}
+
+	for _, host := range hosts {
+		if err := cert.VerifyHostname(host); err != nil {
+			t.Errorf("VerifyHostname(%s) error = %v", host, err)
+		}
+	}
+
+	// the certificate is its own CA
+	roots := x509.NewCertPool()
+	roots.AddCert(cert)
+	if _, err := cert.Verify(x509.VerifyOptions{DNSName: "registry.example.lan", Roots: roots}); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package utils
+
+import (
+	"os"
+	"strings"
+)
+
+// ciEnvVars contains the environment variables, which are
+// set by common CI systems
+var ciEnvVars = []string{"CI", "GITHUB_ACTIONS", "GITLAB_CI"}
+
+// IsCI checks if the current process runs inside a CI system,
+// like GitHub Actions or GitLab CI
+func IsCI() bool {
+	return isCIEnv(os.Getenv)
+}
+
+// isCIEnv checks if one of the ciEnvVars is set to a value,
+// which is not "false" or "0"
+func isCIEnv(getenv func(key string) string) bool {
+	for _, key := range ciEnvVars {
+		value := strings.ToLower(strings.TrimSpace(getenv(key)))
+		if value != "" && value != "false" && value != "0" {
+			return true
+		}
+	}
+
+	return false
+}
@@ -22,23 +22,100 @@
 package utils
 
 import (
+	"bytes"
+	"io"
+	"os"
 	"os/exec"
+	"strings"
 )
 
+// cLocaleEnv contains the environment variables, which select the
+// C locale, so messages of commands, like apt, are not translated
+var cLocaleEnv = []string{"LANG=C", "LC_ALL=C"}
+
 // CommandExists checks if a command exists in the system PATH
 func CommandExists(name string) bool {
 	_, err := exec.LookPath(name)
 	return err == nil
 }
 
-// RunCommand runs a command and returns its output and any error
+// CommandVersion runs the version command of a tool, like
+// "git --version", and returns its trimmed standard output
+//
+// Warnings on standard error, like the ones of plugins, are ignored
+func CommandVersion(name string, args ...string) (string, error) {
+	stdout, _, err := RunCommandOutput(name, args...)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(stdout)), nil
+}
+
+// ParsedCommandEnv returns the environment for commands, whose output
+// is parsed, which is the one of this process with the C locale
+// and optional additional KEY=VALUE pairs
+//
+// Commands, whose output is only shown to the user, like the ones
+// of installations, should keep the locale of the user
+func ParsedCommandEnv(extra ...string) []string {
+	env := append(os.Environ(), cLocaleEnv...)
+	return append(env, extra...)
+}
+
+// RunCommand runs a command with the C locale
+// and returns its output and any error
 func RunCommand(name string, args ...string) ([]byte, error) {
 	cmd := exec.Command(name, args...)
+	cmd.Env = ParsedCommandEnv()
 	return cmd.CombinedOutput()
 }
 
+// RunCommandCombinedToWriter runs a command with the C locale, writes
+// its combined output to w while it is running and also returns the
+// complete output, which is parsed by the callers
+func RunCommandCombinedToWriter(w io.Writer, name string, args ...string) ([]byte, error) {
+	var buffer bytes.Buffer
+	output := io.MultiWriter(w, &buffer)
+
+	cmd := exec.Command(name, args...)
+	cmd.Env = ParsedCommandEnv()
+	cmd.Stdout = output
+	cmd.Stderr = output
+
+	err := cmd.Run()
+	return buffer.Bytes(), err
+}
+
+// RunCommandOutput runs a command with the C locale and returns
+// its standard output and standard error separately and any error
+func RunCommandOutput(name string, args ...string) ([]byte, []byte, error) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	cmd := exec.Command(name, args...)
+	cmd.Env = ParsedCommandEnv()
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	return stdout.Bytes(), stderr.Bytes(), err
+}
+
 // RunCommandSilent runs a command without capturing output
 func RunCommandSilent(name string, args ...string) error {
 	cmd := exec.Command(name, args...)
 	return cmd.Run()
 }
+
+// RunCommandSilentCapture runs a command like RunCommandSilent, but
+// returns its trimmed combined output, if it fails, which can be used
+// for error messages, and nil on success
+func RunCommandSilentCapture(name string, args ...string) ([]byte, error) {
+	output, err := RunCommand(name, args...)
+	if err != nil {
+		return bytes.TrimSpace(output), err
+	}
+
+	return nil, nil
+}
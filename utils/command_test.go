@@ -0,0 +1,139 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package utils
+
+import (
+	"bytes"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// skipWithoutShell skips tests, which run commands with sh
+func skipWithoutShell(t *testing.T) {
+	t.Helper()
+
+	if runtime.GOOS == "windows" || !CommandExists("sh") {
+		t.Skip("sh is not available")
+	}
+}
+
+func TestRunCommandCombinedToWriter(t *testing.T) {
+	skipWithoutShell(t)
+
+	var w bytes.Buffer
+	output, err := RunCommandCombinedToWriter(&w, "sh", "-c", "echo out; echo err >&2")
+	if err != nil {
+		t.Fatalf("RunCommandCombinedToWriter() error = %v", err)
+	}
+
+	for _, want := range []string{"out", "err"} {
+		if !strings.Contains(string(output), want) {
+			t.Errorf("returned output %q does not contain %q", output, want)
+		}
+	}
+	if w.String() != string(output) {
+		t.Errorf("writer received %q, want %q", w.String(), output)
+	}
+}
+
+func TestParsedCommandEnv(t *testing.T) {
+	t.Setenv("LC_ALL", "de_DE.UTF-8")
+
+	env := ParsedCommandEnv("GIT_TERMINAL_PROMPT=0")
+
+	// later values win over the ones of this process
+	if i, j := slices.Index(env, "LC_ALL=de_DE.UTF-8"), slices.Index(env, "LC_ALL=C"); j == -1 || j < i {
+		t.Errorf("ParsedCommandEnv() = %q, want LC_ALL=C after the locale of the user", env)
+	}
+	if env[len(env)-1] != "GIT_TERMINAL_PROMPT=0" {
+		t.Errorf("ParsedCommandEnv() = %q, want GIT_TERMINAL_PROMPT=0 at the end", env)
+	}
+}
+
+func TestCommandLocale(t *testing.T) {
+	skipWithoutShell(t)
+	t.Setenv("LC_ALL", "de_DE.UTF-8")
+
+	output, err := RunCommand("sh", "-c", "printf %s \"$LC_ALL\"")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(output) != "C" {
+		t.Errorf("LC_ALL of a parsed command = %q, want %q", output, "C")
+	}
+
+	// captured install commands are parsed, too, like the lock
+	// messages of apt
+	var buf bytes.Buffer
+	output, err = RunCommandCombinedToWriter(&buf, "sh", "-c", "printf %s \"$LC_ALL\"")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(output) != "C" || buf.String() != "C" {
+		t.Errorf("LC_ALL of a captured command = %q (written: %q), want %q", output, buf.String(), "C")
+	}
+}
+
+func TestRunCommandSilentCapture(t *testing.T) {
+	skipWithoutShell(t)
+
+	output, err := RunCommandSilentCapture("sh", "-c", "echo ok")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output != nil {
+		t.Errorf("RunCommandSilentCapture() = %q on success, want nil", output)
+	}
+
+	output, err = RunCommandSilentCapture("sh", "-c", "echo out; echo failed >&2; exit 1")
+	if err == nil {
+		t.Fatal("RunCommandSilentCapture() error = nil, want an error")
+	}
+	if got := string(output); got != "out\nfailed" {
+		t.Errorf("RunCommandSilentCapture() = %q on failure, want %q", got, "out\nfailed")
+	}
+}
+
+func TestRunCommandOutput(t *testing.T) {
+	skipWithoutShell(t)
+
+	stdout, stderr, err := RunCommandOutput("sh", "-c", "echo out; echo err >&2; echo more")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(stdout) != "out\nmore\n" {
+		t.Errorf("stdout = %q, want %q", stdout, "out\nmore\n")
+	}
+	if string(stderr) != "err\n" {
+		t.Errorf("stderr = %q, want %q", stderr, "err\n")
+	}
+
+	stdout, stderr, err = RunCommandOutput("sh", "-c", "echo partial; echo failed >&2; exit 3")
+	if err == nil {
+		t.Fatal("RunCommandOutput() error = nil, want the exit status")
+	}
+	if string(stdout) != "partial\n" || string(stderr) != "failed\n" {
+		t.Errorf("RunCommandOutput() = %q, %q, want %q, %q", stdout, stderr, "partial\n", "failed\n")
+	}
+}
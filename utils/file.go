@@ -0,0 +1,184 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package utils
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// AtomicWriteFile writes data to a file, which is either written
+// completely or left unchanged, even if the process is interrupted
+//
+// The data is written to a temporary file in the same directory
+// first, which replaces the file afterwards
+func AtomicWriteFile(path string, data []byte, perm os.FileMode) error {
+	return atomicWriteFile(path, data, perm, writeTempFile)
+}
+
+// atomicWriteFile is the implementation of AtomicWriteFile, which
+// writes the temporary file with writeTemp
+func atomicWriteFile(path string, data []byte, perm os.FileMode, writeTemp func(file *os.File, data []byte, perm os.FileMode) error) error {
+	tempFile, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tempPath := tempFile.Name()
+
+	if err := writeTemp(tempFile, data, perm); err != nil {
+		os.Remove(tempPath)
+		return err
+	}
+
+	if err := os.Rename(tempPath, path); err != nil {
+		os.Remove(tempPath)
+		return err
+	}
+
+	return nil
+}
+
+// EnsureLine makes sure, that a file contains newLine, by replacing the
+// lines, which start with matchPrefix, or inserting it otherwise, and
+// returns if the file has been changed
+//
+// Leading whitespace and a comment sign are ignored, so a commented
+// default, like "#Port 22" of sshd_config, is replaced in place. A new
+// line is inserted before the first line, which starts with
+// beforePrefix, like a "Match " block of sshd_config, whose settings
+// would not apply globally, or appended, if there is no such line or
+// beforePrefix is empty. The file is written with AtomicWriteFile and
+// keeps its permissions
+func EnsureLine(path string, matchPrefix string, newLine string, beforePrefix string) (bool, error) {
+	perm := os.FileMode(0644)
+
+	content, err := os.ReadFile(path)
+	if err == nil {
+		if info, err := os.Stat(path); err == nil {
+			perm = info.Mode().Perm()
+		}
+	} else if !os.IsNotExist(err) {
+		return false, err
+	}
+
+	newContent, changed := ensureLine(string(content), matchPrefix, newLine, beforePrefix)
+	if !changed {
+		return false, nil
+	}
+
+	if err := AtomicWriteFile(path, []byte(newContent), perm); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// WriteFileIfChanged writes data to a file, but only if the file does
+// not exist or has a different content, and returns if it has been written
+//
+// The data is written with AtomicWriteFile, so an interrupted
+// run does not leave a partial file
+func WriteFileIfChanged(path string, data []byte, perm os.FileMode) (bool, error) {
+	existingData, err := os.ReadFile(path)
+	if err == nil && bytes.Equal(existingData, data) {
+		return false, nil
+	}
+
+	if err := AtomicWriteFile(path, data, perm); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// ensureLine replaces the first line of content, which starts with
+// matchPrefix, with newLine and removes the other ones, or inserts
+// newLine before the first line, which starts with beforePrefix, or
+// at the end, keeping the line endings
+func ensureLine(content string, matchPrefix string, newLine string, beforePrefix string) (string, bool) {
+	eol := "\n"
+	if strings.Contains(content, "\r\n") {
+		eol = "\r\n"
+	}
+	lineWithEOL := newLine
+	if eol == "\r\n" {
+		lineWithEOL += "\r"
+	}
+
+	lines := strings.Split(content, "\n")
+	newLines := make([]string, 0, len(lines)+1)
+
+	replaced := false
+	insertAt := -1
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(strings.TrimSuffix(line, "\r"))
+
+		if strings.HasPrefix(strings.TrimLeft(trimmed, "#"), matchPrefix) {
+			// only the first matching line is kept, so
+			// newLine is not contained multiple times
+			if !replaced {
+				newLines = append(newLines, lineWithEOL)
+				replaced = true
+			}
+			continue
+		}
+
+		if insertAt == -1 && beforePrefix != "" && strings.HasPrefix(trimmed, beforePrefix) {
+			insertAt = len(newLines)
+		}
+		newLines = append(newLines, line)
+	}
+
+	if !replaced {
+		if insertAt == -1 {
+			if content != "" && !strings.HasSuffix(content, "\n") {
+				content += eol
+			}
+			return content + newLine + eol, true
+		}
+
+		newLines = slices.Insert(newLines, insertAt, lineWithEOL)
+	}
+
+	newContent := strings.Join(newLines, "\n")
+	return newContent, newContent != content
+}
+
+// writeTempFile writes data to a temporary file, flushes it
+// to the disk and closes it
+func writeTempFile(file *os.File, data []byte, perm os.FileMode) error {
+	_, err := file.Write(data)
+	if err == nil {
+		err = file.Chmod(perm)
+	}
+	if err == nil {
+		err = file.Sync()
+	}
+
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,210 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package utils
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFileIfChanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+
+	written, err := WriteFileIfChanged(path, []byte("a=1\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !written {
+		t.Error("WriteFileIfChanged() = false for a new file, want true")
+	}
+
+	written, err = WriteFileIfChanged(path, []byte("a=1\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if written {
+		t.Error("WriteFileIfChanged() = true for the same content, want false")
+	}
+
+	written, err = WriteFileIfChanged(path, []byte("a=2\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !written {
+		t.Error("WriteFileIfChanged() = false for a different content, want true")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "a=2\n" {
+		t.Errorf("content = %q, want %q", data, "a=2\n")
+	}
+}
+
+func TestAtomicWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sshd_config")
+
+	if err := os.WriteFile(path, []byte("Port 22\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := AtomicWriteFile(path, []byte("Port 2222\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "Port 2222\n" {
+		t.Errorf("content = %q, want %q", data, "Port 2222\n")
+	}
+
+	assertNoTempFiles(t, dir)
+}
+
+func TestAtomicWriteFileMidWriteFailure(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sshd_config")
+
+	if err := os.WriteFile(path, []byte("Port 22\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	errInterrupted := errors.New("interrupted")
+	writeHalf := func(file *os.File, data []byte, perm os.FileMode) error {
+		file.Write(data[:len(data)/2])
+		file.Close()
+		return errInterrupted
+	}
+
+	err := atomicWriteFile(path, []byte("Port 2222\nPermitRootLogin no\n"), 0644, writeHalf)
+	if !errors.Is(err, errInterrupted) {
+		t.Fatalf("atomicWriteFile() error = %v, want %v", err, errInterrupted)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "Port 22\n" {
+		t.Errorf("content = %q, want the unchanged %q", data, "Port 22\n")
+	}
+
+	assertNoTempFiles(t, dir)
+}
+
+// assertNoTempFiles checks, that AtomicWriteFile
+// left no temporary files in dir
+func assertNoTempFiles(t *testing.T, dir string) {
+	t.Helper()
+
+	tempFiles, err := filepath.Glob(filepath.Join(dir, "*.tmp"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tempFiles) > 0 {
+		t.Errorf("temporary files left: %q", tempFiles)
+	}
+}
+
+func TestEnsureLine(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		want        string
+		wantChanged bool
+	}{
+		{
+			name:        "update commented default",
+			content:     "Include /etc/ssh/sshd_config.d/*.conf\n#Port 22\nPermitRootLogin no\n",
+			want:        "Include /etc/ssh/sshd_config.d/*.conf\nPort 2222\nPermitRootLogin no\n",
+			wantChanged: true,
+		},
+		{
+			name:        "update every port line",
+			content:     "#Port 22\nPort 2200\nPermitRootLogin no\n",
+			want:        "Port 2222\nPermitRootLogin no\n",
+			wantChanged: true,
+		},
+		{
+			name:        "append",
+			content:     "PermitRootLogin no",
+			want:        "PermitRootLogin no\nPort 2222\n",
+			wantChanged: true,
+		},
+		{
+			name:        "append with CRLF",
+			content:     "PermitRootLogin no\r\n",
+			want:        "PermitRootLogin no\r\nPort 2222\r\n",
+			wantChanged: true,
+		},
+		{
+			name:        "no change",
+			content:     "Port 2222\nPermitRootLogin no\n",
+			want:        "Port 2222\nPermitRootLogin no\n",
+			wantChanged: false,
+		},
+		{
+			name:        "trailing match block",
+			content:     "PermitRootLogin no\n#Match User anoncvs\nMatch User git\n    X11Forwarding no\n",
+			want:        "PermitRootLogin no\n#Match User anoncvs\nPort 2222\nMatch User git\n    X11Forwarding no\n",
+			wantChanged: true,
+		},
+		{
+			name:        "trailing match block with CRLF",
+			content:     "PermitRootLogin no\r\nMatch User git\r\n    X11Forwarding no\r\n",
+			want:        "PermitRootLogin no\r\nPort 2222\r\nMatch User git\r\n    X11Forwarding no\r\n",
+			wantChanged: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "sshd_config")
+			if err := os.WriteFile(path, []byte(tt.content), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			changed, err := EnsureLine(path, "Port ", "Port 2222", "Match ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if changed != tt.wantChanged {
+				t.Errorf("EnsureLine() = %v, want %v", changed, tt.wantChanged)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("content = %q, want %q", data, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// HostsEntryMarker is the comment, which marks the entries
+// of the hosts file, that have been added by autark
+const HostsEntryMarker = "# added by autark"
+
+// HostsFilePath returns the path of the hosts file of the system
+func HostsFilePath() string {
+	if runtime.GOOS == "windows" {
+		systemRoot := os.Getenv("SystemRoot")
+		if systemRoot == "" {
+			systemRoot = `C:\Windows`
+		}
+
+		return filepath.Join(systemRoot, "System32", "drivers", "etc", "hosts")
+	}
+
+	return "/etc/hosts"
+}
+
+// RemoveHostsEntry removes the entries for hostname from the content
+// of a hosts file, which have been added by SetHostsEntry, and
+// returns the new content and if it has been changed
+//
+// Entries, which have not been added by autark, are kept
+func RemoveHostsEntry(content string, hostname string) (string, bool) {
+	lines := strings.SplitAfter(content, "\n")
+	kept := make([]string, 0, len(lines))
+
+	for _, line := range lines {
+		if strings.HasSuffix(strings.TrimSpace(line), HostsEntryMarker) && slices.Contains(getHostsEntryNames(line), hostname) {
+			continue
+		}
+
+		kept = append(kept, line)
+	}
+
+	if len(kept) == len(lines) {
+		return content, false
+	}
+	return strings.Join(kept, ""), true
+}
+
+// SetHostsEntry returns the content of a hosts file, where hostname
+// maps to ip, and if it has been changed
+//
+// Entries for hostname, which have been added by autark before, are
+// replaced, so running it again with the same values changes nothing
+func SetHostsEntry(content string, hostname string, ip string) (string, bool) {
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) > 1 && fields[0] == ip && slices.Contains(getHostsEntryNames(line), hostname) {
+			return content, false // already mapped
+		}
+	}
+
+	newContent, _ := RemoveHostsEntry(content, hostname)
+	if newContent != "" && !strings.HasSuffix(newContent, "\n") {
+		newContent += "\n"
+	}
+
+	return newContent + ip + "\t" + hostname + "\t" + HostsEntryMarker + "\n", true
+}
+
+// getHostsEntryNames returns the hostnames of a line of a hosts file
+func getHostsEntryNames(line string) []string {
+	line, _, _ = strings.Cut(line, "#")
+
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return nil
+	}
+
+	return fields[1:]
+}
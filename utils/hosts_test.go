@@ -0,0 +1,98 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package utils
+
+import "testing"
+
+const testHostsContent = "127.0.0.1\tlocalhost\n::1\tlocalhost ip6-localhost\n"
+
+func TestSetHostsEntry(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		ip          string
+		want        string
+		wantChanged bool
+	}{
+		{
+			name:        "add",
+			content:     testHostsContent,
+			ip:          "127.0.1.1",
+			want:        testHostsContent + "127.0.1.1\tmyhost\t" + HostsEntryMarker + "\n",
+			wantChanged: true,
+		},
+		{
+			name:        "add without trailing newline",
+			content:     "127.0.0.1\tlocalhost",
+			ip:          "127.0.1.1",
+			want:        "127.0.0.1\tlocalhost\n127.0.1.1\tmyhost\t" + HostsEntryMarker + "\n",
+			wantChanged: true,
+		},
+		{
+			name:        "already mapped by the user",
+			content:     testHostsContent + "127.0.1.1 myhost.example.com myhost\n",
+			ip:          "127.0.1.1",
+			want:        testHostsContent + "127.0.1.1 myhost.example.com myhost\n",
+			wantChanged: false,
+		},
+		{
+			name:        "replace own entry",
+			content:     testHostsContent + "127.0.1.1\tmyhost\t" + HostsEntryMarker + "\n",
+			ip:          "127.0.0.1",
+			want:        testHostsContent + "127.0.0.1\tmyhost\t" + HostsEntryMarker + "\n",
+			wantChanged: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed := SetHostsEntry(tt.content, "myhost", tt.ip)
+			if got != tt.want || changed != tt.wantChanged {
+				t.Errorf("SetHostsEntry() = %q, %v, want %q, %v", got, changed, tt.want, tt.wantChanged)
+			}
+
+			// running it again changes nothing
+			again, changed := SetHostsEntry(got, "myhost", tt.ip)
+			if again != got || changed {
+				t.Errorf("SetHostsEntry() again = %q, %v, want %q, false", again, changed, got)
+			}
+		})
+	}
+}
+
+func TestRemoveHostsEntry(t *testing.T) {
+	content := testHostsContent +
+		"127.0.1.1\tmyhost\t" + HostsEntryMarker + "\n" +
+		"10.0.0.5\tmyhost-db\n"
+
+	got, changed := RemoveHostsEntry(content, "myhost")
+	if want := testHostsContent + "10.0.0.5\tmyhost-db\n"; got != want || !changed {
+		t.Errorf("RemoveHostsEntry() = %q, %v, want %q, true", got, changed, want)
+	}
+
+	// entries of the user are kept
+	userContent := testHostsContent + "127.0.1.1\tmyhost\n"
+	got, changed = RemoveHostsEntry(userContent, "myhost")
+	if got != userContent || changed {
+		t.Errorf("RemoveHostsEntry() = %q, %v, want %q, false", got, changed, userContent)
+	}
+}
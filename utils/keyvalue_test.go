@@ -0,0 +1,74 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package utils
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestParseKeyValuePairs(t *testing.T) {
+	tests := []struct {
+		name    string
+		pairs   []string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name:  "empty",
+			pairs: nil,
+			want:  map[string]string{},
+		},
+		{
+			name:  "value with equal signs",
+			pairs: []string{"REGISTRY_HTTP_SECRET=a=b=c", "EMPTY="},
+			want:  map[string]string{"REGISTRY_HTTP_SECRET": "a=b=c", "EMPTY": ""},
+		},
+		{
+			name:  "later pairs override earlier ones",
+			pairs: []string{"LEVEL=info", " LEVEL =debug"},
+			want:  map[string]string{"LEVEL": "debug"},
+		},
+		{
+			name:    "missing equal sign",
+			pairs:   []string{"LEVEL"},
+			wantErr: true,
+		},
+		{
+			name:    "empty key",
+			pairs:   []string{" =value"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseKeyValuePairs(tt.pairs)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseKeyValuePairs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !maps.Equal(got, tt.want) {
+				t.Errorf("ParseKeyValuePairs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// FileLock is an advisory lock, which is held as long
+// as its lock file exists
+type FileLock struct {
+	path string
+}
+
+// LockHeldError is returned by AcquireFileLock if another
+// process already holds a lock
+type LockHeldError struct {
+	// Path is the path of the lock file
+	Path string
+	// PID is the ID of the process holding the lock
+	PID int
+}
+
+func (e *LockHeldError) Error() string {
+	return fmt.Sprintf("lock %s is held by process %d", e.Path, e.PID)
+}
+
+// AcquireFileLock tries to acquire the lock of a specific lock file
+// and fails with a *LockHeldError if it is held by a running process
+//
+// Lock files of processes, which are not running anymore, are replaced
+func AcquireFileLock(path string) (*FileLock, error) {
+	// the lock file is written completely under a temporary name and
+	// linked into place, so other processes never see it without PID
+	tempFile, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return nil, err
+	}
+	tempPath := tempFile.Name()
+	defer os.Remove(tempPath)
+
+	_, err = tempFile.WriteString(strconv.Itoa(os.Getpid()))
+	if err == nil {
+		err = tempFile.Chmod(0644)
+	}
+	if closeErr := tempFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for attempt := 0; attempt < 2; attempt++ {
+		err := os.Link(tempPath, path)
+		if err == nil {
+			return &FileLock{path: path}, nil
+		}
+
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue // released in the meantime
+			}
+			return nil, err
+		}
+
+		pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+		if err == nil && isProcessRunning(pid) {
+			return nil, &LockHeldError{Path: path, PID: pid}
+		}
+
+		// stale lock file
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+
+	return nil, fmt.Errorf("could not acquire lock %s", path)
+}
+
+// Path returns the path of the lock file
+func (l *FileLock) Path() string {
+	return l.path
+}
+
+// Release releases the lock by removing its lock file
+func (l *FileLock) Release() error {
+	err := os.Remove(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	return err
+}
+
+func isProcessRunning(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+
+	if runtime.GOOS == "windows" {
+		// FindProcess fails on Windows if the process does not exist
+		return true
+	}
+
+	err = process.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, os.ErrPermission)
+}
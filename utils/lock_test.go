@@ -0,0 +1,132 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package utils
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestAcquireFileLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "autark.lock")
+
+	lock, err := AcquireFileLock(path)
+	if err != nil {
+		t.Fatalf("AcquireFileLock() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != strconv.Itoa(os.Getpid()) {
+		t.Errorf("lock file contains %q, want the PID %d", data, os.Getpid())
+	}
+
+	var heldErr *LockHeldError
+	if _, err := AcquireFileLock(path); !errors.As(err, &heldErr) {
+		t.Fatalf("second AcquireFileLock() error = %v, want a *LockHeldError", err)
+	}
+	if heldErr.PID != os.Getpid() {
+		t.Errorf("LockHeldError.PID = %d, want %d", heldErr.PID, os.Getpid())
+	}
+
+	if err := lock.Release(); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+
+	lock, err = AcquireFileLock(path)
+	if err != nil {
+		t.Fatalf("AcquireFileLock() after Release() error = %v", err)
+	}
+	lock.Release()
+}
+
+func TestAcquireFileLockConcurrently(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "autark.lock")
+
+	for round := 0; round < 50; round++ {
+		var wg sync.WaitGroup
+		locks := make(chan *FileLock, 2)
+		errs := make(chan error, 2)
+
+		for i := 0; i < 2; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				lock, err := AcquireFileLock(path)
+				if err != nil {
+					errs <- err
+					return
+				}
+				locks <- lock
+			}()
+		}
+		wg.Wait()
+		close(locks)
+		close(errs)
+
+		if len(locks) != 1 {
+			t.Fatalf("round %d: %d goroutines acquired the lock, want 1", round, len(locks))
+		}
+		for err := range errs {
+			var heldErr *LockHeldError
+			if !errors.As(err, &heldErr) {
+				t.Fatalf("round %d: AcquireFileLock() error = %v, want a *LockHeldError", round, err)
+			}
+		}
+
+		for lock := range locks {
+			if err := lock.Release(); err != nil {
+				t.Fatalf("Release() error = %v", err)
+			}
+		}
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("%d files are left in the lock directory, want none", len(entries))
+	}
+}
+
+func TestAcquireFileLockReplacesStaleLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "autark.lock")
+
+	// a PID, which is not in use
+	if err := os.WriteFile(path, []byte("999999999"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	lock, err := AcquireFileLock(path)
+	if err != nil {
+		t.Fatalf("AcquireFileLock() error = %v", err)
+	}
+	lock.Release()
+}
@@ -0,0 +1,187 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package utils
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// NetworkInterface contains the information of a network
+// interface, which is required to select the primary IP
+type NetworkInterface struct {
+	// Name is the name, like "eth0"
+	Name string
+	// Flags contains the flags, like net.FlagUp
+	Flags net.Flags
+	// IPs contains the IP addresses
+	IPs []net.IP
+}
+
+// virtualInterfacePrefixes contains the prefixes of the names of
+// virtual interfaces of container runtimes, hypervisors and VPNs,
+// which are not reachable by other hosts of the network
+var virtualInterfacePrefixes = []string{
+	"br-", "cali", "cni", "docker", "flannel", "podman", "tailscale", "tap",
+	"tun", "utun", "vboxnet", "veth", "virbr", "vEthernet", "vmnet", "wg", "zt",
+}
+
+// DetectHostIP detects the IP address of the interface,
+// which is used for outgoing traffic
+//
+// No data is sent, the UDP "connection" only selects a route
+func DetectHostIP() (net.IP, error) {
+	conn, err := net.Dial("udp", "192.0.2.1:80")
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	addr, ok := conn.LocalAddr().(*net.UDPAddr)
+	if !ok || addr.IP.IsLoopback() {
+		return nil, fmt.Errorf("no non-loopback IP address found")
+	}
+
+	return addr.IP, nil
+}
+
+// DetectPrimaryIPv4 detects the primary IPv4 address of this host,
+// which is the one used for outgoing traffic, if it does not belong
+// to a virtual interface, like docker0 or a VPN, or the first one
+// of a physical interface otherwise
+func DetectPrimaryIPv4() (net.IP, error) {
+	interfaces, err := ListNetworkInterfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	routeIP, _ := DetectHostIP()
+
+	ip := SelectPrimaryIPv4(interfaces, routeIP)
+	if ip == nil {
+		return nil, fmt.Errorf("no IPv4 address found on a physical network interface")
+	}
+
+	return ip, nil
+}
+
+// InterfaceIPv4 returns the first IPv4 address of a specific
+// network interface
+func InterfaceIPv4(name string) (net.IP, error) {
+	interfaces, err := ListNetworkInterfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, iface := range interfaces {
+		if iface.Name != name {
+			continue
+		}
+
+		for _, ip := range iface.IPs {
+			if ip4 := ip.To4(); ip4 != nil {
+				return ip4, nil
+			}
+		}
+		return nil, fmt.Errorf("network interface %s has no IPv4 address", name)
+	}
+
+	return nil, fmt.Errorf("network interface %s not found", name)
+}
+
+// IsVirtualInterface checks if the name of a network interface
+// belongs to a virtual one, like "docker0" or "veth1234"
+func IsVirtualInterface(name string) bool {
+	for _, prefix := range virtualInterfacePrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ListNetworkInterfaces returns all network interfaces
+// of this host with their IP addresses
+func ListNetworkInterfaces() ([]NetworkInterface, error) {
+	netInterfaces, err := net.Interfaces()
+	if err != nil {
+		return nil, fmt.Errorf("could not list network interfaces: %w", err)
+	}
+
+	interfaces := make([]NetworkInterface, 0, len(netInterfaces))
+	for _, netInterface := range netInterfaces {
+		iface := NetworkInterface{
+			Name:  netInterface.Name,
+			Flags: netInterface.Flags,
+		}
+
+		addrs, err := netInterface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			if ipNet, ok := addr.(*net.IPNet); ok {
+				iface.IPs = append(iface.IPs, ipNet.IP)
+			}
+		}
+
+		interfaces = append(interfaces, iface)
+	}
+
+	return interfaces, nil
+}
+
+// SelectPrimaryIPv4 selects the primary IPv4 address from a list of
+// network interfaces, where loopback, down and virtual interfaces
+// are skipped
+//
+// preferred, like the IP used for outgoing traffic, is returned, if it
+// belongs to one of the remaining interfaces, otherwise their first
+// global unicast IPv4 address, or nil if there is none
+func SelectPrimaryIPv4(interfaces []NetworkInterface, preferred net.IP) net.IP {
+	candidates := make([]net.IP, 0)
+
+	for _, iface := range interfaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 || IsVirtualInterface(iface.Name) {
+			continue
+		}
+
+		for _, ip := range iface.IPs {
+			ip4 := ip.To4()
+			if ip4 == nil || !ip4.IsGlobalUnicast() {
+				continue
+			}
+
+			if preferred != nil && ip4.Equal(preferred) {
+				return ip4
+			}
+			candidates = append(candidates, ip4)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return nil
+	}
+	return candidates[0]
+}
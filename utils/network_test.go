@@ -0,0 +1,84 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package utils
+
+import (
+	"net"
+	"testing"
+)
+
+func TestIsVirtualInterface(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{name: "docker0", want: true},
+		{name: "veth1a2b3c", want: true},
+		{name: "br-0123456789ab", want: true},
+		{name: "vEthernet (WSL)", want: true},
+		{name: "wg0", want: true},
+		{name: "eth0", want: false},
+		{name: "enp3s0", want: false},
+		{name: "wlan0", want: false},
+		{name: "en0", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := IsVirtualInterface(tt.name); got != tt.want {
+			t.Errorf("IsVirtualInterface(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSelectPrimaryIPv4(t *testing.T) {
+	up := net.FlagUp | net.FlagBroadcast
+	interfaces := []NetworkInterface{
+		{Name: "lo", Flags: net.FlagUp | net.FlagLoopback, IPs: []net.IP{net.ParseIP("127.0.0.1")}},
+		{Name: "docker0", Flags: up, IPs: []net.IP{net.ParseIP("172.17.0.1")}},
+		{Name: "eth1", Flags: net.FlagBroadcast, IPs: []net.IP{net.ParseIP("10.0.0.9")}},
+		{Name: "eth0", Flags: up, IPs: []net.IP{net.ParseIP("fe80::1"), net.ParseIP("169.254.10.1"), net.ParseIP("192.168.1.20")}},
+		{Name: "wlan0", Flags: up, IPs: []net.IP{net.ParseIP("192.168.2.30")}},
+	}
+
+	tests := []struct {
+		name      string
+		preferred net.IP
+		want      net.IP
+	}{
+		{name: "first candidate", preferred: nil, want: net.ParseIP("192.168.1.20")},
+		{name: "preferred", preferred: net.ParseIP("192.168.2.30"), want: net.ParseIP("192.168.2.30")},
+		{name: "preferred of a virtual interface", preferred: net.ParseIP("172.17.0.1"), want: net.ParseIP("192.168.1.20")},
+		{name: "preferred of a down interface", preferred: net.ParseIP("10.0.0.9"), want: net.ParseIP("192.168.1.20")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SelectPrimaryIPv4(interfaces, tt.preferred); !got.Equal(tt.want) {
+				t.Errorf("SelectPrimaryIPv4() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got := SelectPrimaryIPv4(interfaces[:3], nil); got != nil {
+		t.Errorf("SelectPrimaryIPv4() = %v, want nil without candidates", got)
+	}
+}
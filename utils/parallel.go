@@ -0,0 +1,52 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package utils
+
+import "sync"
+
+// RunParallel calls fn for each index from 0 to count-1, with at most
+// concurrency calls at the same time, and returns their errors by index
+func RunParallel(count int, concurrency int, fn func(i int) error) []error {
+	errs := make([]error, count)
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for range min(concurrency, count) {
+		wg.Go(func() {
+			for i := range jobs {
+				errs[i] = fn(i)
+			}
+		})
+	}
+
+	for i := range count {
+		jobs <- i
+	}
+	close(jobs)
+
+	wg.Wait()
+	return errs
+}
@@ -0,0 +1,83 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package utils
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRunParallel(t *testing.T) {
+	const count = 10
+	const concurrency = 3
+
+	var mu sync.Mutex
+	running := 0
+	maxRunning := 0
+	called := make([]bool, count)
+
+	errs := RunParallel(count, concurrency, func(i int) error {
+		mu.Lock()
+		running++
+		maxRunning = max(maxRunning, running)
+		called[i] = true
+		mu.Unlock()
+
+		time.Sleep(5 * time.Millisecond)
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+
+		if i%2 == 1 {
+			return fmt.Errorf("error %d", i)
+		}
+		return nil
+	})
+
+	if len(errs) != count {
+		t.Fatalf("RunParallel() returned %d errors, want %d", len(errs), count)
+	}
+	for i, err := range errs {
+		if !called[i] {
+			t.Errorf("fn(%d) was not called", i)
+		}
+		if wantErr := i%2 == 1; (err != nil) != wantErr {
+			t.Errorf("errs[%d] = %v, want error: %v", i, err, wantErr)
+		}
+	}
+	if maxRunning > concurrency {
+		t.Errorf("%d calls at the same time, want at most %d", maxRunning, concurrency)
+	}
+}
+
+func TestRunParallelWithoutConcurrency(t *testing.T) {
+	errs := RunParallel(2, 0, func(i int) error {
+		return nil
+	})
+
+	if len(errs) != 2 || errs[0] != nil || errs[1] != nil {
+		t.Errorf("RunParallel() = %v, want 2 nil errors", errs)
+	}
+}
@@ -0,0 +1,60 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package utils
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ExpandPath expands a leading "~" to the home directory of the current user
+// and $VAR / ${VAR} with the values of environment variables
+//
+// Undefined environment variables result in an error instead
+// of an empty string
+func ExpandPath(p string) (string, error) {
+	if p == "~" || strings.HasPrefix(p, "~/") || strings.HasPrefix(p, `~\`) {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("could not expand ~: %w", err)
+		}
+
+		p = homeDir + p[1:]
+	}
+
+	undefinedVars := make([]string, 0)
+	expanded := os.Expand(p, func(name string) string {
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			undefinedVars = append(undefinedVars, name)
+		}
+
+		return value
+	})
+
+	if len(undefinedVars) > 0 {
+		return "", fmt.Errorf("undefined environment variable(s) in path %s: %s", p, strings.Join(undefinedVars, ", "))
+	}
+
+	return expanded, nil
+}
@@ -0,0 +1,68 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package utils
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExpandPath(t *testing.T) {
+	homeDir := t.TempDir()
+	t.Setenv("HOME", homeDir)
+	t.Setenv("USERPROFILE", homeDir)
+	t.Setenv("AUTARK_TEST_DIR", "/srv/autark")
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "~", want: homeDir},
+		{path: "~/compose.yaml", want: homeDir + "/compose.yaml"},
+		{path: "$HOME/compose.yaml", want: homeDir + "/compose.yaml"},
+		{path: "${AUTARK_TEST_DIR}/compose.yaml", want: "/srv/autark/compose.yaml"},
+		{path: "./compose.yaml", want: "./compose.yaml"},
+		{path: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		got, err := ExpandPath(tt.path)
+		if err != nil {
+			t.Errorf("ExpandPath(%q) error = %v", tt.path, err)
+			continue
+		}
+		if filepath.ToSlash(got) != filepath.ToSlash(tt.want) {
+			t.Errorf("ExpandPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestExpandPathUndefinedVariable(t *testing.T) {
+	_, err := ExpandPath("$AUTARK_UNDEFINED_TEST_VAR/compose.yaml")
+	if err == nil {
+		t.Fatal("ExpandPath() error = nil, want an error for an undefined variable")
+	}
+	if !strings.Contains(err.Error(), "AUTARK_UNDEFINED_TEST_VAR") {
+		t.Errorf("error %q does not name the undefined variable", err.Error())
+	}
+}
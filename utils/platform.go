@@ -23,10 +23,18 @@ package utils
 
 import (
 	"bufio"
+	"context"
+	"fmt"
 	"os"
 	"os/exec"
+	"regexp"
 	"runtime"
+	"runtime/debug"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 // OSType represents the operating system type
@@ -57,6 +65,44 @@ const (
 	DistroUnknown  LinuxDistro = "unknown"
 )
 
+// netSessionTimeout is the maximum time of the "net session"
+// fallback of the Windows admin check
+const netSessionTimeout = 5 * time.Second
+
+// osReleasePath is the path of the file, which describes
+// the Linux distribution
+const osReleasePath = "/etc/os-release"
+
+// cpuInfoPath is the path of the file, which
+// describes the CPUs on Linux
+const cpuInfoPath = "/proc/cpuinfo"
+
+// armModelNameRegex matches the version of the "model name" of
+// /proc/cpuinfo on 32-bit ARM, like "ARMv6-compatible processor rev 7 (v6l)"
+var armModelNameRegex = regexp.MustCompile(`(?i)^armv(\d+)`)
+
+// ostreeBootedPath is the path of the file, which exists
+// on systems booted from an ostree deployment
+const ostreeBootedPath = "/run/ostree-booted"
+
+// fedoraOSTreeVariants contains the VARIANT_IDs of the immutable
+// Fedora variants, which are based on rpm-ostree
+var fedoraOSTreeVariants = []string{"silverblue", "kinoite", "sericea", "onyx", "coreos", "iot"}
+
+// KnownLinuxDistros contains all Linux distributions, which can be detected
+var KnownLinuxDistros = []LinuxDistro{
+	DistroDebian,
+	DistroUbuntu,
+	DistroFedora,
+	DistroRHEL,
+	DistroCentOS,
+	DistroArch,
+	DistroAlpine,
+	DistroOpenSUSE,
+	DistroGentoo,
+	DistroVoid,
+}
+
 // PackageManager represents the package manager type
 type PackageManager string
 
@@ -78,13 +124,64 @@ const (
 	PkgMgrUnknown     PackageManager = "unknown"
 )
 
+// KnownPackageManagers contains all package managers, which can be detected
+var KnownPackageManagers = []PackageManager{
+	PkgMgrApt,
+	PkgMgrDnf,
+	PkgMgrPacman,
+	PkgMgrApk,
+	PkgMgrZypper,
+	PkgMgrEmerge,
+	PkgMgrXbpsInstall,
+	PkgMgrSnap,
+	PkgMgrFlatpak,
+	PkgMgrBrew,
+	PkgMgrPort,
+	PkgMgrPkg,
+	PkgMgrChoco,
+	PkgMgrWinget,
+}
+
 // PlatformInfo contains information about the current platform
 type PlatformInfo struct {
-	OS             OSType
-	Arch           string
-	LinuxDistro    LinuxDistro
-	LinuxDistroID  string
-	PackageManager PackageManager
+	OS            OSType      `json:"os"`
+	Arch          string      `json:"arch"`
+	LinuxDistro   LinuxDistro `json:"linux_distro"`
+	LinuxDistroID string      `json:"linux_distro_id"`
+	// LinuxVariantID is the VARIANT_ID of /etc/os-release,
+	// like "microos" or "silverblue", which is optional
+	LinuxVariantID string         `json:"linux_variant_id"`
+	PackageManager PackageManager `json:"package_manager"`
+	// ARMVariant is the version of a 32-bit ARM CPU, like "v6"
+	// or "v7", which is empty on other architectures
+	ARMVariant string `json:"arm_variant,omitempty"`
+	// Detected indicates if the platform could be detected completely,
+	// which is false on Linux, if /etc/os-release is missing, like in
+	// distroless or scratch containers, or contains an unknown distro
+	Detected bool `json:"detected"`
+
+	capabilities     *PlatformCapabilities
+	capabilitiesOnce sync.Once
+}
+
+// detectARMVariant detects the version of a 32-bit ARM CPU from
+// /proc/cpuinfo or, as fallback, from the GOARM setting of the build
+func (p *PlatformInfo) detectARMVariant() {
+	if data, err := os.ReadFile(cpuInfoPath); err == nil {
+		p.ARMVariant = parseCPUInfoARMVariant(string(data))
+	}
+
+	if p.ARMVariant == "" {
+		if info, ok := debug.ReadBuildInfo(); ok {
+			for _, setting := range info.Settings {
+				if setting.Key == "GOARM" && setting.Value != "" {
+					// like "7" or "6,softfloat"
+					version, _, _ := strings.Cut(setting.Value, ",")
+					p.ARMVariant = "v" + version
+				}
+			}
+		}
+	}
 }
 
 func (p *PlatformInfo) detectBSDPackageManager() {
@@ -101,13 +198,14 @@ func (p *PlatformInfo) detectDarwinPackageManager() {
 	}
 }
 
-func (p *PlatformInfo) detectLinuxDistro() {
-	osRelease, err := parseOSRelease("/etc/os-release")
+func (p *PlatformInfo) detectLinuxDistro(path string) {
+	osRelease, err := parseOSRelease(path)
 	if err != nil {
 		return
 	}
 
 	p.LinuxDistroID = osRelease["ID"]
+	p.LinuxVariantID = osRelease["VARIANT_ID"]
 	idLike := osRelease["ID_LIKE"]
 
 	switch p.LinuxDistroID {
@@ -142,6 +240,8 @@ func (p *PlatformInfo) detectLinuxDistro() {
 			p.LinuxDistro = DistroOpenSUSE
 		}
 	}
+
+	p.Detected = p.LinuxDistro != DistroUnknown
 }
 
 func (p *PlatformInfo) detectLinuxPackageManager() {
@@ -216,16 +316,22 @@ func DetectPlatform() *PlatformInfo {
 	switch runtime.GOOS {
 	case "linux":
 		info.OS = OSLinux
-		info.detectLinuxDistro()
+		info.detectLinuxDistro(osReleasePath)
 		info.detectLinuxPackageManager()
+		if info.Arch == "arm" {
+			info.detectARMVariant()
+		}
 	case "darwin":
 		info.OS = OSDarwin
+		info.Detected = true
 		info.detectDarwinPackageManager()
 	case "windows":
 		info.OS = OSWindows
+		info.Detected = true
 		info.detectWindowsPackageManager()
 	case "freebsd", "netbsd", "openbsd", "dragonfly":
 		info.OS = OSFreeBSD
+		info.Detected = true
 		info.detectBSDPackageManager()
 	}
 
@@ -240,6 +346,41 @@ func (p *PlatformInfo) detectWindowsPackageManager() {
 	}
 }
 
+// DebianArch returns the architecture of the platform as used by
+// Debian packages and apt repositories, like "amd64" or "armhf"
+func (p *PlatformInfo) DebianArch() string {
+	return getDebianArch(p.Arch, p.ARMVariant, p.LinuxDistroID)
+}
+
+// IsFedoraOSTree checks if the platform is an immutable Fedora variant,
+// like Silverblue or CoreOS, where packages have to be layered
+// with rpm-ostree
+func (p *PlatformInfo) IsFedoraOSTree() bool {
+	return isFedoraOSTree(p.LinuxDistro, p.LinuxVariantID, fileExists(ostreeBootedPath))
+}
+
+// IsMicroOS checks if the platform is an immutable openSUSE MicroOS
+// or SLE Micro, where packages have to be installed into a new
+// snapshot with transactional-update
+func (p *PlatformInfo) IsMicroOS() bool {
+	return isMicroOS(p.LinuxDistroID, p.LinuxVariantID)
+}
+
+// String returns a readable summary of the platform,
+// like "linux/amd64 (distro: ubuntu, package manager: apt)"
+func (p *PlatformInfo) String() string {
+	if p.OS != OSLinux {
+		return fmt.Sprintf("%s/%s (package manager: %s)", p.OS, p.Arch, p.PackageManager)
+	}
+
+	distro := string(p.LinuxDistro)
+	if p.LinuxDistroID != "" && p.LinuxDistroID != distro {
+		distro = fmt.Sprintf("%s [%s]", distro, p.LinuxDistroID)
+	}
+
+	return fmt.Sprintf("%s/%s (distro: %s, package manager: %s)", p.OS, p.Arch, distro, p.PackageManager)
+}
+
 // IsRoot checks if the current process has root/administrator privileges
 func IsRoot() bool {
 	switch runtime.GOOS {
@@ -251,13 +392,144 @@ func IsRoot() bool {
 	}
 }
 
+// PrivilegeEscalationCommand returns the name of the command that can be used
+// to run other commands with root privileges, preferring sudo over doas,
+// or an empty string if none of them is available
+func PrivilegeEscalationCommand() string {
+	return findPrivilegeEscalationCommand(CommandExists)
+}
+
+// RerunWithPrivileges runs the current executable again with the same
+// arguments via a privilege escalation command, like sudo or doas,
+// and returns its exit code
+func RerunWithPrivileges(escalationCmd string) (int, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return 1, err
+	}
+
+	args := append([]string{executable}, os.Args[1:]...)
+
+	cmd := exec.Command(escalationCmd, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return exitErr.ExitCode(), nil
+		}
+		return 1, err
+	}
+
+	return 0, nil
+}
+
+func findPrivilegeEscalationCommand(commandExists func(name string) bool) string {
+	for _, name := range []string{"sudo", "doas"} {
+		if commandExists(name) {
+			return name
+		}
+	}
+
+	return ""
+}
+
+// getDebianArch returns the Debian architecture of a Go architecture
+//
+// 32-bit ARM is "armhf" from ARMv7 on and "armel" before, except on
+// Raspbian, whose "armhf" is built for the ARMv6 of the first
+// Raspberry Pi models
+func getDebianArch(goarch string, armVariant string, distroID string) string {
+	switch goarch {
+	case "386":
+		return "i386"
+	case "arm":
+		if armVariant == "v6" && distroID != "raspbian" {
+			return "armel"
+		}
+		return "armhf"
+	default:
+		return goarch
+	}
+}
+
+// isFedoraOSTree checks if a distro of the Fedora family is an
+// ostree variant, by its VARIANT_ID or the marker file of ostree
+func isFedoraOSTree(distro LinuxDistro, variantID string, ostreeBooted bool) bool {
+	if distro != DistroFedora && distro != DistroRHEL && distro != DistroCentOS {
+		return false
+	}
+
+	return ostreeBooted || slices.Contains(fedoraOSTreeVariants, variantID)
+}
+
+// isMicroOS checks if the ID and VARIANT_ID of /etc/os-release,
+// like "opensuse-microos" or "sle-micro", belong to MicroOS
+func isMicroOS(id string, variantID string) bool {
+	return variantID == "microos" ||
+		strings.Contains(id, "microos") ||
+		strings.HasSuffix(id, "-micro")
+}
+
 // isWindowsAdmin checks for administrator privileges on Windows
+//
+// The token API is preferred, "net session" is only used as fallback
 func isWindowsAdmin() bool {
-	// Try to execute a command that requires admin privileges
-	// We use "net session" which fails if not running as admin
-	cmd := exec.Command("cmd", "/C", "net", "session")
-	err := cmd.Run()
-	return err == nil
+	if elevated, err := isWindowsTokenElevated(); err == nil {
+		return elevated
+	}
+
+	return runNetSession(netSessionTimeout) == nil
+}
+
+// runNetSession executes "net session", which requires admin privileges
+// and fails if not running as admin, but cancels it after a timeout,
+// because it can hang on some domain-joined machines
+func runNetSession(timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	err := exec.CommandContext(ctx, "cmd", "/C", "net", "session").Run()
+	if ctx.Err() != nil {
+		return fmt.Errorf("net session did not finish within %s", timeout)
+	}
+
+	return err
+}
+
+// parseCPUInfoARMVariant returns the version of a 32-bit ARM CPU from
+// the content of /proc/cpuinfo, like "v6" or "v7", or an empty string
+//
+// The "model name" is preferred, because the ARMv6 of the first
+// Raspberry Pi models reports 7 as "CPU architecture"
+func parseCPUInfoARMVariant(content string) string {
+	architecture := ""
+
+	for _, line := range strings.Split(content, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "model name", "Processor":
+			if match := armModelNameRegex.FindStringSubmatch(value); match != nil {
+				return "v" + match[1]
+			}
+		case "CPU architecture":
+			if architecture == "" {
+				architecture = value
+			}
+		}
+	}
+
+	if version, err := strconv.Atoi(architecture); err == nil {
+		return fmt.Sprintf("v%d", version)
+	}
+	return ""
 }
 
 func parseOSRelease(path string) (map[string]string, error) {
@@ -0,0 +1,264 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFindPrivilegeEscalationCommand(t *testing.T) {
+	tests := []struct {
+		name      string
+		available []string
+		want      string
+	}{
+		{name: "sudo and doas", available: []string{"sudo", "doas"}, want: "sudo"},
+		{name: "only sudo", available: []string{"sudo"}, want: "sudo"},
+		{name: "only doas", available: []string{"doas"}, want: "doas"},
+		{name: "none", available: nil, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commandExists := func(name string) bool {
+				for _, available := range tt.available {
+					if available == name {
+						return true
+					}
+				}
+				return false
+			}
+
+			if got := findPrivilegeEscalationCommand(commandExists); got != tt.want {
+				t.Errorf("findPrivilegeEscalationCommand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlatformInfoJSON(t *testing.T) {
+	info := &PlatformInfo{
+		OS:             OSLinux,
+		Arch:           "amd64",
+		LinuxDistro:    DistroUbuntu,
+		LinuxDistroID:  "pop",
+		PackageManager: PkgMgrApt,
+		Detected:       true,
+	}
+
+	data, err := json.Marshal(info)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	var values map[string]any
+	if err := json.Unmarshal(data, &values); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]any{
+		"os":               "linux",
+		"arch":             "amd64",
+		"linux_distro":     "ubuntu",
+		"linux_distro_id":  "pop",
+		"linux_variant_id": "",
+		"package_manager":  "apt",
+		"detected":         true,
+	}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("JSON = %s, want keys and values %v", data, want)
+	}
+}
+
+func TestPlatformInfoString(t *testing.T) {
+	tests := []struct {
+		info *PlatformInfo
+		want string
+	}{
+		{
+			info: &PlatformInfo{OS: OSLinux, Arch: "amd64", LinuxDistro: DistroUbuntu, LinuxDistroID: "ubuntu", PackageManager: PkgMgrApt},
+			want: "linux/amd64 (distro: ubuntu, package manager: apt)",
+		},
+		{
+			info: &PlatformInfo{OS: OSLinux, Arch: "arm64", LinuxDistro: DistroUbuntu, LinuxDistroID: "pop", PackageManager: PkgMgrApt},
+			want: "linux/arm64 (distro: ubuntu [pop], package manager: apt)",
+		},
+		{
+			info: &PlatformInfo{OS: OSDarwin, Arch: "arm64", PackageManager: PkgMgrBrew},
+			want: "darwin/arm64 (package manager: brew)",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := tt.info.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestDetectLinuxDistro(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name         string
+		content      *string
+		wantDistro   LinuxDistro
+		wantID       string
+		wantDetected bool
+	}{
+		{name: "missing os-release", content: nil, wantDistro: DistroUnknown, wantDetected: false},
+		{name: "ubuntu", content: stringPtr("NAME=\"Ubuntu\"\nID=ubuntu\nID_LIKE=debian\n"), wantDistro: DistroUbuntu, wantID: "ubuntu", wantDetected: true},
+		{name: "derivative", content: stringPtr("# comment\nID='mydistro'\nID_LIKE=\"rhel fedora\"\n"), wantDistro: DistroFedora, wantID: "mydistro", wantDetected: true},
+		{name: "unknown distro", content: stringPtr("ID=nixos\n"), wantDistro: DistroUnknown, wantID: "nixos", wantDetected: false},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, fmt.Sprintf("os-release-%d", i))
+			if tt.content != nil {
+				if err := os.WriteFile(path, []byte(*tt.content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			info := &PlatformInfo{OS: OSLinux, LinuxDistro: DistroUnknown}
+			info.detectLinuxDistro(path)
+
+			if info.LinuxDistro != tt.wantDistro {
+				t.Errorf("LinuxDistro = %q, want %q", info.LinuxDistro, tt.wantDistro)
+			}
+			if info.LinuxDistroID != tt.wantID {
+				t.Errorf("LinuxDistroID = %q, want %q", info.LinuxDistroID, tt.wantID)
+			}
+			if info.Detected != tt.wantDetected {
+				t.Errorf("Detected = %v, want %v", info.Detected, tt.wantDetected)
+			}
+		})
+	}
+}
+
+func TestParseOSReleaseMissingFile(t *testing.T) {
+	values, err := parseOSRelease(filepath.Join(t.TempDir(), "os-release"))
+	if err == nil {
+		t.Error("parseOSRelease() error = nil, want an error for a missing file")
+	}
+	if values == nil || len(values) != 0 {
+		t.Errorf("parseOSRelease() = %v, want an empty map", values)
+	}
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func TestIsMicroOS(t *testing.T) {
+	tests := []struct {
+		id        string
+		variantID string
+		want      bool
+	}{
+		{id: "opensuse-microos", want: true},
+		{id: "sle-micro", want: true},
+		{id: "opensuse-tumbleweed", variantID: "microos", want: true},
+		{id: "opensuse-tumbleweed", want: false},
+		{id: "opensuse-leap", variantID: "", want: false},
+		{id: "ubuntu", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := isMicroOS(tt.id, tt.variantID); got != tt.want {
+			t.Errorf("isMicroOS(%q, %q) = %v, want %v", tt.id, tt.variantID, got, tt.want)
+		}
+	}
+}
+
+func TestIsFedoraOSTree(t *testing.T) {
+	tests := []struct {
+		name         string
+		distro       LinuxDistro
+		variantID    string
+		ostreeBooted bool
+		want         bool
+	}{
+		{name: "silverblue", distro: DistroFedora, variantID: "silverblue", want: true},
+		{name: "coreos", distro: DistroFedora, variantID: "coreos", want: true},
+		{name: "ostree booted", distro: DistroFedora, ostreeBooted: true, want: true},
+		{name: "rhel ostree", distro: DistroRHEL, ostreeBooted: true, want: true},
+		{name: "workstation", distro: DistroFedora, variantID: "workstation", want: false},
+		{name: "other distro", distro: DistroDebian, variantID: "silverblue", ostreeBooted: true, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isFedoraOSTree(tt.distro, tt.variantID, tt.ostreeBooted); got != tt.want {
+				t.Errorf("isFedoraOSTree(%q, %q, %v) = %v, want %v", tt.distro, tt.variantID, tt.ostreeBooted, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCPUInfoARMVariant(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "raspberry pi zero",
+			content: "processor\t: 0\nmodel name\t: ARMv6-compatible processor rev 7 (v6l)\nBogoMIPS\t: 697.95\nCPU architecture: 7\n",
+			want:    "v6",
+		},
+		{
+			name:    "raspberry pi 3 in 32-bit mode",
+			content: "processor\t: 0\nmodel name\t: ARMv7 Processor rev 4 (v7l)\nCPU architecture: 7\n\nprocessor\t: 1\nmodel name\t: ARMv7 Processor rev 4 (v7l)\n",
+			want:    "v7",
+		},
+		{
+			name:    "old kernel",
+			content: "Processor\t: ARMv7 Processor rev 10 (v7l)\nprocessor\t: 0\n",
+			want:    "v7",
+		},
+		{
+			name:    "architecture only",
+			content: "processor\t: 0\nCPU implementer\t: 0x41\nCPU architecture: 8\n",
+			want:    "v8",
+		},
+		{
+			name:    "x86",
+			content: "processor\t: 0\nmodel name\t: Intel(R) Core(TM) i7-8650U CPU @ 1.90GHz\n",
+			want:    "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseCPUInfoARMVariant(tt.content); got != tt.want {
+				t.Errorf("parseCPUInfoARMVariant() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package utils
+
+import (
+	"fmt"
+	"time"
+)
+
+// PollUntil calls check every interval, until it returns true or
+// timeout is exceeded
+//
+// Errors of check are not fatal, because the checked service may
+// not be ready yet, but the last one is returned on timeout
+func PollUntil(timeout time.Duration, interval time.Duration, check func() (bool, error)) error {
+	deadline := time.Now().Add(timeout)
+
+	var lastErr error
+	for {
+		ok, err := check()
+		if err == nil && ok {
+			return nil
+		}
+		lastErr = err
+
+		if time.Now().Add(interval).After(deadline) {
+			if lastErr != nil {
+				return fmt.Errorf("timeout after %s: %w", timeout, lastErr)
+			}
+			return fmt.Errorf("timeout after %s", timeout)
+		}
+
+		time.Sleep(interval)
+	}
+}
@@ -0,0 +1,109 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+// RedactedValue replaces secrets in logs and output
+const RedactedValue = "********"
+
+// secretNameRegex matches names of flags, environment variables
+// and settings, which contain secrets
+var secretNameRegex = regexp.MustCompile(`(?i)(password|passwd|secret|token|credential|api[_-]?key)`)
+
+// IsSecretName checks if the name of a flag, environment variable
+// or setting indicates, that its value is a secret
+func IsSecretName(name string) bool {
+	// flags like --password-stdin or --password-file do not contain the secret
+	if strings.HasSuffix(name, "-stdin") || strings.HasSuffix(name, "-file") {
+		return false
+	}
+
+	return secretNameRegex.MatchString(name)
+}
+
+// RedactArgs returns a copy of command line arguments, where secrets
+// are masked, which are values of flags like --password, assignments
+// like REGISTRY_PASSWORD=..., also as values of flags, like
+// --registry-env=REGISTRY_HTTP_SECRET=..., and passwords in URLs
+func RedactArgs(args []string) []string {
+	redacted := make([]string, len(args))
+
+	maskNext := false
+	for i, arg := range args {
+		if maskNext {
+			redacted[i] = RedactedValue
+			maskNext = false
+			continue
+		}
+
+		if strings.HasPrefix(arg, "-") {
+			name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+			if IsSecretName(name) {
+				if hasValue {
+					redacted[i] = arg[:strings.Index(arg, "=")+1] + RedactedValue
+				} else {
+					redacted[i] = arg
+					maskNext = true // value is the next argument
+				}
+				continue
+			}
+			if hasValue {
+				redacted[i] = arg[:strings.Index(arg, "=")+1] + RedactValue(value)
+				continue
+			}
+		}
+
+		redacted[i] = RedactValue(arg)
+	}
+
+	return redacted
+}
+
+// RedactCommandLine returns a command line for logs, where
+// secrets of the arguments are masked
+func RedactCommandLine(name string, args ...string) string {
+	return strings.TrimSpace(name + " " + strings.Join(RedactArgs(args), " "))
+}
+
+// RedactValue masks the value of an assignment with a secret name,
+// like "REGISTRY_PASSWORD=...", and the password of an URL
+func RedactValue(value string) string {
+	if key, _, ok := strings.Cut(value, "="); ok && !strings.ContainsAny(key, "/: ") && IsSecretName(key) {
+		return key + "=" + RedactedValue
+	}
+
+	if _, rest, ok := strings.Cut(value, "://"); ok {
+		host, _, _ := strings.Cut(rest, "/")
+		if userInfo, _, hasUserInfo := strings.Cut(host, "@"); hasUserInfo {
+			if user, _, hasPassword := strings.Cut(userInfo, ":"); hasPassword {
+				prefix := value[:len(value)-len(rest)]
+				return prefix + user + ":" + RedactedValue + rest[len(userInfo):]
+			}
+		}
+	}
+
+	return value
+}
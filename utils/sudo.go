@@ -0,0 +1,103 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package utils
+
+import (
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// SudoUser contains the user, who has run autark with sudo
+type SudoUser struct {
+	// UID is the user ID from SUDO_UID
+	UID int
+	// GID is the group ID from SUDO_GID
+	GID int
+}
+
+// DropFileOwnership gives a file or directory, which has been created
+// by autark running as root via sudo, back to the invoking user, so it
+// is not root-owned in the home directory of that user
+//
+// Files outside of the home directory of the invoking user, like in
+// /var/lib/autark, are not changed, as well as files created without sudo
+func DropFileOwnership(path string) error {
+	if os.Getuid() != 0 {
+		return nil
+	}
+
+	sudoUser, ok := GetSudoUser()
+	if !ok {
+		return nil
+	}
+
+	u, err := user.LookupId(strconv.Itoa(sudoUser.UID))
+	if err != nil {
+		return nil
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil || !isPathInside(absPath, u.HomeDir) {
+		return nil
+	}
+
+	return os.Lchown(path, sudoUser.UID, sudoUser.GID)
+}
+
+// GetSudoUser returns the user, who has run autark with sudo,
+// from the SUDO_UID and SUDO_GID environment variables
+func GetSudoUser() (*SudoUser, bool) {
+	return getSudoUser(os.Getenv)
+}
+
+// getSudoUser reads the user of sudo with getenv, where root
+// itself is ignored, because its files are root-owned anyway
+func getSudoUser(getenv func(key string) string) (*SudoUser, bool) {
+	uid, err := strconv.Atoi(strings.TrimSpace(getenv("SUDO_UID")))
+	if err != nil || uid <= 0 {
+		return nil, false
+	}
+
+	gid, err := strconv.Atoi(strings.TrimSpace(getenv("SUDO_GID")))
+	if err != nil || gid < 0 {
+		return nil, false
+	}
+
+	return &SudoUser{UID: uid, GID: gid}, true
+}
+
+// isPathInside checks if path is dir or inside of it
+func isPathInside(path string, dir string) bool {
+	if dir == "" || dir == "/" {
+		return false
+	}
+
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
@@ -0,0 +1,82 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package utils
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestGetSudoUser(t *testing.T) {
+	tests := []struct {
+		name   string
+		env    map[string]string
+		want   *SudoUser
+		wantOK bool
+	}{
+		{name: "user", env: map[string]string{"SUDO_UID": "1000", "SUDO_GID": " 1000 "}, want: &SudoUser{UID: 1000, GID: 1000}, wantOK: true},
+		{name: "group root", env: map[string]string{"SUDO_UID": "1000", "SUDO_GID": "0"}, want: &SudoUser{UID: 1000, GID: 0}, wantOK: true},
+		{name: "root", env: map[string]string{"SUDO_UID": "0", "SUDO_GID": "0"}},
+		{name: "without sudo", env: map[string]string{}},
+		{name: "invalid UID", env: map[string]string{"SUDO_UID": "abc", "SUDO_GID": "1000"}},
+		{name: "missing GID", env: map[string]string{"SUDO_UID": "1000"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := getSudoUser(func(key string) string {
+				return tt.env[key]
+			})
+			if ok != tt.wantOK {
+				t.Fatalf("getSudoUser() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && *got != *tt.want {
+				t.Errorf("getSudoUser() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsPathInside(t *testing.T) {
+	home := filepath.Join(t.TempDir(), "home", "alice")
+
+	tests := []struct {
+		path string
+		dir  string
+		want bool
+	}{
+		{path: home, dir: home, want: true},
+		{path: filepath.Join(home, ".config", "autark"), dir: home, want: true},
+		{path: filepath.Join(home, "..Hidden"), dir: home, want: true},
+		{path: filepath.Join(home, "..", "bob"), dir: home, want: false},
+		{path: home + "2", dir: home, want: false},
+		{path: filepath.Dir(home), dir: home, want: false},
+		{path: home, dir: "", want: false},
+		{path: "/var/lib/autark", dir: "/", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := isPathInside(tt.path, tt.dir); got != tt.want {
+			t.Errorf("isPathInside(%q, %q) = %v, want %v", tt.path, tt.dir, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+// The MIT License (MIT)
+// Copyright (c) 2026 Marcel Joachim Kloubert <https://marcel.coffee>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of
+// this software and associated documentation files (the "Software"), to deal in
+// the Software without restriction, including without limitation the rights to
+// use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+// of the Software, and to permit persons to whom the Software is furnished to do
+// so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING
+// FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package utils
+
+import "testing"
+
+func TestParseSemver(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "git version 2.43.0", want: "2.43.0"},
+		{input: "git version 2.39.3 (Apple Git-146)", want: "2.39.3"},
+		{input: "git version 2.45.1.windows.1", want: "2.45.1"},
+		{input: "Docker version 24.0.7, build afdd53b", want: "24.0.7"},
+		{input: "Docker version 27.3.1-rd, build 41ca978", want: "27.3.1"},
+		{input: "Docker Compose version v2.29.7", want: "2.29.7"},
+		{input: "version 2", want: ""},
+		{input: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := ParseSemver(tt.input); got != tt.want {
+			t.Errorf("ParseSemver(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}